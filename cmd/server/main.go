@@ -7,6 +7,7 @@ import (
 
 	"github.com/minilik/ecommerce/config"
 	di_container "github.com/minilik/ecommerce/internal/infrastructure/container"
+	"github.com/minilik/ecommerce/internal/infrastructure/server"
 
 	// Import handlers and router for Swagger doc generation
 	// These imports ensure Swaggo can scan the handler files for annotations
@@ -45,10 +46,11 @@ func main() {
 		}
 	}()
 
-	addr := fmt.Sprintf(":%d", cfg.Server.Port)
-	app.Logger.Info("starting HTTP server", zap.String("address", addr))
-
-	if err := app.Router.Run(addr); err != nil {
+	srv, err := server.New(cfg.Server, app.Router, app.Logger)
+	if err != nil {
+		app.Logger.Fatal("invalid server config", zap.Error(err))
+	}
+	if err := srv.ListenAndServe(); err != nil {
 		app.Logger.Fatal("server exited with error", zap.Error(err))
 	}
 }
@@ -11,22 +11,76 @@ import (
 // Config: holds the application configuration values.
 type Config struct {
 	App      AppConfig      `mapstructure:"app"`
+	Log      LogConfig      `mapstructure:"log"`
 	Server   ServerConfig   `mapstructure:"server"`
+	Cors     CorsConfig     `mapstructure:"cors"`
 	Database DatabaseConfig `mapstructure:"database"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
+	Auth     AuthConfig     `mapstructure:"auth"`
 	Cloud    Cloudinary     `mapstructure:"cloudinary"`
 	Rate     RateLimit      `mapstructure:"rate_limit"`
 	Cache    CacheConfig    `mapstructure:"cache"`
 	Admin    AdminSeed      `mapstructure:"admin_seed"`
+	Product  ProductConfig  `mapstructure:"product"`
+	Order    OrderConfig    `mapstructure:"order"`
 }
 
 type AppConfig struct {
 	Name        string `mapstructure:"name"`
 	Environment string `mapstructure:"environment"`
+	// ReadOnly rejects every write except login with 503 for maintenance windows. It can also be
+	// switched on with the READ_ONLY environment variable.
+	ReadOnly bool `mapstructure:"read_only"`
+	// SeedSampleData inserts a demo user and a small catalog at startup, outside production only.
+	// It can also be switched on with the SEED_SAMPLE_DATA environment variable.
+	SeedSampleData bool `mapstructure:"seed_sample_data"`
 }
 
+// LogConfig overrides the environment-based logger defaults when set.
+type LogConfig struct {
+	Level    string      `mapstructure:"level"`  // debug, info, warn, error
+	Format   string      `mapstructure:"format"` // json or console
+	Sampling LogSampling `mapstructure:"sampling"`
+	// DebugRoutes enables redacted request/response body logging for the listed route groups.
+	DebugRoutes []string `mapstructure:"debug_routes"`
+	// SlowRequestThreshold logs a warning for requests slower than this; zero disables it.
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
+}
+
+// LogSampling controls production log sampling per second; error logs are never sampled.
+type LogSampling struct {
+	Initial    int `mapstructure:"initial"`
+	Thereafter int `mapstructure:"thereafter"`
+}
+
+// ServerConfig holds listener settings. RequestTimeout is the deadline put on each request's
+// context and UploadTimeout replaces it on the image upload routes; zero means no deadline.
 type ServerConfig struct {
-	Port int `mapstructure:"port"`
+	Port           int           `mapstructure:"port"`
+	TLS            TLSConfig     `mapstructure:"tls"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	UploadTimeout  time.Duration `mapstructure:"upload_timeout"`
+	// HealthCritical lists the dependencies (db, cache, uploader) that fail /health/ready with 503
+	// when down; the others only show up in the body. HealthTimeout bounds each check.
+	HealthCritical []string      `mapstructure:"health_critical"`
+	HealthTimeout  time.Duration `mapstructure:"health_timeout"`
+	// TimestampFormat is how times are written in JSON responses: rfc3339 or epoch_millis.
+	// TimeZone, an IANA name such as Europe/Berlin, is the zone rfc3339 times are shown in.
+	TimestampFormat string `mapstructure:"timestamp_format"`
+	TimeZone        string `mapstructure:"time_zone"`
+}
+
+// TLSConfig enables HTTPS on server.port when both files are set. RedirectHTTPPort, when
+// non-zero, also listens for plain HTTP on that port and redirects it to HTTPS.
+type TLSConfig struct {
+	CertFile         string `mapstructure:"cert_file"`
+	KeyFile          string `mapstructure:"key_file"`
+	RedirectHTTPPort int    `mapstructure:"redirect_http_port"`
+}
+
+// CorsConfig holds cross-origin settings. MaxAge is how long browsers may cache preflight responses.
+type CorsConfig struct {
+	MaxAge time.Duration `mapstructure:"max_age"`
 }
 
 type DatabaseConfig struct {
@@ -36,13 +90,61 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	Name     string `mapstructure:"name"`
 	SSLMode  string `mapstructure:"sslmode"`
+	// StatementTimeout is enforced by Postgres on every session; 0 leaves the server default.
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	// QueryTimeout bounds repository calls whose context carries no deadline; 0 disables it.
+	QueryTimeout time.Duration `mapstructure:"query_timeout"`
+	// ConnMaxIdleTime closes pooled connections idle for longer; keep it below any proxy's idle
+	// timeout. 0 keeps idle connections until ConnMaxLifetime.
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
 }
 
 type JWTConfig struct {
-	Secret          string        `mapstructure:"secret"`
-	Issuer          string        `mapstructure:"issuer"`
+	Secret string `mapstructure:"secret"`
+	Issuer string `mapstructure:"issuer"`
+	// Audience is set as the aud claim and required on incoming tokens; empty disables both.
+	Audience        string        `mapstructure:"audience"`
 	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	// Delivery controls how Login hands out the access token: header (JSON body), cookie, or both.
+	Delivery     string `mapstructure:"delivery"`
+	CookieName   string `mapstructure:"cookie_name"`
+	CookieSecure bool   `mapstructure:"cookie_secure"`
+	// TokenHeader and TokenScheme say where requests carry the access token, e.g. "X-Access-Token"
+	// with an empty scheme for a bare token. Authorization: Bearer keeps working alongside a custom header.
+	TokenHeader string `mapstructure:"token_header"`
+	TokenScheme string `mapstructure:"token_scheme"`
+}
+
+// AuthConfig holds account validation rules.
+// Username lengths are counted in characters; a zero bound is not enforced.
+// UsernameUnicode accepts any Unicode letter or digit instead of ASCII alphanumerics only.
+// BlockedEmailDomains (plus one domain per line from BlockedEmailDomainsFile) rejects disposable
+// email providers at registration and email change; both empty disables the check.
+type AuthConfig struct {
+	UsernameMinLength       int            `mapstructure:"username_min_length"`
+	UsernameMaxLength       int            `mapstructure:"username_max_length"`
+	UsernameUnicode         bool           `mapstructure:"username_unicode"`
+	BlockedEmailDomains     []string       `mapstructure:"blocked_email_domains"`
+	BlockedEmailDomainsFile string         `mapstructure:"blocked_email_domains_file"`
+	PasswordPolicy          PasswordPolicy `mapstructure:"password_policy"`
+	// EmailVerificationTTL is how long an email verification link stays valid.
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	// PasswordResetTTL is how long a password reset link stays valid.
+	PasswordResetTTL time.Duration `mapstructure:"password_reset_ttl"`
+	// RegistrationEnabled allows public self-registration; when false only admins create accounts.
+	RegistrationEnabled bool `mapstructure:"registration_enabled"`
+}
+
+// PasswordPolicy is the set of rules a new password must satisfy. Lengths are in characters;
+// MaxLength 0 means no upper bound.
+type PasswordPolicy struct {
+	MinLength      int  `mapstructure:"min_length"`
+	MaxLength      int  `mapstructure:"max_length"`
+	RequireLower   bool `mapstructure:"require_lower"`
+	RequireUpper   bool `mapstructure:"require_upper"`
+	RequireDigit   bool `mapstructure:"require_digit"`
+	RequireSpecial bool `mapstructure:"require_special"`
 }
 
 type Cloudinary struct {
@@ -51,18 +153,105 @@ type Cloudinary struct {
 	APISecret    string `mapstructure:"api_secret"`
 	UploadPreset string `mapstructure:"upload_preset"` // prefer unsigned uploads via preset
 	Folder       string `mapstructure:"folder"`
+	// UploadConcurrency caps how many files of one request are uploaded at the same time (min 1).
+	UploadConcurrency int `mapstructure:"upload_concurrency"`
+	// FolderPerEnvironment and FolderPerCategory append app.environment and the product's category
+	// to Folder, e.g. "ecommerce/production/shoes".
+	FolderPerEnvironment bool `mapstructure:"folder_per_environment"`
+	FolderPerCategory    bool `mapstructure:"folder_per_category"`
+	// SignedURLTTL is how long signed image delivery URLs stay valid.
+	SignedURLTTL time.Duration `mapstructure:"signed_url_ttl"`
+	// CDNBase, when set, replaces the scheme and host of stored image URLs in API responses
+	// (keeping the path), so switching CDNs needs no data migration.
+	CDNBase string `mapstructure:"cdn_base"`
+	// After BreakerThreshold consecutive failed uploads (network errors, 5xx, 429), uploads fail at
+	// once with 503 for BreakerCooldown, after which one upload probes whether Cloudinary is back.
+	// A threshold of 0 disables the breaker.
+	BreakerThreshold int           `mapstructure:"breaker_threshold"`
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown"`
 }
 
 type RateLimit struct {
 	Enabled bool          `mapstructure:"enabled"`
 	Limit   int           `mapstructure:"limit"`
 	Window  time.Duration `mapstructure:"window"`
+	// MaxConcurrent caps the requests one IP may have in flight, independently of Enabled; 0 disables it.
+	MaxConcurrent int `mapstructure:"max_concurrent"`
 }
 
+// CacheConfig controls the in-memory product cache. A warning is logged when it fills to
+// SoftLimitRatio of MaxProductEntries; zero disables the warning.
 type CacheConfig struct {
 	Enabled           bool          `mapstructure:"enabled"`
 	ProductListTTL    time.Duration `mapstructure:"product_list_ttl"`
 	MaxProductEntries int           `mapstructure:"max_product_entries"`
+	SoftLimitRatio    float64       `mapstructure:"soft_limit_ratio"`
+}
+
+// ProductConfig holds business bounds for product values. A zero maximum means unlimited.
+// ImageExtensions is the allowlist of image file extensions accepted on upload.
+type ProductConfig struct {
+	MaxPrice             float64  `mapstructure:"max_price"`
+	MaxStock             int      `mapstructure:"max_stock"`
+	MaxDescriptionLength int      `mapstructure:"max_description_length"` // in characters (runes)
+	ImageExtensions      []string `mapstructure:"image_extensions"`
+	// ExportBatchSize is how many products the catalog export reads per query.
+	ExportBatchSize int `mapstructure:"export_batch_size"`
+	// DefaultSort orders product listings that name neither a sort nor a view with one.
+	DefaultSort string `mapstructure:"default_sort"`
+	// AllowedCategories restricts product categories to this list (matched case-insensitively)
+	// while the category repository holds no categories; empty keeps categories free text.
+	AllowedCategories []string `mapstructure:"allowed_categories"`
+	// ImageCacheMaxAge is the Cache-Control max-age of GET /products/{id}/images.
+	ImageCacheMaxAge time.Duration `mapstructure:"image_cache_max_age"`
+	// SuggestLimit caps typeahead results; SuggestCacheMaxAge is their Cache-Control max-age.
+	SuggestLimit       int           `mapstructure:"suggest_limit"`
+	SuggestCacheMaxAge time.Duration `mapstructure:"suggest_cache_max_age"`
+	// ListTotals counts matching products for list responses when the request does not pass count.
+	ListTotals bool `mapstructure:"list_totals"`
+	// DocumentExtensions is the allowlist of document (spec sheet, manual) file extensions.
+	DocumentExtensions []string `mapstructure:"document_extensions"`
+	// MaxUploadBytes caps the combined size of the files of one upload request; 0 disables the cap.
+	MaxUploadBytes int64 `mapstructure:"max_upload_bytes"`
+	// CategoryCacheMaxAge is the Cache-Control max-age of GET /products/categories.
+	CategoryCacheMaxAge time.Duration `mapstructure:"category_cache_max_age"`
+	// DedupUploads hashes uploaded images and returns the stored image instead of uploading a
+	// file whose bytes the product already has.
+	DedupUploads bool `mapstructure:"dedup_uploads"`
+}
+
+// OrderConfig holds order processing rules.
+// AllowBackorder fulfills available stock and backorders the shortfall instead of rejecting the order.
+// PendingTTL is the default age after which pending orders are expired; ExpireBatchSize bounds each expiry transaction.
+// MaxDescriptionLength caps the order description in characters; zero disables the cap.
+type OrderConfig struct {
+	AllowBackorder       bool          `mapstructure:"allow_backorder"`
+	PendingTTL           time.Duration `mapstructure:"pending_ttl"`
+	ExpireBatchSize      int           `mapstructure:"expire_batch_size"`
+	MaxDescriptionLength int           `mapstructure:"max_description_length"`
+	// CreateTimeout is the budget for the whole order-creation transaction, which spans several
+	// statements and so gets more than database.query_timeout; 0 disables it.
+	CreateTimeout time.Duration `mapstructure:"create_timeout"`
+	// MaxTotal is the order total above which an order is not auto-accepted; 0 disables the guard.
+	// MaxTotalAction is "review" to hold such orders as review_required or "reject" to refuse them.
+	MaxTotal       float64 `mapstructure:"max_total"`
+	MaxTotalAction string  `mapstructure:"max_total_action"`
+	// RequireVerifiedEmail refuses orders from users who have not verified their email.
+	RequireVerifiedEmail bool `mapstructure:"require_verified_email"`
+	// MaxItems caps the line items (and referenced reservations) of one order; 0 disables the cap.
+	MaxItems int `mapstructure:"max_items"`
+	// MaxOpenOrders caps how many pending orders a user may hold at once; 0 means unlimited.
+	MaxOpenOrders int `mapstructure:"max_open_orders"`
+	// ForbidSelfPurchase rejects line items for products the ordering user listed (marketplaces).
+	ForbidSelfPurchase bool `mapstructure:"forbid_self_purchase"`
+	// ReservationTTL is how long reserved stock is held when the caller does not ask for a window;
+	// ReservationMaxTTL caps requested windows. ReservationSweepInterval is how often expired
+	// reservations are released back to stock; 0 disables the background sweep.
+	ReservationTTL           time.Duration `mapstructure:"reservation_ttl"`
+	ReservationMaxTTL        time.Duration `mapstructure:"reservation_max_ttl"`
+	ReservationSweepInterval time.Duration `mapstructure:"reservation_sweep_interval"`
+	// NumberPrefix starts every order number, as in ORD-2024-000123. It is upper-cased when used.
+	NumberPrefix string `mapstructure:"number_prefix"`
 }
 
 // AdminSeed holds initial admin user seeding configuration.
@@ -71,6 +260,42 @@ type AdminSeed struct {
 	Email    string `mapstructure:"email"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
+	// SyncPassword re-hashes the configured password onto an existing admin at startup.
+	SyncPassword bool `mapstructure:"sync_password"`
+	// Users lists additional admins seeded alongside the primary one.
+	Users []AdminUser `mapstructure:"users"`
+}
+
+// AdminUser is a single admin account to seed.
+type AdminUser struct {
+	Email    string `mapstructure:"email"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// Accounts returns the primary admin followed by the listed users, each carrying the shared
+// Enabled and SyncPassword settings. Entries without an email or password are dropped.
+func (a AdminSeed) Accounts() []AdminSeed {
+	primary := a
+	primary.Users = nil
+	candidates := []AdminSeed{primary}
+	for _, u := range a.Users {
+		candidates = append(candidates, AdminSeed{
+			Enabled:      a.Enabled,
+			Email:        u.Email,
+			Username:     u.Username,
+			Password:     u.Password,
+			SyncPassword: a.SyncPassword,
+		})
+	}
+
+	accounts := make([]AdminSeed, 0, len(candidates))
+	for _, acc := range candidates {
+		if acc.Email != "" && acc.Password != "" {
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts
 }
 
 // Load loads the configuration from the provided path (directory). It falls back to the current working directory.
@@ -107,8 +332,27 @@ func Load(path string) (*Config, error) {
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("app.name", "ecommerce-api")
 	v.SetDefault("app.environment", "development")
+	v.SetDefault("app.read_only", false)
+	_ = v.BindEnv("app.read_only", "APP_READ_ONLY", "READ_ONLY")
+	v.SetDefault("app.seed_sample_data", false)
+	_ = v.BindEnv("app.seed_sample_data", "APP_SEED_SAMPLE_DATA", "SEED_SAMPLE_DATA")
+
+	v.SetDefault("log.sampling.initial", 100)
+	v.SetDefault("log.sampling.thereafter", 100)
+	v.SetDefault("log.slow_request_threshold", "2s")
 
 	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.tls.cert_file", "")
+	v.SetDefault("server.tls.key_file", "")
+	v.SetDefault("server.tls.redirect_http_port", 0)
+	v.SetDefault("server.request_timeout", 0)
+	v.SetDefault("server.upload_timeout", "2m")
+	v.SetDefault("server.health_critical", []string{"db"})
+	v.SetDefault("server.health_timeout", "2s")
+	v.SetDefault("server.timestamp_format", "rfc3339")
+	v.SetDefault("server.time_zone", "UTC")
+
+	v.SetDefault("cors.max_age", time.Hour*12)
 
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", 5432)
@@ -116,23 +360,86 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.password", "postgres")
 	v.SetDefault("database.name", "ecommerce")
 	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.statement_timeout", "30s")
+	v.SetDefault("database.query_timeout", "5s")
+	v.SetDefault("database.conn_max_idle_time", "1m")
 
 	v.SetDefault("jwt.secret", "change-this-secret")
 	v.SetDefault("jwt.issuer", "ecommerce-api")
+	v.SetDefault("jwt.audience", "ecommerce-api")
 	v.SetDefault("jwt.access_token_ttl", time.Minute*30)
 	v.SetDefault("jwt.refresh_token_ttl", time.Hour*24*7)
+	v.SetDefault("jwt.delivery", "header")
+	v.SetDefault("jwt.cookie_name", "access_token")
+	v.SetDefault("jwt.cookie_secure", true)
+	v.SetDefault("jwt.token_header", "Authorization")
+	v.SetDefault("jwt.token_scheme", "Bearer")
+	v.SetDefault("auth.username_min_length", 3)
+	v.SetDefault("auth.username_max_length", 32)
+	v.SetDefault("auth.username_unicode", false)
+	v.SetDefault("auth.password_policy.min_length", 8)
+	v.SetDefault("auth.password_policy.max_length", 0)
+	v.SetDefault("auth.password_policy.require_lower", true)
+	v.SetDefault("auth.password_policy.require_upper", true)
+	v.SetDefault("auth.password_policy.require_digit", true)
+	v.SetDefault("auth.password_policy.require_special", true)
+	v.SetDefault("auth.email_verification_ttl", "24h")
+	v.SetDefault("auth.password_reset_ttl", "1h")
+	v.SetDefault("auth.registration_enabled", true)
 
 	v.SetDefault("cloudinary.folder", "ecommerce")
+	v.SetDefault("cloudinary.upload_concurrency", 2)
+	v.SetDefault("cloudinary.folder_per_environment", false)
+	v.SetDefault("cloudinary.folder_per_category", false)
+	v.SetDefault("cloudinary.cdn_base", "")
+	v.SetDefault("cloudinary.signed_url_ttl", "1h")
+	v.SetDefault("cloudinary.breaker_threshold", 5)
+	v.SetDefault("cloudinary.breaker_cooldown", "30s")
 
 	v.SetDefault("rate_limit.enabled", true)
 	v.SetDefault("rate_limit.limit", 100)
 	v.SetDefault("rate_limit.window", time.Minute)
+	v.SetDefault("rate_limit.max_concurrent", 20)
 
 	v.SetDefault("cache.enabled", true)
 	v.SetDefault("cache.product_list_ttl", time.Minute*1)
 	v.SetDefault("cache.max_product_entries", 1000)
+	v.SetDefault("cache.soft_limit_ratio", 0.8)
 
 	v.SetDefault("admin_seed.enabled", false)
+	v.SetDefault("admin_seed.sync_password", false)
+
+	v.SetDefault("product.max_price", 0)
+	v.SetDefault("product.max_stock", 0)
+	v.SetDefault("product.max_description_length", 5000)
+	v.SetDefault("product.image_extensions", []string{"jpg", "jpeg", "png", "webp"})
+	v.SetDefault("product.export_batch_size", 500)
+	v.SetDefault("product.default_sort", "newest")
+	v.SetDefault("product.allowed_categories", []string{})
+	v.SetDefault("product.image_cache_max_age", "5m")
+	v.SetDefault("product.suggest_limit", 10)
+	v.SetDefault("product.suggest_cache_max_age", "30s")
+	v.SetDefault("product.list_totals", true)
+	v.SetDefault("product.max_upload_bytes", 20<<20)
+	v.SetDefault("product.category_cache_max_age", "1m")
+	v.SetDefault("product.dedup_uploads", false)
+	v.SetDefault("product.document_extensions", []string{"pdf", "txt", "csv", "docx", "xlsx"})
+
+	v.SetDefault("order.allow_backorder", false)
+	v.SetDefault("order.pending_ttl", "24h")
+	v.SetDefault("order.expire_batch_size", 100)
+	v.SetDefault("order.max_description_length", 500)
+	v.SetDefault("order.create_timeout", "10s")
+	v.SetDefault("order.max_total", 0)
+	v.SetDefault("order.max_total_action", "review")
+	v.SetDefault("order.require_verified_email", false)
+	v.SetDefault("order.max_items", 100)
+	v.SetDefault("order.max_open_orders", 0)
+	v.SetDefault("order.forbid_self_purchase", false)
+	v.SetDefault("order.reservation_ttl", "15m")
+	v.SetDefault("order.reservation_max_ttl", "1h")
+	v.SetDefault("order.reservation_sweep_interval", "1m")
+	v.SetDefault("order.number_prefix", "ORD")
 }
 
 func applyFallbacks(cfg *Config) {
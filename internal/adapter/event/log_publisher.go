@@ -0,0 +1,54 @@
+package event
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+type logPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher returns an EventPublisher that records events in the application log.
+// It is the default until a message broker or notifier is wired in.
+func NewLogPublisher(logger *zap.Logger) domain.EventPublisher {
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event domain.Event) error {
+	fields := []zap.Field{zap.String("event", event.Name())}
+	switch e := event.(type) {
+	case domain.OutOfStockEvent:
+		fields = append(fields,
+			zap.String("product_id", e.ProductID.String()),
+			zap.String("product_name", e.ProductName),
+			zap.String("order_id", e.OrderID.String()),
+			zap.Time("occurred_at", e.OccurredAt),
+		)
+	case domain.OrderReviewRequiredEvent:
+		fields = append(fields,
+			zap.String("order_id", e.OrderID.String()),
+			zap.String("user_id", e.UserID.String()),
+			zap.Float64("total", e.Total),
+			zap.Float64("max_total", e.MaxTotal),
+			zap.Time("occurred_at", e.OccurredAt),
+		)
+	case domain.EmailVerificationRequestedEvent:
+		fields = append(fields,
+			zap.String("user_id", e.UserID.String()),
+			zap.String("email", e.Email),
+			zap.Time("expires_at", e.ExpiresAt),
+		)
+	case domain.PasswordResetRequestedEvent:
+		fields = append(fields,
+			zap.String("user_id", e.UserID.String()),
+			zap.String("email", e.Email),
+			zap.Time("expires_at", e.ExpiresAt),
+		)
+	}
+	p.logger.Info("domain event", fields...)
+	return nil
+}
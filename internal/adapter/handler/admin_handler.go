@@ -7,20 +7,163 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
-	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
 	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	"github.com/minilik/ecommerce/pkg/cache"
+	"github.com/minilik/ecommerce/pkg/metrics"
 	"github.com/minilik/ecommerce/pkg/response"
 )
 
 type AdminHandler struct {
-	auth   authusecase.Service
-	logger *zap.Logger
+	auth    authusecase.Service
+	uploads *metrics.Histogram
+	cache   cache.Cache
+	logger  *zap.Logger
 }
 
 func NewAdminHandler(auth authusecase.Service, logger *zap.Logger) *AdminHandler {
 	return &AdminHandler{auth: auth, logger: logger}
 }
 
+// WithUploadMetrics exposes the upload size histogram through UploadMetrics.
+func (h *AdminHandler) WithUploadMetrics(uploads *metrics.Histogram) *AdminHandler {
+	h.uploads = uploads
+	return h
+}
+
+// WithCache lets FlushCache clear the product cache and CacheStats report on it when it implements
+// cache.StatsReporter; nil means caching is disabled.
+func (h *AdminHandler) WithCache(c cache.Cache) *AdminHandler {
+	h.cache = c
+	return h
+}
+
+// FlushCache drops every cached entry so reads go back to the database (admin-only).
+func (h *AdminHandler) FlushCache(c *gin.Context) {
+	// @Summary Flush caches
+	// @Description Clear all cached product data, e.g. after a direct database edit (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/cache/flush [post]
+	claims, _ := middleware.GetUserClaims(c)
+	entries := 0
+	if h.cache != nil {
+		flushed, err := h.cache.Flush(c.Request.Context())
+		if err != nil {
+			h.logger.Error("cache flush failed", zap.Error(err))
+			respondError(c, err, "failed to flush cache")
+			return
+		}
+		entries = flushed
+	}
+	h.logger.Info("cache flushed",
+		zap.String("admin_id", claims.UserID.String()),
+		zap.String("admin", claims.Username),
+		zap.Int("entries", entries),
+	)
+	c.JSON(http.StatusOK, response.SuccessBase("cache flushed", gin.H{"entries": entries}))
+}
+
+// CacheStats reports product cache usage so max_product_entries can be tuned (admin-only).
+func (h *AdminHandler) CacheStats(c *gin.Context) {
+	// @Summary Cache stats
+	// @Description Product cache size, capacity and hit, miss and rejected-insert counters (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/cache/stats [get]
+	if h.cache == nil {
+		c.JSON(http.StatusOK, response.SuccessBase("cache stats", gin.H{"enabled": false}))
+		return
+	}
+	reporter, ok := h.cache.(cache.StatsReporter)
+	if !ok {
+		c.JSON(http.StatusOK, response.SuccessBase("cache stats", gin.H{"enabled": true}))
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("cache stats", struct {
+		Enabled bool `json:"enabled"`
+		cache.Stats
+	}{true, reporter.Stats()}))
+}
+
+// UploadMetrics returns the per-route upload request size histogram (admin-only).
+func (h *AdminHandler) UploadMetrics(c *gin.Context) {
+	// @Summary Upload size metrics
+	// @Description Request body sizes observed on upload routes, bucketed per route (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/metrics/uploads [get]
+	samples := map[string]metrics.Sample{}
+	if h.uploads != nil {
+		samples = h.uploads.Snapshot()
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("upload metrics", samples))
+}
+
+// CreateUser creates an account on an admin's behalf, the only way in when registration is closed (admin-only).
+func (h *AdminHandler) CreateUser(c *gin.Context) {
+	// @Summary Create user
+	// @Description Create an account with a chosen role (default user), also while self-registration is disabled (admin only)
+	// @Tags Admin
+	// @Accept json
+	// @Produce json
+	// @Param payload body authusecase.CreateUserInput true "New user"
+	// @Success 201 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/users [post]
+	var input authusecase.CreateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	user, err := h.auth.CreateUser(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Warn("create user failed", zap.Error(err))
+		respondError(c, err, "failed to create user")
+		return
+	}
+	claims, _ := middleware.GetUserClaims(c)
+	h.logger.Info("user created by admin",
+		zap.String("admin_id", claims.UserID.String()),
+		zap.String("user_id", user.UserID.String()),
+		zap.String("role", user.Role),
+	)
+	c.JSON(http.StatusCreated, response.SuccessBase("user created", toUserResponse(user)))
+}
+
+// GetUser returns a single user's details without the password (admin-only).
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	// @Summary Get user
+	// @Description Get a user's details by id (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Param id path string true "User ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/users/{id} [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid user id", []string{err.Error()}))
+		return
+	}
+	user, err := h.auth.GetUser(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Warn("get user failed", zap.Error(err))
+		respondError(c, err, "failed to fetch user")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("user retrieved", toUserResponse(user)))
+}
+
 // PromoteUserToAdmin promotes a user to admin (admin-only).
 func (h *AdminHandler) PromoteUserToAdmin(c *gin.Context) {
 	// @Summary Promote user to admin
@@ -40,14 +183,75 @@ func (h *AdminHandler) PromoteUserToAdmin(c *gin.Context) {
 	}
 	h.logger.Info("Admin promotion", zap.String("admin", id.String()))
 	if err := h.auth.PromoteToAdmin(c.Request.Context(), id); err != nil {
-		if err == domain.ErrUserNotFound {
-			// h.logger.Info("Admin promotion", zap.String("admin", id.String()))
-			c.JSON(http.StatusNotFound, response.ErrorBase("user not found", []string{err.Error()}))
-			return
-		}
 		h.logger.Warn("promote user failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("failed to promote user", []string{err.Error()}))
+		respondError(c, err, "failed to promote user")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessEmpty("user promoted to admin"))
+}
+
+// SetUserRole gives a user any supported role (admin-only).
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	// @Summary Set user role
+	// @Description Give a user one of the supported roles (admin, user). Demoting the last admin is refused with 409 (admin only)
+	// @Tags Admin
+	// @Accept json
+	// @Produce json
+	// @Param id path string true "User ID"
+	// @Param payload body authusecase.SetRoleInput true "Role"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/users/{id}/role [put]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid user id", []string{err.Error()}))
+		return
+	}
+	var input authusecase.SetRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	user, err := h.auth.SetRole(c.Request.Context(), id, input.Role)
+	if err != nil {
+		h.logger.Warn("set user role failed", zap.Error(err))
+		respondError(c, err, "failed to set user role")
+		return
+	}
+	claims, _ := middleware.GetUserClaims(c)
+	h.logger.Info("user role set",
+		zap.String("admin_id", claims.UserID.String()),
+		zap.String("user_id", id.String()),
+		zap.String("role", user.Role),
+	)
+	c.JSON(http.StatusOK, response.SuccessBase("user role updated", toUserResponse(user)))
+}
+
+// PromoteUserToAdminByEmail promotes a user identified by email to admin (admin-only).
+func (h *AdminHandler) PromoteUserToAdminByEmail(c *gin.Context) {
+	// @Summary Promote user to admin by email
+	// @Description Promote a user to admin role by email lookup (admin only)
+	// @Tags Admin
+	// @Accept json
+	// @Produce json
+	// @Param payload body authusecase.PromoteByEmailInput true "User email"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/users/promote [post]
+	var input authusecase.PromoteByEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	if err := h.auth.PromoteToAdminByEmail(c.Request.Context(), input.Email); err != nil {
+		h.logger.Warn("promote user by email failed", zap.Error(err))
+		respondError(c, err, "failed to promote user")
 		return
 	}
-	c.JSON(http.StatusOK, response.SuccessBase("user promoted to admin", nil))
+	c.JSON(http.StatusOK, response.SuccessEmpty("user promoted to admin"))
 }
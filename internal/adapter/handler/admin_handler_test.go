@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,7 +15,10 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
+	"github.com/minilik/ecommerce/internal/domain"
 	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	"github.com/minilik/ecommerce/pkg/cache"
 )
 
 type mockAuthServiceForAdmin struct {
@@ -23,6 +29,14 @@ func (m *mockAuthServiceForAdmin) Register(ctx context.Context, input authusecas
 	return nil, nil
 }
 
+func (m *mockAuthServiceForAdmin) CreateUser(ctx context.Context, input authusecase.CreateUserInput) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
 func (m *mockAuthServiceForAdmin) Login(ctx context.Context, input authusecase.LoginInput) (*authusecase.AuthResponse, error) {
 	return nil, nil
 }
@@ -32,6 +46,51 @@ func (m *mockAuthServiceForAdmin) PromoteToAdmin(ctx context.Context, userID uui
 	return args.Error(0)
 }
 
+func (m *mockAuthServiceForAdmin) PromoteToAdminByEmail(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *mockAuthServiceForAdmin) UpdateEmail(ctx context.Context, userID uuid.UUID, input authusecase.UpdateEmailInput) (*authusecase.UpdateEmailResponse, error) {
+	return nil, nil
+}
+
+func (m *mockAuthServiceForAdmin) GetUser(ctx context.Context, userID uuid.UUID) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
+func (m *mockAuthServiceForAdmin) SendEmailVerification(ctx context.Context, userID uuid.UUID) (*authusecase.EmailVerificationResponse, error) {
+	return nil, nil
+}
+
+func (m *mockAuthServiceForAdmin) VerifyEmail(ctx context.Context, token string) (*authusecase.VerifyEmailResponse, error) {
+	return nil, nil
+}
+
+func (m *mockAuthServiceForAdmin) ForgotPassword(ctx context.Context, input authusecase.ForgotPasswordInput) error {
+	return nil
+}
+
+func (m *mockAuthServiceForAdmin) SetRole(ctx context.Context, userID uuid.UUID, role string) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
+func (m *mockAuthServiceForAdmin) ResetPassword(ctx context.Context, input authusecase.ResetPasswordInput) error {
+	return nil
+}
+
+func (m *mockAuthServiceForAdmin) TokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	return false, nil
+}
+
 func TestAdminHandler_PromoteUserToAdmin(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
@@ -56,3 +115,273 @@ func TestAdminHandler_PromoteUserToAdmin(t *testing.T) {
 	})
 }
 
+func TestAdminHandler_PromoteUserToAdminByEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		handler := NewAdminHandler(mockSvc, logger)
+
+		mockSvc.On("PromoteToAdminByEmail", mock.Anything, "user@example.com").Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/promote", bytes.NewBufferString(`{"email":"user@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		handler.PromoteUserToAdminByEmail(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		handler := NewAdminHandler(mockSvc, logger)
+
+		mockSvc.On("PromoteToAdminByEmail", mock.Anything, "missing@example.com").Return(domain.ErrUserNotFound)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/promote", bytes.NewBufferString(`{"email":"missing@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+
+		handler.PromoteUserToAdminByEmail(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestAdminHandler_SetUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	put := func(handler *AdminHandler, id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/admin/users/"+id+"/role", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Username: "root", Role: domain.RoleAdmin})
+		handler.SetUserRole(c)
+		return w
+	}
+
+	t.Run("valid role", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		userID := uuid.New()
+		mockSvc.On("SetRole", mock.Anything, userID, "admin").Return(&authusecase.UserSummary{UserID: userID, Role: "admin"}, nil)
+
+		w := put(NewAdminHandler(mockSvc, logger), userID.String(), `{"role":"admin"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data authusecase.UserSummary `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "admin", body.Data.Role)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		userID := uuid.New()
+		mockSvc.On("SetRole", mock.Anything, userID, "superuser").Return(nil, domain.ErrInvalidRole)
+
+		w := put(NewAdminHandler(mockSvc, logger), userID.String(), `{"role":"superuser"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid_role")
+	})
+
+	t.Run("missing role", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		w := put(NewAdminHandler(mockSvc, logger), uuid.NewString(), `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("last admin", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		userID := uuid.New()
+		mockSvc.On("SetRole", mock.Anything, userID, "user").Return(nil, domain.ErrLastAdmin)
+
+		w := put(NewAdminHandler(mockSvc, logger), userID.String(), `{"role":"user"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "last admin")
+	})
+}
+
+func TestAdminHandler_CreateUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	post := func(handler *AdminHandler, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/users", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Username: "root", Role: domain.RoleAdmin})
+		handler.CreateUser(c)
+		return w
+	}
+
+	t.Run("created", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		input := authusecase.CreateUserInput{Username: "jane", Email: "jane@example.com", Password: "Secret#123"}
+		userID := uuid.New()
+		mockSvc.On("CreateUser", mock.Anything, input).Return(&authusecase.UserSummary{UserID: userID, Username: "jane", Email: "jane@example.com", Role: "user"}, nil)
+
+		w := post(NewAdminHandler(mockSvc, logger), `{"username":"jane","email":"jane@example.com","password":"Secret#123"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var body struct {
+			Data authusecase.UserSummary `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, userID, body.Data.UserID)
+		assert.Equal(t, "user", body.Data.Role)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		w := post(NewAdminHandler(mockSvc, logger), `{"username":"jane","email":"jane@example.com"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		mockSvc.On("CreateUser", mock.Anything, mock.Anything).Return(nil, domain.ErrEmailAlreadyExists)
+
+		w := post(NewAdminHandler(mockSvc, logger), `{"username":"jane","email":"jane@example.com","password":"Secret#123"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "email already exists")
+	})
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	get := func(handler *AdminHandler, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/"+id, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		handler.GetUser(c)
+		return w
+	}
+
+	t.Run("found", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		userID := uuid.New()
+		mockSvc.On("GetUser", mock.Anything, userID).Return(&authusecase.UserSummary{
+			UserID:   userID,
+			Username: "jane",
+			Email:    "jane@example.com",
+			Role:     "user",
+		}, nil)
+
+		w := get(NewAdminHandler(mockSvc, logger), userID.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "jane@example.com", body.Data["email"])
+		assert.NotContains(t, body.Data, "password")
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		userID := uuid.New()
+		mockSvc.On("GetUser", mock.Anything, userID).Return(nil, domain.ErrUserNotFound)
+
+		w := get(NewAdminHandler(mockSvc, logger), userID.String())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		mockSvc := new(mockAuthServiceForAdmin)
+		w := get(NewAdminHandler(mockSvc, logger), "not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminHandler_FlushCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c := cache.NewMemoryCache(time.Minute, 10)
+	c.Set("product:1", "a")
+	c.Set("products:p=1&s=10", "b")
+	handler := NewAdminHandler(new(mockAuthServiceForAdmin), zap.NewNop()).WithCache(cache.Memory(c))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil)
+	ctx.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Username: "root", Role: domain.RoleAdmin})
+	handler.FlushCache(ctx)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, c.Len())
+	_, ok := c.Get("product:1")
+	assert.False(t, ok)
+
+	var body struct {
+		Data map[string]int `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Data["entries"])
+
+	w = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil)
+	NewAdminHandler(new(mockAuthServiceForAdmin), zap.NewNop()).WithCache(outageCache{}).FlushCache(ctx)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "a cache that cannot be reached is reported")
+}
+
+func TestAdminHandler_CacheStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c := cache.NewMemoryCache(time.Minute, 10)
+	c.Set("product:1", "a")
+	c.Get("product:1")
+	c.Get("product:2")
+
+	for name, tc := range map[string]struct {
+		cache cache.Cache
+		want  string
+	}{
+		"enabled":  {cache.Memory(c), `{"enabled":true,"size":1,"max":10,"softLimit":0,"hits":1,"misses":1,"rejected":0}`},
+		"no stats": {outageCache{}, `{"enabled":true}`},
+		"disabled": {nil, `{"enabled":false}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewAdminHandler(new(mockAuthServiceForAdmin), zap.NewNop()).WithCache(tc.cache)
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/cache/stats", nil)
+			handler.CacheStats(ctx)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body struct {
+				Data json.RawMessage `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.JSONEq(t, tc.want, string(body.Data))
+		})
+	}
+}
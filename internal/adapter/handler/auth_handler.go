@@ -2,36 +2,68 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
-	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
 	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
 	"github.com/minilik/ecommerce/pkg/response"
 )
 
+// TokenDelivery selects how Login hands the access token to the client.
+type TokenDelivery string
+
+const (
+	// TokenDeliveryHeader returns the token in the JSON body for use in the Authorization header.
+	TokenDeliveryHeader TokenDelivery = "header"
+	// TokenDeliveryCookie only sets an HttpOnly cookie, keeping the token out of JS-readable storage.
+	TokenDeliveryCookie TokenDelivery = "cookie"
+	// TokenDeliveryBoth returns the token in the body and sets the cookie.
+	TokenDeliveryBoth TokenDelivery = "both"
+)
+
 type AuthHandler struct {
-	service authusecase.Service
-	logger  *zap.Logger
+	service      authusecase.Service
+	logger       *zap.Logger
+	delivery     TokenDelivery
+	cookieName   string
+	cookieSecure bool
 }
 
 func NewAuthHandler(service authusecase.Service, logger *zap.Logger) *AuthHandler {
 	return &AuthHandler{
-		service: service,
-		logger:  logger,
+		service:  service,
+		logger:   logger,
+		delivery: TokenDeliveryHeader,
+	}
+}
+
+// WithTokenDelivery configures how Login delivers the token. Unknown modes fall back to header delivery.
+func (h *AuthHandler) WithTokenDelivery(mode TokenDelivery, cookieName string, secure bool) *AuthHandler {
+	switch mode {
+	case TokenDeliveryCookie, TokenDeliveryBoth:
+		h.delivery = mode
+	default:
+		h.delivery = TokenDeliveryHeader
 	}
+	h.cookieName = cookieName
+	h.cookieSecure = secure
+	return h
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
 	// @Summary Register a new user
-	// @Description Create a new user account (role=user)
+	// @Description Create a new user account (role=user). Refused with 403 when auth.registration_enabled is false
 	// @Tags Auth
 	// @Accept json
 	// @Produce json
 	// @Param payload body authusecase.RegisterInput true "Register payload"
 	// @Success 201 {object} response.Base
 	// @Failure 400 {object} response.Base
+	// @Failure 403 {object} response.Base
 	// @Router /auth/register [post]
 	var input authusecase.RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -41,13 +73,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	res, err := h.service.Register(c.Request.Context(), input)
 	if err != nil {
-		switch err {
-		case domain.ErrEmailAlreadyExists, domain.ErrUsernameAlreadyExists, domain.ErrInvalidCredentials:
-			c.JSON(http.StatusBadRequest, response.ErrorBase(err.Error(), []string{err.Error()}))
-		default:
-			h.logger.Error("register failed", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, response.ErrorBase("registration failed", []string{err.Error()}))
-		}
+		h.logger.Warn("register failed", zap.Error(err))
+		respondError(c, err, "registration failed")
 		return
 	}
 
@@ -73,14 +100,190 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	res, err := h.service.Login(c.Request.Context(), input)
 	if err != nil {
-		if err == domain.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, response.ErrorBase("invalid credentials", []string{err.Error()}))
-			return
+		h.logger.Warn("login failed", zap.Error(err))
+		respondError(c, err, "login failed")
+		return
+	}
+
+	if h.delivery != TokenDeliveryHeader && h.cookieName != "" {
+		maxAge := int(time.Until(res.ExpiresAt).Seconds())
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(h.cookieName, res.Token, maxAge, "/", "", h.cookieSecure, true)
+		if h.delivery == TokenDeliveryCookie {
+			res.Token = ""
 		}
-		h.logger.Error("login failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("login failed", []string{err.Error()}))
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("login successful", authResponse{AuthResponse: res, ExpiresAt: response.NewTime(res.ExpiresAt)}))
+}
+
+func (h *AuthHandler) UpdateEmail(c *gin.Context) {
+	// @Summary Update email
+	// @Description Change the authenticated user's email; requires the current password
+	// @Tags Auth
+	// @Accept json
+	// @Produce json
+	// @Security BearerAuth
+	// @Param payload body authusecase.UpdateEmailInput true "Update email payload"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 401 {object} response.Base
+	// @Router /auth/email [put]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	var input authusecase.UpdateEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+
+	res, err := h.service.UpdateEmail(c.Request.Context(), claims.UserID, input)
+	if err != nil {
+		h.logger.Warn("update email failed", zap.Error(err))
+		respondError(c, err, "failed to update email")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("email updated", res))
+}
+
+// WhoAmIResponse echoes the claims the server read from the caller's token.
+type WhoAmIResponse struct {
+	UserID    uuid.UUID     `json:"userId"`
+	Username  string        `json:"username"`
+	Role      string        `json:"role"`
+	Issuer    string        `json:"issuer"`
+	IssuedAt  response.Time `json:"issuedAt"`
+	ExpiresAt response.Time `json:"expiresAt"`
+}
+
+// WhoAmI returns the parsed token claims without touching the database, so clients can check what
+// the server extracted from their token.
+func (h *AuthHandler) WhoAmI(c *gin.Context) {
+	// @Summary Token claims
+	// @Description Return the claims parsed from the caller's token (no database lookup)
+	// @Tags Auth
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 200 {object} response.Base
+	// @Failure 401 {object} response.Base
+	// @Router /auth/whoami [get]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("token claims", WhoAmIResponse{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Role:      string(claims.Role),
+		Issuer:    claims.Issuer,
+		IssuedAt:  response.NewTime(claims.IssuedAt),
+		ExpiresAt: response.NewTime(claims.ExpiresAt),
+	}))
+}
+
+func (h *AuthHandler) SendEmailVerification(c *gin.Context) {
+	// @Summary Send email verification
+	// @Description Send a signed, expiring verification link to the authenticated user's email
+	// @Tags Auth
+	// @Produce json
+	// @Security BearerAuth
+	// @Success 202 {object} response.Base
+	// @Failure 401 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Router /auth/verify/send [post]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	res, err := h.service.SendEmailVerification(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.logger.Warn("send email verification failed", zap.Error(err))
+		respondError(c, err, "failed to send verification email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessBase("verification email sent", emailVerificationResponse{EmailVerificationResponse: res, ExpiresAt: response.NewTime(res.ExpiresAt)}))
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	// @Summary Verify email
+	// @Description Mark an email as verified using the token from a verification link
+	// @Tags Auth
+	// @Produce json
+	// @Param token query string true "Verification token"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Router /auth/verify [get]
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{"token is required"}))
+		return
+	}
+
+	res, err := h.service.VerifyEmail(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, err, "failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("email verified", res))
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	// @Summary Request password reset
+	// @Description Send a single-use, expiring password reset link. The response is the same whether or not the email is registered
+	// @Tags Auth
+	// @Accept json
+	// @Produce json
+	// @Param payload body authusecase.ForgotPasswordInput true "Account email"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Router /auth/password/forgot [post]
+	var input authusecase.ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+
+	if err := h.service.ForgotPassword(c.Request.Context(), input); err != nil {
+		h.logger.Error("forgot password failed", zap.Error(err))
+		respondError(c, err, "failed to request password reset")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessEmpty("if the email is registered, a reset link has been sent"))
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	// @Summary Reset password
+	// @Description Set a new password using the token from a reset link; the token works once
+	// @Tags Auth
+	// @Accept json
+	// @Produce json
+	// @Param payload body authusecase.ResetPasswordInput true "Reset token and new password"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Router /auth/password/reset [post]
+	var input authusecase.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), input); err != nil {
+		respondError(c, err, "failed to reset password")
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessBase("login successful", res))
+	c.JSON(http.StatusOK, response.SuccessEmpty("password updated"))
 }
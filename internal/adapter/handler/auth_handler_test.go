@@ -7,14 +7,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
 	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
 )
 
 type mockAuthService struct {
@@ -29,6 +33,14 @@ func (m *mockAuthService) Register(ctx context.Context, input authusecase.Regist
 	return args.Get(0).(*authusecase.RegisterResponse), args.Error(1)
 }
 
+func (m *mockAuthService) CreateUser(ctx context.Context, input authusecase.CreateUserInput) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
 func (m *mockAuthService) Login(ctx context.Context, input authusecase.LoginInput) (*authusecase.AuthResponse, error) {
 	args := m.Called(ctx, input)
 	if args.Get(0) == nil {
@@ -42,6 +54,66 @@ func (m *mockAuthService) PromoteToAdmin(ctx context.Context, userID uuid.UUID)
 	return args.Error(0)
 }
 
+func (m *mockAuthService) PromoteToAdminByEmail(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *mockAuthService) UpdateEmail(ctx context.Context, userID uuid.UUID, input authusecase.UpdateEmailInput) (*authusecase.UpdateEmailResponse, error) {
+	args := m.Called(ctx, userID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UpdateEmailResponse), args.Error(1)
+}
+
+func (m *mockAuthService) GetUser(ctx context.Context, userID uuid.UUID) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
+func (m *mockAuthService) SendEmailVerification(ctx context.Context, userID uuid.UUID) (*authusecase.EmailVerificationResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.EmailVerificationResponse), args.Error(1)
+}
+
+func (m *mockAuthService) VerifyEmail(ctx context.Context, token string) (*authusecase.VerifyEmailResponse, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.VerifyEmailResponse), args.Error(1)
+}
+
+func (m *mockAuthService) ForgotPassword(ctx context.Context, input authusecase.ForgotPasswordInput) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *mockAuthService) SetRole(ctx context.Context, userID uuid.UUID, role string) (*authusecase.UserSummary, error) {
+	args := m.Called(ctx, userID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authusecase.UserSummary), args.Error(1)
+}
+
+func (m *mockAuthService) ResetPassword(ctx context.Context, input authusecase.ResetPasswordInput) error {
+	args := m.Called(ctx, input)
+	return args.Error(0)
+}
+
+func (m *mockAuthService) TokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
@@ -113,3 +185,109 @@ func TestAuthHandler_Login(t *testing.T) {
 		mockSvc.AssertExpectations(t)
 	})
 }
+
+func TestAuthHandler_Login_TokenDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+	input := authusecase.LoginInput{Email: "test@example.com", Password: "password123"}
+
+	login := func(mode TokenDelivery) *httptest.ResponseRecorder {
+		mockSvc := new(mockAuthService)
+		mockSvc.On("Login", mock.Anything, input).Return(&authusecase.AuthResponse{
+			Token:     "test-token",
+			ExpiresAt: time.Now().Add(30 * time.Minute),
+			UserID:    uuid.New(),
+			Email:     "test@example.com",
+			Role:      "user",
+		}, nil)
+		handler := NewAuthHandler(mockSvc, logger).WithTokenDelivery(mode, "access_token", true)
+
+		body, _ := json.Marshal(input)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.Login(c)
+		return w
+	}
+	bodyToken := func(t *testing.T, w *httptest.ResponseRecorder) (string, bool) {
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		token, ok := body.Data["token"].(string)
+		return token, ok
+	}
+
+	tests := []struct {
+		mode       TokenDelivery
+		wantBody   bool
+		wantCookie bool
+	}{
+		{mode: TokenDeliveryHeader, wantBody: true, wantCookie: false},
+		{mode: TokenDeliveryCookie, wantBody: false, wantCookie: true},
+		{mode: TokenDeliveryBoth, wantBody: true, wantCookie: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			w := login(tt.mode)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			token, ok := bodyToken(t, w)
+			assert.Equal(t, tt.wantBody, ok, "token in body")
+			if tt.wantBody {
+				assert.Equal(t, "test-token", token)
+			}
+
+			setCookie := w.Header().Get("Set-Cookie")
+			if !tt.wantCookie {
+				assert.Empty(t, setCookie)
+				return
+			}
+			assert.Contains(t, setCookie, "access_token=test-token")
+			assert.Contains(t, setCookie, "HttpOnly")
+			assert.Contains(t, setCookie, "Secure")
+			assert.Contains(t, setCookie, "SameSite=Lax")
+		})
+	}
+}
+
+func TestAuthHandler_WhoAmI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	manager, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+	userID := uuid.New()
+	token, err := manager.GenerateAccessToken(userID, "jane", "admin", 30*time.Minute, "ecommerce-api")
+	require.NoError(t, err)
+
+	handler := NewAuthHandler(new(mockAuthService), logger)
+	r := gin.New()
+	r.GET("/api/v1/auth/whoami", middleware.NewAuthMiddleware(logger, manager).RequireAuth(), handler.WhoAmI)
+
+	t.Run("claims round-trip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data WhoAmIResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, userID, body.Data.UserID)
+		assert.Equal(t, "jane", body.Data.Username)
+		assert.Equal(t, "admin", body.Data.Role)
+		assert.Equal(t, "ecommerce-api", body.Data.Issuer)
+		assert.WithinDuration(t, time.Now(), body.Data.IssuedAt.Time, 5*time.Second)
+		assert.WithinDuration(t, body.Data.IssuedAt.Add(30*time.Minute), body.Data.ExpiresAt.Time, time.Second)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/auth/whoami", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// respondCacheable writes body as JSON with a strong ETag over the encoded bytes and a public
+// Cache-Control of maxAge (no-cache when maxAge is zero, so clients still revalidate). A request
+// whose If-None-Match lists the current ETag gets 304 Not Modified with no body.
+func respondCacheable(c *gin.Context, logger *zap.Logger, body interface{}, maxAge time.Duration) {
+	encoded, ok := encodeBody(c, logger, body)
+	if !ok {
+		return
+	}
+	etag := bodyETag(encoded)
+
+	c.Header("ETag", etag)
+	if maxAge > 0 {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", encoded)
+}
+
+// respondVersioned writes a single resource that last changed at lastModified. It sends a weak
+// ETag, which stays valid if a proxy compresses the body, and a Last-Modified header, with
+// Cache-Control no-cache so clients revalidate every time. As RFC 9110 requires, If-None-Match
+// decides alone when it is present; If-Modified-Since is only checked when it is absent.
+func respondVersioned(c *gin.Context, logger *zap.Logger, body interface{}, lastModified time.Time) {
+	encoded, ok := encodeBody(c, logger, body)
+	if !ok {
+		return
+	}
+	etag := "W/" + bodyETag(encoded)
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", "no-cache")
+	if notModifiedSince(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", encoded)
+}
+
+// notModifiedSince evaluates a request's conditional headers against etag and lastModified.
+// An unparsable If-Modified-Since is ignored, as is one on a resource with no modification time.
+func notModifiedSince(c *gin.Context, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+	ifModifiedSince := c.GetHeader("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.After(since)
+}
+
+// encodeBody marshals body for the conditional responses. A body that cannot be encoded is logged
+// and answered with a plain 500, since encoding it again for the error response would fail too.
+func encodeBody(c *gin.Context, logger *zap.Logger, body interface{}) ([]byte, bool) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		logger.Error("failed to encode response body", zap.String("path", c.FullPath()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorBase("internal server error", nil))
+		return nil, false
+	}
+	return encoded, true
+}
+
+func bodyETag(encoded []byte) string {
+	sum := sha256.Sum256(encoded)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches applies If-None-Match's weak comparison: "*" or any listed tag equal to etag once
+// W/ prefixes are ignored.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
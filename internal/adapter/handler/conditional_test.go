@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+)
+
+type stubImageService struct {
+	productusecase.ImageService
+	images   []domain.ProductImage
+	bulk     []productusecase.BulkUploadResult
+	disabled bool
+}
+
+func (s *stubImageService) UploadBulk(ctx context.Context, uploads []productusecase.BulkImageUpload) ([]productusecase.BulkUploadResult, error) {
+	return s.bulk, nil
+}
+
+func (s *stubImageService) ListImages(ctx context.Context, productID uuid.UUID) ([]domain.ProductImage, error) {
+	return s.images, nil
+}
+
+func (s *stubImageService) Enabled() bool {
+	return !s.disabled
+}
+
+func TestProductHandler_ListImages_Conditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	productID := uuid.New()
+	images := &stubImageService{images: []domain.ProductImage{{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/lamp.jpg"}}}
+	h := NewProductHandler(nil, zap.NewNop()).
+		WithImageService(images).
+		WithImageCacheMaxAge(5 * time.Minute)
+	r := gin.New()
+	r.GET("/products/:id/images", h.ListImages)
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products/"+productID.String()+"/images", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "public, max-age=300", first.Header().Get("Cache-Control"))
+	assert.Contains(t, first.Body.String(), "lamp.jpg")
+
+	notModified := get(etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotModified, get(`"other", W/`+etag).Code, "weak comparison within a list")
+	assert.Equal(t, http.StatusOK, get(`"stale"`).Code)
+
+	images.images = append(images.images, domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/desk.jpg"})
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code, "a new image changes the ETag")
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestProductHandler_SignedImageURLs_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewProductHandler(nil, zap.NewNop()).WithImageService(&stubImageService{disabled: true})
+	r := gin.New()
+	r.GET("/products/:id/images/signed", h.SignedImageURLs)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/"+uuid.NewString()+"/images/signed", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"images_disabled"`)
+}
+
+func TestProductHandler_ListCategories(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(mockProductService)
+	svc.On("ListCategories", mock.Anything).Return([]domain.CategoryCount{{Name: "Books", Count: 12}}, nil)
+	h := NewProductHandler(svc, zap.NewNop()).WithCategoryCacheMaxAge(time.Minute)
+	r := gin.New()
+	r.GET("/products/categories", h.ListCategories)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/categories", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"message":"product categories retrieved","data":[{"name":"Books","count":12}]}`, w.Body.String())
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+}
+
+func TestProductHandler_Get_Conditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updatedAt := time.Date(2025, 3, 4, 10, 30, 15, 500000000, time.UTC)
+	product := &domain.Product{ID: uuid.New(), Name: "Lamp", UpdatedAt: updatedAt}
+	svc := new(mockProductService)
+	svc.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+	h := NewProductHandler(svc, zap.NewNop())
+	r := gin.New()
+	r.GET("/products/:id", h.Get)
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get(nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "Tue, 04 Mar 2025 10:30:15 GMT", first.Header().Get("Last-Modified"))
+	etag := first.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), "weak ETag, got %q", etag)
+	assert.Equal(t, "no-cache", first.Header().Get("Cache-Control"))
+	assert.Contains(t, first.Body.String(), "Lamp")
+
+	t.Run("If-Modified-Since", func(t *testing.T) {
+		notModified := get(map[string]string{"If-Modified-Since": "Tue, 04 Mar 2025 10:30:15 GMT"})
+		assert.Equal(t, http.StatusNotModified, notModified.Code, "sub-second precision is not compared")
+		assert.Empty(t, notModified.Body.String())
+		assert.Equal(t, "Tue, 04 Mar 2025 10:30:15 GMT", notModified.Header().Get("Last-Modified"))
+
+		assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-Modified-Since": "Wed, 05 Mar 2025 00:00:00 GMT"}).Code)
+		assert.Equal(t, http.StatusOK, get(map[string]string{"If-Modified-Since": "Tue, 04 Mar 2025 10:30:14 GMT"}).Code)
+		assert.Equal(t, http.StatusOK, get(map[string]string{"If-Modified-Since": "yesterday"}).Code, "an invalid date is ignored")
+	})
+
+	t.Run("If-None-Match", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-None-Match": etag}).Code)
+		assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-None-Match": strings.TrimPrefix(etag, "W/")}).Code)
+	})
+
+	t.Run("If-None-Match takes precedence", func(t *testing.T) {
+		w := get(map[string]string{"If-None-Match": `W/"stale"`, "If-Modified-Since": "Wed, 05 Mar 2025 00:00:00 GMT"})
+		assert.Equal(t, http.StatusOK, w.Code, "a date that would match does not override a mismatched ETag")
+
+		w = get(map[string]string{"If-None-Match": etag, "If-Modified-Since": "Mon, 03 Mar 2025 00:00:00 GMT"})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+}
+
+func TestRespondCacheable_EncodeFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.ErrorLevel)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products/suggest", nil)
+
+	respondCacheable(c, zap.New(core), gin.H{"price": math.Inf(1)}, time.Minute)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"success":false,"message":"internal server error"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+	require.Equal(t, 1, logs.Len())
+	assert.Contains(t, logs.All()[0].ContextMap()["error"], "unsupported value")
+}
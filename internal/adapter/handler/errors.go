@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// respondError writes err as a response.Base. A *domain.AppError anywhere in the chain supplies
+// the status, code and message; any other error is an unexpected failure reported as 500 with fallback.
+// An error in the chain with an ErrorDetails method also fills the details field.
+// A cancelled or timed-out context is reported as 499 or 503 without the underlying driver error.
+func respondError(c *gin.Context, err error, fallback string) {
+	if ctxErr := domain.ContextError(err); ctxErr != nil {
+		err = ctxErr
+	}
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		resp := response.ErrorCode(appErr.Code, appErr.Message, errorMessages(err))
+		var detailed interface{ ErrorDetails() interface{} }
+		if errors.As(err, &detailed) {
+			resp.Details = detailed.ErrorDetails()
+		}
+		c.JSON(appErr.Status, resp)
+		return
+	}
+	c.JSON(http.StatusInternalServerError, response.ErrorBase(fallback, errorMessages(err)))
+}
+
+// errorMessages flattens a joined error into one message per wrapped error.
+func errorMessages(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		msgs := make([]string, 0, len(joined.Unwrap()))
+		for _, e := range joined.Unwrap() {
+			msgs = append(msgs, e.Error())
+		}
+		return msgs
+	}
+	return []string{err.Error()}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	run := func(err error) (int, response.Base) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		respondError(c, err, "fallback message")
+
+		var body response.Base
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	t.Run("app error maps to its status", func(t *testing.T) {
+		code, body := run(domain.ErrProductNotFound)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.False(t, body.Success)
+		assert.Equal(t, "product not found", body.Message)
+		assert.Equal(t, "product_not_found", body.Code)
+	})
+
+	t.Run("wrapped app error keeps status and full detail", func(t *testing.T) {
+		code, body := run(fmt.Errorf("%w: Desk Lamp", domain.ErrInsufficientStock))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "insufficient stock", body.Message)
+		assert.Equal(t, []string{"insufficient stock: Desk Lamp"}, body.Errors)
+	})
+
+	t.Run("stock shortages are returned as details", func(t *testing.T) {
+		productID := uuid.New()
+		code, body := run(&domain.InsufficientStockError{Shortages: []domain.StockShortage{
+			{ProductID: productID, ProductName: "Desk Lamp", Requested: 3, Available: 1},
+		}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "insufficient_stock", body.Code)
+		assert.Equal(t, []string{"insufficient stock: Desk Lamp"}, body.Errors)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"productId":   productID.String(),
+			"productName": "Desk Lamp",
+			"requested":   float64(3),
+			"available":   float64(1),
+		}}, body.Details)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		code, body := run(domain.NewValidationError("price must be greater than zero"))
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "validation_failed", body.Code)
+	})
+
+	t.Run("plain error defaults to 500", func(t *testing.T) {
+		code, body := run(errors.New("connection reset"))
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.Equal(t, "fallback message", body.Message)
+		assert.Empty(t, body.Code)
+		assert.Equal(t, []string{"connection reset"}, body.Errors)
+	})
+
+	t.Run("context errors are mapped without driver detail", func(t *testing.T) {
+		code, body := run(fmt.Errorf("failed to connect to `host=db`: %w", context.Canceled))
+		assert.Equal(t, domain.StatusClientClosedRequest, code)
+		assert.Equal(t, "request_canceled", body.Code)
+		assert.Equal(t, []string{"request was canceled"}, body.Errors)
+
+		code, body = run(fmt.Errorf("select products: %w", context.DeadlineExceeded))
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "request_timeout", body.Code)
+	})
+
+	t.Run("joined errors are listed individually", func(t *testing.T) {
+		err := errors.Join(
+			fmt.Errorf("%w: a.svg", domain.ErrUnsupportedImage),
+			fmt.Errorf("%w: b.exe", domain.ErrUnsupportedImage),
+		)
+		code, body := run(err)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, []string{"unsupported image file: a.svg", "unsupported image file: b.exe"}, body.Errors)
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// productField is one entry of the fields allowlist: the key it has in the full product JSON and
+// how to read its value.
+type productField struct {
+	key   string
+	value func(p *domain.Product) interface{}
+}
+
+// productFields is the allowlist for the fields query parameter, keyed by the lower-cased name a
+// client passes. Projected products keep the keys of the full product JSON.
+var productFields = map[string]productField{
+	"id":          {"ID", func(p *domain.Product) interface{} { return p.ID }},
+	"name":        {"Name", func(p *domain.Product) interface{} { return p.Name }},
+	"description": {"Description", func(p *domain.Product) interface{} { return p.Description }},
+	"price":       {"Price", func(p *domain.Product) interface{} { return p.Price }},
+	"stock":       {"Stock", func(p *domain.Product) interface{} { return p.Stock }},
+	"category":    {"Category", func(p *domain.Product) interface{} { return p.Category }},
+	"categoryid":  {"CategoryId", func(p *domain.Product) interface{} { return p.CategoryId }},
+	"userid":      {"UserID", func(p *domain.Product) interface{} { return p.UserID }},
+	"images":      {"images", func(p *domain.Product) interface{} { return toAssetResponses(p.Images) }},
+	"createdat":   {"CreatedAt", func(p *domain.Product) interface{} { return response.NewTime(p.CreatedAt) }},
+	"updatedat":   {"UpdatedAt", func(p *domain.Product) interface{} { return response.NewTime(p.UpdatedAt) }},
+	"externalid":  {"externalId", func(p *domain.Product) interface{} { return p.ExternalID }},
+}
+
+// parseProductFields parses a comma-separated fields parameter. An empty value returns nil, which
+// keeps the full product; an unknown name is a validation error.
+func parseProductFields(raw string) ([]productField, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var fields []productField
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		field, ok := productFields[name]
+		if !ok {
+			return nil, domain.NewValidationError("unknown field %q", name)
+		}
+		seen[name] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// projectProducts returns products unchanged when fields is empty, and otherwise one object per
+// product holding only the requested fields.
+func projectProducts(products []domain.Product, fields []productField) interface{} {
+	if len(fields) == 0 {
+		return toProductResponses(products)
+	}
+	out := make([]map[string]interface{}, len(products))
+	for i := range products {
+		projected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			projected[field.key] = field.value(&products[i])
+		}
+		out[i] = projected
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// Dependency states reported by the readiness endpoint.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// ErrDegraded marks a dependency that still works in a limited way, e.g. an uploader that is not
+// configured. Checks wrap it to report "degraded" instead of "down".
+var ErrDegraded = errors.New("degraded")
+
+// HealthCheck probes one dependency. A nil error means ok.
+type HealthCheck func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthReport is the readiness body: the overall status and one status per dependency.
+type HealthReport struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+type HealthHandler struct {
+	checks   []namedCheck
+	critical map[string]bool
+	timeout  time.Duration
+	logger   *zap.Logger
+}
+
+// NewHealthHandler reports on the given critical dependencies. Only a critical dependency that is
+// down fails readiness; the others only change the body.
+func NewHealthHandler(critical []string, timeout time.Duration, logger *zap.Logger) *HealthHandler {
+	h := &HealthHandler{critical: make(map[string]bool, len(critical)), timeout: timeout, logger: logger}
+	for _, name := range critical {
+		h.critical[name] = true
+	}
+	return h
+}
+
+// WithCheck adds a named dependency check. Checks run concurrently on each request.
+func (h *HealthHandler) WithCheck(name string, check HealthCheck) *HealthHandler {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+	return h
+}
+
+// Ready runs every check and answers 200 unless a critical dependency is down.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	// @Summary Readiness check
+	// @Description Report the status of each dependency; 503 when a critical one is down
+	// @Tags Health
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Router /health/ready [get]
+	report := h.Report(c.Request.Context())
+	if report.Status == HealthDown {
+		c.JSON(http.StatusServiceUnavailable, response.Base{Success: false, Message: "not ready", Data: report})
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("ready", report))
+}
+
+// Report runs the checks. The overall status is down when a critical dependency is down,
+// degraded when any dependency is not ok, and ok otherwise.
+func (h *HealthHandler) Report(ctx context.Context) HealthReport {
+	statuses := make([]string, len(h.checks))
+	var wg sync.WaitGroup
+	for i, nc := range h.checks {
+		wg.Add(1)
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+			statuses[i] = h.run(ctx, nc)
+		}(i, nc)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthOK, Checks: make(map[string]string, len(h.checks))}
+	for i, nc := range h.checks {
+		report.Checks[nc.name] = statuses[i]
+		switch {
+		case statuses[i] == HealthOK:
+		case statuses[i] == HealthDown && h.critical[nc.name]:
+			report.Status = HealthDown
+		case report.Status == HealthOK:
+			report.Status = HealthDegraded
+		}
+	}
+	return report
+}
+
+func (h *HealthHandler) run(ctx context.Context, nc namedCheck) string {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	err := nc.check(ctx)
+	if err == nil {
+		return HealthOK
+	}
+	h.logger.Warn("health check failed", zap.String("dependency", nc.name), zap.Error(err))
+	if errors.Is(err, ErrDegraded) {
+		return HealthDegraded
+	}
+	return HealthDown
+}
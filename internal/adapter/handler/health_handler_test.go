@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func serveReady(t *testing.T, h *HealthHandler) (int, HealthReport) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/health/ready", nil)
+
+	h.Ready(c)
+
+	var body struct {
+		Data HealthReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body.Data
+}
+
+func okCheck(context.Context) error { return nil }
+
+func TestHealthHandler_Ready(t *testing.T) {
+	degraded := func(context.Context) error { return fmt.Errorf("uploader not configured: %w", ErrDegraded) }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+
+	t.Run("all ok", func(t *testing.T) {
+		h := NewHealthHandler([]string{"db"}, time.Second, zap.NewNop()).
+			WithCheck("db", okCheck).
+			WithCheck("cache", okCheck).
+			WithCheck("uploader", okCheck)
+
+		code, report := serveReady(t, h)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, HealthOK, report.Status)
+		assert.Equal(t, map[string]string{"db": "ok", "cache": "ok", "uploader": "ok"}, report.Checks)
+	})
+
+	t.Run("non-critical degraded", func(t *testing.T) {
+		h := NewHealthHandler([]string{"db"}, time.Second, zap.NewNop()).
+			WithCheck("db", okCheck).
+			WithCheck("cache", failing).
+			WithCheck("uploader", degraded)
+
+		code, report := serveReady(t, h)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Equal(t, map[string]string{"db": "ok", "cache": "down", "uploader": "degraded"}, report.Checks)
+	})
+
+	t.Run("critical failing", func(t *testing.T) {
+		h := NewHealthHandler([]string{"db", "cache"}, time.Second, zap.NewNop()).
+			WithCheck("db", failing).
+			WithCheck("cache", okCheck).
+			WithCheck("uploader", degraded)
+
+		code, report := serveReady(t, h)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, HealthDown, report.Status)
+		assert.Equal(t, "down", report.Checks["db"])
+	})
+
+	t.Run("check timeout counts as down", func(t *testing.T) {
+		slow := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		h := NewHealthHandler([]string{"db"}, 10*time.Millisecond, zap.NewNop()).WithCheck("db", slow)
+
+		code, report := serveReady(t, h)
+
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", report.Checks["db"])
+	})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+)
+
+// ParseCDNBase validates cloudinary.cdn_base. An empty value returns nil, which disables rewriting.
+func ParseCDNBase(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	base, err := url.Parse(raw)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid cdn base %q: want an absolute http(s) URL", raw)
+	}
+	return base, nil
+}
+
+// rewriteImageURL moves a stored image URL onto base: scheme and host are replaced and base's path,
+// if any, is prefixed, while the stored path (Cloudinary's /image/upload/.../public_id) is kept.
+// URLs that do not parse as absolute URLs are returned unchanged.
+func rewriteImageURL(base *url.URL, stored string) string {
+	if base == nil {
+		return stored
+	}
+	u, err := url.Parse(stored)
+	if err != nil || u.Host == "" {
+		return stored
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	u.Path = strings.TrimSuffix(base.Path, "/") + u.Path
+	u.RawPath = ""
+	return u.String()
+}
+
+// The helpers below copy before rewriting: products may be shared with the service's cache.
+
+func rewriteImages(base *url.URL, images []domain.ProductImage) []domain.ProductImage {
+	if base == nil || len(images) == 0 {
+		return images
+	}
+	out := make([]domain.ProductImage, len(images))
+	for i, img := range images {
+		img.URL = rewriteImageURL(base, img.URL)
+		out[i] = img
+	}
+	return out
+}
+
+func rewriteProduct(base *url.URL, product *domain.Product) *domain.Product {
+	if base == nil || product == nil || len(product.Images) == 0 {
+		return product
+	}
+	copied := *product
+	copied.Images = rewriteImages(base, product.Images)
+	return &copied
+}
+
+func rewriteProducts(base *url.URL, products []domain.Product) []domain.Product {
+	if base == nil {
+		return products
+	}
+	out := make([]domain.Product, len(products))
+	for i := range products {
+		out[i] = products[i]
+		out[i].Images = rewriteImages(base, products[i].Images)
+	}
+	return out
+}
+
+func rewriteBulkResults(base *url.URL, results []productusecase.BulkUploadResult) []productusecase.BulkUploadResult {
+	if base == nil {
+		return results
+	}
+	out := make([]productusecase.BulkUploadResult, len(results))
+	for i := range results {
+		out[i] = results[i]
+		out[i].Images = rewriteImages(base, results[i].Images)
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
+	"github.com/minilik/ecommerce/internal/domain"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+)
+
+func TestProductHandler_CDNBase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const stored = "https://res.cloudinary.com/demo/image/upload/v1700000000/ecommerce/lamp.jpg"
+	product := &domain.Product{
+		ID:     uuid.New(),
+		Name:   "Lamp",
+		Images: []domain.ProductImage{{ID: uuid.New(), URL: stored}},
+	}
+	get := func(t *testing.T, h *ProductHandler) string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products/"+product.ID.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: product.ID.String()}}
+		h.Get(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var body struct {
+			Data domain.Product `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data.Images, 1)
+		return body.Data.Images[0].URL
+	}
+
+	t.Run("rewrites the host and keeps the path", func(t *testing.T) {
+		svc := new(mockProductService)
+		svc.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+		base, err := ParseCDNBase("https://cdn.example.com/assets/")
+		require.NoError(t, err)
+
+		url := get(t, NewProductHandler(svc, zap.NewNop()).WithCDNBase(base))
+		assert.Equal(t, "https://cdn.example.com/assets/demo/image/upload/v1700000000/ecommerce/lamp.jpg", url)
+		assert.Equal(t, stored, product.Images[0].URL, "the service's product is not modified")
+	})
+
+	t.Run("passes through when unset", func(t *testing.T) {
+		svc := new(mockProductService)
+		svc.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+		base, err := ParseCDNBase("")
+		require.NoError(t, err)
+
+		assert.Equal(t, stored, get(t, NewProductHandler(svc, zap.NewNop()).WithCDNBase(base)))
+	})
+
+	t.Run("rejects a base without a host", func(t *testing.T) {
+		_, err := ParseCDNBase("cdn.example.com")
+		assert.Error(t, err)
+	})
+}
+
+func TestProductHandler_CDNBase_CreateAndExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const (
+		stored    = "https://res.cloudinary.com/demo/image/upload/v1700000000/ecommerce/lamp.jpg"
+		rewritten = "https://cdn.example.com/demo/image/upload/v1700000000/ecommerce/lamp.jpg"
+	)
+	base, err := ParseCDNBase("https://cdn.example.com")
+	require.NoError(t, err)
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Images: []domain.ProductImage{{ID: uuid.New(), URL: stored}}}
+
+	t.Run("create", func(t *testing.T) {
+		// a retried create with a known external id returns the stored product, images included
+		input := productusecase.CreateProductInput{Name: "Lamp", Description: "Desk lamp", Price: 20, Stock: 1, Category: "Home", ExternalID: "lamp-1"}
+		adminID := uuid.New()
+		svc := new(mockProductService)
+		svc.On("Create", mock.Anything, adminID, input).Return(&product, nil)
+
+		encoded, err := json.Marshal(input)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(encoded))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("currentUser", middleware.UserClaims{UserID: adminID, Role: domain.RoleAdmin})
+		NewProductHandler(svc, zap.NewNop()).WithCDNBase(base).Create(c)
+
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var body struct {
+			Data domain.Product `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data.Images, 1)
+		assert.Equal(t, rewritten, body.Data.Images[0].URL)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		svc := new(mockProductService)
+		svc.On("Export", mock.Anything).Return([][]domain.Product{{product}}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/products/export", nil)
+		NewProductHandler(svc, zap.NewNop()).WithCDNBase(base).Export(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var products []domain.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+		require.Len(t, products, 1)
+		require.Len(t, products[0].Images, 1)
+		assert.Equal(t, rewritten, products[0].Images[0].URL)
+	})
+
+	assert.Equal(t, stored, product.Images[0].URL, "the service's product is not modified")
+}
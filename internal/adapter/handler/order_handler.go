@@ -1,10 +1,12 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/minilik/ecommerce/internal/adapter/middleware"
@@ -34,6 +36,7 @@ func (h *OrderHandler) Create(c *gin.Context) {
 	// @Param payload body orderusecase.CreateOrderInput true "Order payload"
 	// @Success 201 {object} response.Base
 	// @Failure 400 {object} response.Base
+	// @Failure 409 {object} response.Base
 	// @Security BearerAuth
 	// @Router /orders [post]
 	var input orderusecase.CreateOrderInput
@@ -57,18 +60,47 @@ func (h *OrderHandler) Create(c *gin.Context) {
 	order, err := h.service.Create(c.Request.Context(), claims.UserID, input)
 	if err != nil {
 		h.logger.Warn("failed to create order", zap.Error(err))
-		switch {
-		case errors.Is(err, domain.ErrProductNotFound):
-			c.JSON(http.StatusNotFound, response.ErrorBase("product not found", []string{err.Error()}))
-		case errors.Is(err, domain.ErrInsufficientStock):
-			c.JSON(http.StatusBadRequest, response.ErrorBase("insufficient stock", []string{err.Error()}))
-		default:
-			c.JSON(http.StatusBadRequest, response.ErrorBase("failed to create order", []string{err.Error()}))
-		}
+		respondError(c, err, "failed to create order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.SuccessBase("order created", toOrderResponse(order)))
+}
+
+func (h *OrderHandler) Reorder(c *gin.Context) {
+	// @Summary Reorder
+	// @Description Place a new order with the items of one of your previous orders, at current prices and subject to current stock. priceChanges lists items whose price differs from the original order
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Previous order ID"
+	// @Success 201 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id}/reorder [post]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	result, err := h.service.Reorder(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		h.logger.Warn("failed to reorder", zap.Error(err))
+		respondError(c, err, "failed to reorder")
 		return
 	}
 
-	c.JSON(http.StatusCreated, response.SuccessBase("order created", order))
+	message := "order created"
+	if len(result.PriceChanges) > 0 {
+		message = "order created; some prices changed since the original order"
+	}
+	c.JSON(http.StatusCreated, response.SuccessBase(message, reorderResponse{ReorderResult: result, Order: toOrderResponse(result.Order)}))
 }
 
 func (h *OrderHandler) List(c *gin.Context) {
@@ -76,6 +108,8 @@ func (h *OrderHandler) List(c *gin.Context) {
 	// @Description Get current user's orders
 	// @Tags Orders
 	// @Produce json
+	// @Param status query string false "Only orders in this status: pending, review_required, completed or cancelled"
+	// @Param include_archived query bool false "Also list archived orders"
 	// @Success 200 {object} response.Base
 	// @Security BearerAuth
 	// @Router /orders [get]
@@ -85,12 +119,280 @@ func (h *OrderHandler) List(c *gin.Context) {
 		return
 	}
 
-	orders, err := h.service.ListForUser(c.Request.Context(), claims.UserID)
+	var status domain.OrderStatus
+	if raw := c.Query("status"); raw != "" {
+		parsed, err := domain.ParseOrderStatus(raw)
+		if err != nil {
+			respondError(c, err, "invalid status")
+			return
+		}
+		status = parsed
+	}
+	includeArchived := false
+	if raw := c.Query("include_archived"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorBase("invalid include_archived", []string{"include_archived must be true or false"}))
+			return
+		}
+		includeArchived = parsed
+	}
+
+	orders, err := h.service.ListForUser(c.Request.Context(), claims.UserID, status, includeArchived)
 	if err != nil {
 		h.logger.Error("failed to list orders", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("failed to list orders", []string{err.Error()}))
+		respondError(c, err, "failed to list orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("orders retrieved", toOrderResponses(orders)))
+}
+
+// ExpireStale cancels stale pending orders and restocks their items (admin-only, cron friendly).
+func (h *OrderHandler) ExpireStale(c *gin.Context) {
+	// @Summary Expire stale pending orders
+	// @Description Cancel pending orders older than olderThan (defaults to the configured TTL) and restock their items (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Param olderThan query string false "Minimum order age as a Go duration, e.g. 48h"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/orders/expire [post]
+	var olderThan time.Duration
+	if raw := c.Query("olderThan"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, response.ErrorBase("invalid olderThan", []string{"olderThan must be a positive duration such as 48h"}))
+			return
+		}
+		olderThan = d
+	}
+
+	var actorID uuid.UUID
+	if claims, ok := middleware.GetUserClaims(c); ok {
+		actorID = claims.UserID
+	}
+
+	cancelled, err := h.service.ExpireStale(c.Request.Context(), actorID, olderThan)
+	if err != nil {
+		h.logger.Error("failed to expire stale orders", zap.Error(err))
+		respondError(c, err, "failed to expire orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("stale orders expired", gin.H{"cancelled": cancelled}))
+}
+
+func (h *OrderHandler) History(c *gin.Context) {
+	// @Summary Order status history
+	// @Description List the status transitions of an order, oldest first, with who made each change (owner or admin). FromStatus is empty for the creation entry and ActorID is null for changes made by the system
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id}/history [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	history, err := h.service.History(c.Request.Context(), claims.UserID, claims.Role == domain.RoleAdmin, id)
+	if err != nil {
+		h.logger.Warn("failed to load order history", zap.Error(err))
+		respondError(c, err, "failed to load order history")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("order history retrieved", toStatusChangeResponses(history)))
+}
+
+func (h *OrderHandler) Get(c *gin.Context) {
+	// @Summary Get order
+	// @Description Get an order with its items (owner or admin). Each item keeps the product name and price it was bought at; Product holds the product's current name and first image, and is null once the product has been deleted
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id} [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	order, err := h.service.Get(c.Request.Context(), claims.UserID, claims.Role == domain.RoleAdmin, id)
+	if err != nil {
+		h.logger.Warn("failed to load order", zap.Error(err))
+		respondError(c, err, "failed to load order")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("order retrieved", toOrderResponse(order)))
+}
+
+func (h *OrderHandler) GetByNumber(c *gin.Context) {
+	// @Summary Get order by number
+	// @Description Look up an order with its items by its human-friendly order number, e.g. ORD-2024-000123 (owner or admin). Matching ignores case
+	// @Tags Orders
+	// @Produce json
+	// @Param number path string true "Order number"
+	// @Success 200 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/by-number/{number} [get]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	order, err := h.service.GetByNumber(c.Request.Context(), claims.UserID, claims.Role == domain.RoleAdmin, c.Param("number"))
+	if err != nil {
+		h.logger.Warn("failed to load order by number", zap.Error(err))
+		respondError(c, err, "failed to load order")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("order retrieved", toOrderResponse(order)))
+}
+
+// Archive hides a completed or cancelled order from listings without deleting it (admin-only).
+func (h *OrderHandler) Archive(c *gin.Context) {
+	// @Summary Archive order
+	// @Description Hide a completed or cancelled order from order listings without deleting it; list with include_archived=true to see it again (admin only)
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id}/archive [post]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+
+	order, err := h.service.Archive(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Warn("failed to archive order", zap.Error(err))
+		respondError(c, err, "failed to archive order")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("order archived", toOrderResponse(order)))
+}
+
+// ApproveReview releases an order held for review as a pending order (admin-only).
+func (h *OrderHandler) ApproveReview(c *gin.Context) {
+	// @Summary Approve reviewed order
+	// @Description Release an order held as review_required; it becomes pending and keeps its stock (admin only)
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id}/approve [post]
+	h.review(c, true)
+}
+
+// RejectReview cancels an order held for review and restocks its items (admin-only).
+func (h *OrderHandler) RejectReview(c *gin.Context) {
+	// @Summary Reject reviewed order
+	// @Description Cancel an order held as review_required and return its stock (admin only)
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id}/reject [post]
+	h.review(c, false)
+}
+
+func (h *OrderHandler) review(c *gin.Context, approve bool) {
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+
+	review, message := h.service.RejectReview, "order rejected"
+	if approve {
+		review, message = h.service.ApproveReview, "order approved"
+	}
+	order, err := review(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		h.logger.Warn("failed to review order", zap.Error(err))
+		respondError(c, err, "failed to review order")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase(message, toOrderResponse(order)))
+}
+
+func (h *OrderHandler) Delete(c *gin.Context) {
+	// @Summary Delete order
+	// @Description Permanently delete an order and its items, archived or not (admin only). Cancelled orders are deleted as-is; other orders need force=true and are restocked first
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Order ID"
+	// @Param force query bool false "Restock and delete an order that is not cancelled"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /orders/{id} [delete]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid order id", []string{err.Error()}))
+		return
+	}
+	force := false
+	if raw := c.Query("force"); raw != "" {
+		force, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorBase("invalid force", []string{"force must be true or false"}))
+			return
+		}
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id, force); err != nil {
+		h.logger.Warn("failed to delete order", zap.Error(err))
+		respondError(c, err, "failed to delete order")
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessBase("orders retrieved", orders))
+	c.JSON(http.StatusOK, response.SuccessEmpty("order deleted"))
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -31,14 +32,80 @@ func (m *mockOrderService) Create(ctx context.Context, userID uuid.UUID, input o
 	return args.Get(0).(*domain.Order), args.Error(1)
 }
 
-func (m *mockOrderService) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Order, error) {
-	args := m.Called(ctx, userID)
+func (m *mockOrderService) Reorder(ctx context.Context, userID, orderID uuid.UUID) (*orderusecase.ReorderResult, error) {
+	args := m.Called(ctx, userID, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orderusecase.ReorderResult), args.Error(1)
+}
+
+func (m *mockOrderService) ListForUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error) {
+	args := m.Called(ctx, userID, status, includeArchived)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Order), args.Error(1)
 }
 
+func (m *mockOrderService) ExpireStale(ctx context.Context, actorID uuid.UUID, olderThan time.Duration) (int, error) {
+	args := m.Called(ctx, actorID, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockOrderService) GetByNumber(ctx context.Context, requesterID uuid.UUID, isAdmin bool, number string) (*domain.Order, error) {
+	args := m.Called(ctx, requesterID, isAdmin, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *mockOrderService) Get(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) (*domain.Order, error) {
+	args := m.Called(ctx, requesterID, isAdmin, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *mockOrderService) History(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	args := m.Called(ctx, requesterID, isAdmin, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OrderStatusChange), args.Error(1)
+}
+
+func (m *mockOrderService) Archive(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *mockOrderService) ApproveReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error) {
+	args := m.Called(ctx, actorID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *mockOrderService) RejectReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error) {
+	args := m.Called(ctx, actorID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *mockOrderService) Delete(ctx context.Context, id uuid.UUID, force bool) error {
+	args := m.Called(ctx, id, force)
+	return args.Error(0)
+}
+
 func TestOrderHandler_Create(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	logger := zap.NewNop()
@@ -86,7 +153,7 @@ func TestOrderHandler_List(t *testing.T) {
 
 		orders := []domain.Order{}
 
-		mockSvc.On("ListForUser", mock.Anything, mock.Anything).Return(orders, nil)
+		mockSvc.On("ListForUser", mock.Anything, mock.Anything, domain.OrderStatus(""), false).Return(orders, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
 		w := httptest.NewRecorder()
@@ -99,5 +166,196 @@ func TestOrderHandler_List(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("status filter", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		handler := NewOrderHandler(mockSvc, logger)
+
+		mockSvc.On("ListForUser", mock.Anything, mock.Anything, domain.OrderStatusCompleted, false).Return([]domain.Order{}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders?status=completed", nil)
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Role: domain.RoleUser})
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		handler := NewOrderHandler(mockSvc, logger)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders?status=compelted", nil)
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Role: domain.RoleUser})
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "pending, review_required, completed, cancelled")
+		mockSvc.AssertNotCalled(t, "ListForUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("include archived", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		handler := NewOrderHandler(mockSvc, logger)
+
+		mockSvc.On("ListForUser", mock.Anything, mock.Anything, domain.OrderStatus(""), true).Return([]domain.Order{}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders?include_archived=true", nil)
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Role: domain.RoleUser})
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestOrderHandler_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+	userID := uuid.New()
+
+	get := func(svc *mockOrderService, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+id, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Set("currentUser", middleware.UserClaims{UserID: userID, Role: domain.RoleUser})
+		NewOrderHandler(svc, logger).Get(c)
+		return w
+	}
+
+	t.Run("found", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("Get", mock.Anything, userID, false, id).Return(&domain.Order{ID: id, Items: []domain.OrderItem{{
+			ProductName: "Mug",
+			Product:     &domain.OrderItemProduct{Name: "Stoneware Mug", ImageURL: "https://cdn.example.com/mug.jpg"},
+		}}}, nil)
+
+		w := get(mockSvc, id.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Stoneware Mug")
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("Get", mock.Anything, userID, false, id).Return(nil, domain.ErrOrderNotFound)
+
+		w := get(mockSvc, id.String())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		w := get(new(mockOrderService), "nope")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestOrderHandler_Archive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	archive := func(svc *mockOrderService, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+id+"/archive", nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		NewOrderHandler(svc, logger).Archive(c)
+		return w
+	}
+
+	t.Run("archived", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		archivedAt := time.Now()
+		mockSvc.On("Archive", mock.Anything, id).Return(&domain.Order{ID: id, Status: domain.OrderStatusCompleted, ArchivedAt: &archivedAt}, nil)
+
+		w := archive(mockSvc, id.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("still open", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("Archive", mock.Anything, id).Return(nil, domain.ErrOrderNotArchivable)
+
+		w := archive(mockSvc, id.String())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
 }
 
+func TestOrderHandler_Review(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+	admin := uuid.New()
+
+	review := func(svc *mockOrderService, action, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/orders/"+id+"/"+action, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		c.Set("currentUser", middleware.UserClaims{UserID: admin, Role: domain.RoleAdmin})
+		h := NewOrderHandler(svc, logger)
+		if action == "approve" {
+			h.ApproveReview(c)
+		} else {
+			h.RejectReview(c)
+		}
+		return w
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("ApproveReview", mock.Anything, admin, id).Return(&domain.Order{ID: id, Status: domain.OrderStatusPending}, nil)
+
+		w := review(mockSvc, "approve", id.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("RejectReview", mock.Anything, admin, id).Return(&domain.Order{ID: id, Status: domain.OrderStatusCancelled}, nil)
+
+		w := review(mockSvc, "reject", id.String())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not under review", func(t *testing.T) {
+		mockSvc := new(mockOrderService)
+		id := uuid.New()
+		mockSvc.On("RejectReview", mock.Anything, admin, id).Return(nil, domain.ErrOrderNotUnderReview)
+
+		w := review(mockSvc, "reject", id.String())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		w := review(new(mockOrderService), "approve", "nope")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,7 +20,14 @@ import (
 type ProductHandler struct {
 	service      productusecase.Service
 	imageService productusecase.ImageService
+	views        productusecase.ViewService
+	cdnBase      *url.URL
+	imageMaxAge  time.Duration
+	suggestAge   time.Duration
+	categoryAge  time.Duration
 	logger       *zap.Logger
+	// skipTotals makes list responses uncounted unless the request asks for count=true.
+	skipTotals bool
 }
 
 func NewProductHandler(service productusecase.Service, logger *zap.Logger) *ProductHandler {
@@ -32,6 +42,43 @@ func (h *ProductHandler) WithImageService(img productusecase.ImageService) *Prod
 	return h
 }
 
+// WithViewService enables the view query parameter on List.
+func (h *ProductHandler) WithViewService(views productusecase.ViewService) *ProductHandler {
+	h.views = views
+	return h
+}
+
+// WithCDNBase serves image URLs from base instead of the host they were stored with; nil keeps them.
+func (h *ProductHandler) WithCDNBase(base *url.URL) *ProductHandler {
+	h.cdnBase = base
+	return h
+}
+
+// WithImageCacheMaxAge sets the Cache-Control max-age of image listings; zero makes clients revalidate every time.
+func (h *ProductHandler) WithImageCacheMaxAge(maxAge time.Duration) *ProductHandler {
+	h.imageMaxAge = maxAge
+	return h
+}
+
+// WithListTotals sets whether list responses count matching products when the request does not
+// pass count. Counting is on by default.
+func (h *ProductHandler) WithListTotals(count bool) *ProductHandler {
+	h.skipTotals = !count
+	return h
+}
+
+// WithSuggestCacheMaxAge sets the Cache-Control max-age of typeahead results.
+func (h *ProductHandler) WithSuggestCacheMaxAge(maxAge time.Duration) *ProductHandler {
+	h.suggestAge = maxAge
+	return h
+}
+
+// WithCategoryCacheMaxAge sets the Cache-Control max-age of the category list.
+func (h *ProductHandler) WithCategoryCacheMaxAge(maxAge time.Duration) *ProductHandler {
+	h.categoryAge = maxAge
+	return h
+}
+
 func (h *ProductHandler) Create(c *gin.Context) {
 	// @Summary Create product
 	// @Description Create a product (admin only)
@@ -57,11 +104,11 @@ func (h *ProductHandler) Create(c *gin.Context) {
 
 	product, err := h.service.Create(c.Request.Context(), claims.UserID, input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorBase("failed to create product", []string{err.Error()}))
+		respondError(c, err, "failed to create product")
 		return
 	}
 
-	c.JSON(http.StatusCreated, response.SuccessBase("product created", product))
+	c.JSON(http.StatusCreated, response.SuccessBase("product created", toProductResponse(rewriteProduct(h.cdnBase, product))))
 }
 
 func (h *ProductHandler) Update(c *gin.Context) {
@@ -91,15 +138,11 @@ func (h *ProductHandler) Update(c *gin.Context) {
 
 	product, err := h.service.Update(c.Request.Context(), id, input)
 	if err != nil {
-		if err == domain.ErrProductNotFound {
-			c.JSON(http.StatusNotFound, response.ErrorBase("product not found", []string{err.Error()}))
-			return
-		}
-		c.JSON(http.StatusBadRequest, response.ErrorBase("failed to update product", []string{err.Error()}))
+		respondError(c, err, "failed to update product")
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessBase("product updated", product))
+	c.JSON(http.StatusOK, response.SuccessBase("product updated", toProductResponse(rewriteProduct(h.cdnBase, product))))
 }
 
 func (h *ProductHandler) Delete(c *gin.Context) {
@@ -120,28 +163,23 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 	}
 
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		if err == domain.ErrProductNotFound {
-			c.JSON(http.StatusNotFound, response.ErrorBase("product not found", []string{err.Error()}))
-			return
-		}
-		if err == domain.ErrProductHasPendingOrders {
-			c.JSON(http.StatusBadRequest, response.ErrorBase("cannot delete product", []string{err.Error()}))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("failed to delete product", []string{err.Error()}))
+		respondError(c, err, "failed to delete product")
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessBase("product deleted", nil))
+	c.JSON(http.StatusOK, response.SuccessEmpty("product deleted"))
 }
 
 func (h *ProductHandler) Get(c *gin.Context) {
 	// @Summary Get product
-	// @Description Get product details (public)
+	// @Description Get product details (public). Responses carry a weak ETag and Last-Modified; send If-None-Match or If-Modified-Since to get 304 when unchanged
 	// @Tags Products
 	// @Produce json
 	// @Param id path string true "Product ID"
+	// @Param If-None-Match header string false "ETag from a previous response"
+	// @Param If-Modified-Since header string false "Last-Modified from a previous response; ignored when If-None-Match is sent"
 	// @Success 200 {object} response.Base
+	// @Success 304 "Not modified"
 	// @Failure 404 {object} response.Base
 	// @Router /products/{id} [get]
 	// this is also allowed for public access
@@ -153,15 +191,103 @@ func (h *ProductHandler) Get(c *gin.Context) {
 
 	product, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if err == domain.ErrProductNotFound {
-			c.JSON(http.StatusNotFound, response.ErrorBase("product not found", []string{err.Error()}))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("failed to fetch product", []string{err.Error()}))
+		respondError(c, err, "failed to fetch product")
+		return
+	}
+
+	respondVersioned(c, h.logger, response.SuccessBase("product retrieved", toProductResponse(rewriteProduct(h.cdnBase, product))), product.UpdatedAt)
+}
+
+func (h *ProductHandler) BatchGet(c *gin.Context) {
+	// @Summary Get products by ids
+	// @Description Resolve many products in one call, preserving request order (public)
+	// @Tags Products
+	// @Accept json
+	// @Produce json
+	// @Param payload body productusecase.BatchGetInput true "Product ids"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Router /products/batch [post]
+	var input productusecase.BatchGetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+
+	result, err := h.service.GetByIDs(c.Request.Context(), input.IDs)
+	if err != nil {
+		h.logger.Warn("failed to batch fetch products", zap.Error(err))
+		respondError(c, err, "failed to fetch products")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("products retrieved", batchGetResponse{
+		BatchGetResult: result,
+		Products:       toProductResponses(rewriteProducts(h.cdnBase, result.Products)),
+	}))
+}
+
+func (h *ProductHandler) CheckAvailability(c *gin.Context) {
+	// @Summary Check cart availability
+	// @Description Check a cart against current stock without placing an order (public). Returns per-item available quantity and whether it is in stock
+	// @Tags Products
+	// @Accept json
+	// @Produce json
+	// @Param payload body []productusecase.AvailabilityItemInput true "Cart lines"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Router /products/availability [post]
+	var items []productusecase.AvailabilityItemInput
+	if err := c.ShouldBindJSON(&items); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+
+	result, err := h.service.CheckAvailability(c.Request.Context(), items)
+	if err != nil {
+		h.logger.Warn("failed to check availability", zap.Error(err))
+		respondError(c, err, "failed to check availability")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("availability checked", result))
+}
+
+// Suggest answers typeahead lookups with a minimal id+name shape, separate from List.
+func (h *ProductHandler) Suggest(c *gin.Context) {
+	// @Summary Suggest products
+	// @Description Typeahead: id and name of up to product.suggest_limit products whose name starts with q (public)
+	// @Tags Products
+	// @Produce json
+	// @Param q query string true "Name prefix"
+	// @Success 200 {object} response.Base
+	// @Success 304 "Not modified"
+	// @Failure 400 {object} response.Base
+	// @Router /products/suggest [get]
+	suggestions, err := h.service.Suggest(c.Request.Context(), c.Query("q"))
+	if err != nil {
+		h.logger.Warn("product suggest failed", zap.Error(err))
+		respondError(c, err, "failed to suggest products")
 		return
 	}
+	respondCacheable(c, h.logger, response.SuccessBase("product suggestions", suggestions), h.suggestAge)
+}
 
-	c.JSON(http.StatusOK, response.SuccessBase("product retrieved", product))
+func (h *ProductHandler) ListCategories(c *gin.Context) {
+	// @Summary List product categories
+	// @Description Distinct categories that products are filed under, by name, each with its number of products (public)
+	// @Tags Products
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Success 304 "Not modified"
+	// @Router /products/categories [get]
+	categories, err := h.service.ListCategories(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list product categories", zap.Error(err))
+		respondError(c, err, "failed to list product categories")
+		return
+	}
+	respondCacheable(c, h.logger, response.SuccessBase("product categories retrieved", categories), h.categoryAge)
 }
 
 func (h *ProductHandler) List(c *gin.Context) {
@@ -172,33 +298,170 @@ func (h *ProductHandler) List(c *gin.Context) {
 	// @Param page query int false "Page number"
 	// @Param limit query int false "Page size"
 	// @Param search query string false "Search term"
+	// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+	// @Param view query string false "Saved view ID; its filter fills parameters not given (admin only)"
+	// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+	// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+	// @Param created_from query string false "Only products created at or after this time: RFC 3339 or YYYY-MM-DD (start of day, UTC)"
+	// @Param created_to query string false "Only products created at or before this time: RFC 3339 or YYYY-MM-DD (whole day, UTC)"
 	// @Success 200 {object} response.Paginated
+	// @Failure 400 {object} response.Base
+	// @Failure 401 {object} response.Base
+	// @Failure 404 {object} response.Base
 	// @Router /products [get]
 	// this is also allowed for public access : it returns list of products
-	page := parseQueryInt(c, "page", 1)
-	pageSize := parseQueryInt(c, "limit", 10)
-	search := c.Query("search")
-
-	products, total, err := h.service.List(c.Request.Context(), productusecase.ListProductsInput{
-		Search:   search,
-		Page:     page,
-		PageSize: pageSize,
-	})
+	fields, err := parseProductFields(c.Query("fields"))
 	if err != nil {
-		h.logger.Error("failed to list products", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("failed to list products", []string{err.Error()}))
+		respondError(c, err, "invalid fields")
+		return
+	}
+	skipCount, err := h.skipCount(c)
+	if err != nil {
+		respondError(c, err, "invalid count")
 		return
 	}
+	createdFrom, err := parseCreatedBound(c.Query("created_from"), false)
+	if err != nil {
+		respondError(c, err, "invalid created_from")
+		return
+	}
+	createdTo, err := parseCreatedBound(c.Query("created_to"), true)
+	if err != nil {
+		respondError(c, err, "invalid created_to")
+		return
+	}
+	input := productusecase.ListProductsInput{
+		Search:      c.Query("search"),
+		Sort:        c.Query("sort"),
+		Page:        parseQueryInt(c, "page", 1),
+		PageSize:    parseQueryInt(c, "limit", 0),
+		SkipCount:   skipCount,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+	}
 
-	resp := response.SuccessPaginated(
-		"products retrieved",
-		products,
-		page,
-		pageSize,
-		total,
+	var (
+		products []domain.Product
+		listPage productusecase.ListPage
 	)
+	if viewParam := c.Query("view"); viewParam != "" && h.views != nil {
+		viewID, parseErr := uuid.Parse(viewParam)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorBase("invalid view id", []string{parseErr.Error()}))
+			return
+		}
+		claims, ok := middleware.GetUserClaims(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"saved views require authentication"}))
+			return
+		}
+		var categoryID uuid.UUID
+		input, categoryID, err = h.views.Apply(c.Request.Context(), claims.UserID, viewID, input)
+		if err == nil && categoryID != uuid.Nil {
+			products, listPage, err = h.service.ListByCategory(c.Request.Context(), categoryID, input)
+		} else if err == nil {
+			products, listPage, err = h.service.List(c.Request.Context(), input)
+		}
+	} else {
+		products, listPage, err = h.service.List(c.Request.Context(), input)
+	}
+	if err != nil {
+		h.logger.Error("failed to list products", zap.Error(err))
+		respondError(c, err, "failed to list products")
+		return
+	}
+
+	c.JSON(http.StatusOK, productPage(projectProducts(rewriteProducts(h.cdnBase, products), fields), listPage, input.SkipCount))
+}
+
+// skipCount reads the count query parameter, falling back to the configured default.
+func (h *ProductHandler) skipCount(c *gin.Context) (bool, error) {
+	raw := c.Query("count")
+	if raw == "" {
+		return h.skipTotals, nil
+	}
+	count, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, domain.NewValidationError("count must be true or false")
+	}
+	return !count, nil
+}
+
+// parseCreatedBound parses a created_from or created_to value, either RFC 3339 or a UTC date. A
+// date used as the upper bound covers the whole day. An empty value returns the zero time.
+func parseCreatedBound(raw string, endOfDay bool) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError("%q is not an RFC 3339 time or YYYY-MM-DD date", raw)
+	}
+	if endOfDay {
+		// postgres keeps microseconds, so this is the last instant it can store for that day
+		return day.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+	}
+	return day, nil
+}
 
-	c.JSON(http.StatusOK, resp)
+// productPage builds a product list response from the page the service actually returned. An
+// uncounted total (see ListProductsInput.SkipCount) only tells whether a next page exists, so the
+// totals are left out.
+func productPage(products interface{}, page productusecase.ListPage, skipCount bool) response.Paginated {
+	if skipCount {
+		return response.SuccessPage("products retrieved", products, page.Page, page.PageSize, page.HasNext())
+	}
+	return response.SuccessPaginated("products retrieved", products, page.Page, page.PageSize, page.Total)
+}
+
+func (h *ProductHandler) ListByCategory(c *gin.Context) {
+	// @Summary List products in a category
+	// @Description List products of a category with pagination (public)
+	// @Tags Products
+	// @Produce json
+	// @Param id path string true "Category ID"
+	// @Param page query int false "Page number"
+	// @Param limit query int false "Page size"
+	// @Param search query string false "Search term"
+	// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+	// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+	// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+	// @Success 200 {object} response.Paginated
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Router /categories/{id}/products [get]
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid category id", []string{err.Error()}))
+		return
+	}
+	fields, err := parseProductFields(c.Query("fields"))
+	if err != nil {
+		respondError(c, err, "invalid fields")
+		return
+	}
+	skipCount, err := h.skipCount(c)
+	if err != nil {
+		respondError(c, err, "invalid count")
+		return
+	}
+	products, listPage, err := h.service.ListByCategory(c.Request.Context(), categoryID, productusecase.ListProductsInput{
+		Search:    c.Query("search"),
+		Sort:      c.Query("sort"),
+		Page:      parseQueryInt(c, "page", 1),
+		PageSize:  parseQueryInt(c, "limit", 10),
+		SkipCount: skipCount,
+	})
+	if err != nil {
+		h.logger.Warn("failed to list category products", zap.Error(err))
+		respondError(c, err, "failed to list products")
+		return
+	}
+
+	c.JSON(http.StatusOK, productPage(projectProducts(rewriteProducts(h.cdnBase, products), fields), listPage, skipCount))
 }
 
 func parseQueryInt(c *gin.Context, key string, defaultValue int) int {
@@ -213,6 +476,155 @@ func parseQueryInt(c *gin.Context, key string, defaultValue int) int {
 	return parsed
 }
 
+func (h *ProductHandler) CategoryCounts(c *gin.Context) {
+	// @Summary Product counts per category
+	// @Description Number of products in each category, largest first (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/products/category-counts [get]
+	counts, err := h.service.CountByCategory(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to count products by category", zap.Error(err))
+		respondError(c, err, "failed to count products by category")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessBase("category counts retrieved", counts))
+}
+
+// Export streams the whole catalog as a JSON array, writing and flushing one batch at a time so the
+// response never holds every product in memory (admin-only).
+func (h *ProductHandler) Export(c *gin.Context) {
+	// @Summary Export products
+	// @Description Stream every product as a JSON array, read from the database in batches (admin only)
+	// @Tags Admin
+	// @Produce json
+	// @Success 200 {array} domain.Product
+	// @Security BearerAuth
+	// @Router /admin/products/export [get]
+	opened := false
+	open := func() error {
+		opened = true
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="products.json"`)
+		c.Header("Cache-Control", "no-store")
+		c.Status(http.StatusOK)
+		_, err := c.Writer.WriteString("[")
+		return err
+	}
+
+	exported := 0
+	err := h.service.Export(c.Request.Context(), func(batch []domain.Product) error {
+		if !opened {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		batch = rewriteProducts(h.cdnBase, batch)
+		for i := range batch {
+			item, err := json.Marshal(toProductResponse(&batch[i]))
+			if err != nil {
+				return err
+			}
+			if exported > 0 {
+				if _, err := c.Writer.WriteString(","); err != nil {
+					return err
+				}
+			}
+			if _, err := c.Writer.Write(item); err != nil {
+				return err
+			}
+			exported++
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if !opened {
+			h.logger.Error("failed to export products", zap.Error(err))
+			respondError(c, err, "failed to export products")
+			return
+		}
+		// The status line is already sent; leaving the array unterminated lets clients detect the failure.
+		h.logger.Error("product export aborted mid-stream", zap.Int("exported", exported), zap.Error(err))
+		return
+	}
+
+	if !opened {
+		if err := open(); err != nil {
+			return
+		}
+	}
+	_, _ = c.Writer.WriteString("]")
+}
+
+// ListImages returns a product's images with cache validators so storefronts can revalidate cheaply.
+func (h *ProductHandler) ListImages(c *gin.Context) {
+	// @Summary List product images
+	// @Description A product's stored images (public). Responses carry an ETag and Cache-Control max-age; send If-None-Match to get 304 when unchanged
+	// @Tags Products
+	// @Produce json
+	// @Param id path string true "Product ID"
+	// @Param If-None-Match header string false "ETag from a previous response"
+	// @Success 200 {object} response.Base
+	// @Success 304 "Not modified"
+	// @Failure 400 {object} response.Base
+	// @Router /products/{id}/images [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorBase("image service not configured", []string{}))
+		return
+	}
+	images, err := h.imageService.ListImages(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Warn("list product images failed", zap.Error(err))
+		respondError(c, err, "failed to list product images")
+		return
+	}
+	if images == nil {
+		images = []domain.ProductImage{}
+	}
+	respondCacheable(c, h.logger, response.SuccessBase("product images retrieved", toAssetResponses(rewriteImages(h.cdnBase, images))), h.imageMaxAge)
+}
+
+// SignedImageURLs returns time-limited signed delivery URLs for a product's images.
+func (h *ProductHandler) SignedImageURLs(c *gin.Context) {
+	// @Summary Signed image URLs
+	// @Description Time-limited signed delivery URLs for a product's images (authenticated)
+	// @Tags Products
+	// @Produce json
+	// @Security BearerAuth
+	// @Param id path string true "Product ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 401 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Router /products/{id}/images/signed [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil || !h.imageService.Enabled() {
+		respondError(c, domain.ErrImagesDisabled, "image uploads are disabled")
+		return
+	}
+	urls, err := h.imageService.SignedURLs(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Warn("sign image urls failed", zap.Error(err))
+		respondError(c, err, "failed to sign image urls")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response.SuccessBase("signed image urls", toSignedImageURLResponses(urls)))
+}
+
 func (h *ProductHandler) UploadImages(c *gin.Context) {
 	// @Summary Upload product images
 	// @Description Upload up to 4 images for a product (admin only)
@@ -221,7 +633,11 @@ func (h *ProductHandler) UploadImages(c *gin.Context) {
 	// @Produce json
 	// @Param id path string true "Product ID"
 	// @Param files formData file true "Image files" collectionFormat(multi)
+	// @Param altText formData []string false "Alt text per file, in file order" collectionFormat(multi)
+	// @Param caption formData []string false "Caption per file, in file order" collectionFormat(multi)
 	// @Success 201 {object} response.Base
+	// @Failure 413 {object} response.Base
+	// @Failure 503 {object} response.Base
 	// @Security BearerAuth
 	// @Router /products/{id}/images [post]
 	id, err := uuid.Parse(c.Param("id"))
@@ -229,8 +645,8 @@ func (h *ProductHandler) UploadImages(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
 		return
 	}
-	if h.imageService == nil {
-		c.JSON(http.StatusInternalServerError, response.ErrorBase("image service not configured", []string{}))
+	if h.imageService == nil || !h.imageService.Enabled() {
+		respondError(c, domain.ErrImagesDisabled, "image uploads are disabled")
 		return
 	}
 	claims, ok := middleware.GetUserClaims(c)
@@ -252,10 +668,255 @@ func (h *ProductHandler) UploadImages(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, response.ErrorBase("maximum 4 images allowed", []string{}))
 		return
 	}
-	uploaded, err := h.imageService.UploadImages(c.Request.Context(), id, files)
+	uploaded, err := h.imageService.UploadImages(c.Request.Context(), id, files, imageMetadata(form.Value["altText"], form.Value["caption"]))
+	if err != nil {
+		respondError(c, err, "failed to upload images")
+		return
+	}
+	c.JSON(http.StatusCreated, response.SuccessBase("images uploaded", toAssetResponses(rewriteImages(h.cdnBase, uploaded))))
+}
+
+// UploadDocuments attaches non-image files such as spec sheets to a product (admin-only).
+func (h *ProductHandler) UploadDocuments(c *gin.Context) {
+	// @Summary Upload product documents
+	// @Description Upload up to 10 documents (spec sheets, manuals) for a product; they are listed apart from its images (admin only)
+	// @Tags Products
+	// @Accept multipart/form-data
+	// @Produce json
+	// @Param id path string true "Product ID"
+	// @Param files formData file true "Document files" collectionFormat(multi)
+	// @Success 201 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 413 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Security BearerAuth
+	// @Router /products/{id}/documents [post]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil || !h.imageService.Enabled() {
+		respondError(c, domain.ErrImagesDisabled, "document uploads are disabled")
+		return
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid multipart form", []string{err.Error()}))
+		return
+	}
+	files := form.File["files"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("no files uploaded", []string{}))
+		return
+	}
+	uploaded, err := h.imageService.UploadDocuments(c.Request.Context(), id, files)
+	if err != nil {
+		respondError(c, err, "failed to upload documents")
+		return
+	}
+	c.JSON(http.StatusCreated, response.SuccessBase("documents uploaded", toAssetResponses(uploaded)))
+}
+
+// ListDocuments returns a product's documents; its images are not included.
+func (h *ProductHandler) ListDocuments(c *gin.Context) {
+	// @Summary List product documents
+	// @Description A product's documents such as spec sheets and manuals (public)
+	// @Tags Products
+	// @Produce json
+	// @Param id path string true "Product ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Router /products/{id}/documents [get]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil {
+		respondError(c, domain.ErrImagesDisabled, "image uploads are disabled")
+		return
+	}
+	docs, err := h.imageService.ListDocuments(c.Request.Context(), id)
+	if err != nil {
+		h.logger.Warn("list product documents failed", zap.Error(err))
+		respondError(c, err, "failed to list product documents")
+		return
+	}
+	if docs == nil {
+		docs = []domain.ProductAsset{}
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("product documents retrieved", toAssetResponses(docs)))
+}
+
+// imageMetadata pairs the repeated altText and caption form fields by index.
+func imageMetadata(altTexts, captions []string) []productusecase.ImageMetadata {
+	n := len(altTexts)
+	if len(captions) > n {
+		n = len(captions)
+	}
+	meta := make([]productusecase.ImageMetadata, n)
+	for i := range meta {
+		if i < len(altTexts) {
+			meta[i].AltText = altTexts[i]
+		}
+		if i < len(captions) {
+			meta[i].Caption = captions[i]
+		}
+	}
+	return meta
+}
+
+// UpdateImage edits the alt text and caption of one product image (admin-only).
+func (h *ProductHandler) UpdateImage(c *gin.Context) {
+	// @Summary Update product image metadata
+	// @Description Edit an image's alt text and caption; omitted fields are left unchanged (admin only)
+	// @Tags Products
+	// @Accept json
+	// @Produce json
+	// @Param id path string true "Product ID"
+	// @Param imageId path string true "Image ID"
+	// @Param payload body productusecase.UpdateImageInput true "Image metadata"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /products/{id}/images/{imageId} [patch]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	imageID, err := uuid.Parse(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid image id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorBase("image service not configured", []string{}))
+		return
+	}
+	var input productusecase.UpdateImageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	img, err := h.imageService.UpdateImage(c.Request.Context(), id, imageID, input)
+	if err != nil {
+		respondError(c, err, "failed to update image")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("image updated", toAssetResponse(rewriteImages(h.cdnBase, []domain.ProductImage{*img})[0])))
+}
+
+// DeleteImages removes several images of one product (admin-only).
+func (h *ProductHandler) DeleteImages(c *gin.Context) {
+	// @Summary Delete product images
+	// @Description Delete several images of a product at once (admin only). Ids that are not images of the product are skipped and listed in skipped; the stored files are removed from Cloudinary after the rows are deleted
+	// @Tags Products
+	// @Accept json
+	// @Produce json
+	// @Param id path string true "Product ID"
+	// @Param payload body productusecase.DeleteImagesInput true "Image ids"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Security BearerAuth
+	// @Router /products/{id}/images [delete]
+	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorBase("failed to upload images", []string{err.Error()}))
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid product id", []string{err.Error()}))
+		return
+	}
+	if h.imageService == nil || !h.imageService.Enabled() {
+		respondError(c, domain.ErrImagesDisabled, "image uploads are disabled")
 		return
 	}
-	c.JSON(http.StatusCreated, response.SuccessBase("images uploaded", uploaded))
+	var input productusecase.DeleteImagesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	result, err := h.imageService.DeleteImages(c.Request.Context(), id, input.IDs)
+	if err != nil {
+		h.logger.Warn("failed to delete product images", zap.Error(err))
+		respondError(c, err, "failed to delete images")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("images deleted", result))
+}
+
+func (h *ProductHandler) UploadBulkImages(c *gin.Context) {
+	// @Summary Bulk upload product images
+	// @Description Upload images for multiple products in one request (admin only). The manifest is a JSON array of product ids matching the order of files. Each product succeeds or fails on its own: 201 when all succeed, 207 when some fail, 422 with the per-product results in details when none does
+	// @Tags Products
+	// @Accept multipart/form-data
+	// @Produce json
+	// @Param manifest formData string true "JSON array of product ids, one per file"
+	// @Param files formData file true "Image files" collectionFormat(multi)
+	// @Success 201 {object} response.Base
+	// @Success 207 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 413 {object} response.Base
+	// @Failure 422 {object} response.Base
+	// @Failure 503 {object} response.Base
+	// @Security BearerAuth
+	// @Router /products/images/bulk [post]
+	if h.imageService == nil || !h.imageService.Enabled() {
+		respondError(c, domain.ErrImagesDisabled, "image uploads are disabled")
+		return
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok || claims.Role != domain.RoleAdmin {
+		c.JSON(http.StatusForbidden, response.ErrorBase("forbidden", []string{"admin role required"}))
+		return
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid multipart form", []string{err.Error()}))
+		return
+	}
+	files := form.File["files"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("no files uploaded", []string{}))
+		return
+	}
+	var manifest []uuid.UUID
+	if err := json.Unmarshal([]byte(c.PostForm("manifest")), &manifest); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid manifest", []string{err.Error()}))
+		return
+	}
+	if len(manifest) != len(files) {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid manifest", []string{"manifest must list one product id per file"}))
+		return
+	}
+
+	uploads := make([]productusecase.BulkImageUpload, len(files))
+	for i, fh := range files {
+		uploads[i] = productusecase.BulkImageUpload{ProductID: manifest[i], File: fh}
+	}
+	results, err := h.imageService.UploadBulk(c.Request.Context(), uploads)
+	if err != nil {
+		respondError(c, err, "failed to upload images")
+		return
+	}
+	results = rewriteBulkResults(h.cdnBase, results)
+	succeeded := 0
+	for _, result := range results {
+		if result.Error == "" {
+			succeeded++
+		}
+	}
+	switch {
+	case succeeded == len(results):
+		c.JSON(http.StatusCreated, response.SuccessBase("images uploaded", toBulkUploadResponses(results)))
+	case succeeded > 0:
+		c.JSON(http.StatusMultiStatus, response.SuccessBase("images uploaded for some products", toBulkUploadResponses(results)))
+	default:
+		resp := response.ErrorCode("bulk_upload_failed", "no images uploaded", nil)
+		resp.Details = results
+		c.JSON(http.StatusUnprocessableEntity, resp)
+	}
 }
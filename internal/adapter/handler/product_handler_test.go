@@ -1,19 +1,30 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
 	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
 	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	"github.com/minilik/ecommerce/pkg/clock"
 )
 
 type mockProductService struct {
@@ -49,12 +60,71 @@ func (m *mockProductService) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
-func (m *mockProductService) List(ctx context.Context, input productusecase.ListProductsInput) ([]domain.Product, int64, error) {
+func (m *mockProductService) GetByIDs(ctx context.Context, ids []uuid.UUID) (*productusecase.BatchGetResult, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productusecase.BatchGetResult), args.Error(1)
+}
+
+func (m *mockProductService) CheckAvailability(ctx context.Context, items []productusecase.AvailabilityItemInput) (*productusecase.AvailabilityResult, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*productusecase.AvailabilityResult), args.Error(1)
+}
+
+func (m *mockProductService) Suggest(ctx context.Context, query string) ([]domain.ProductSuggestion, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProductSuggestion), args.Error(1)
+}
+
+func (m *mockProductService) ListCategories(ctx context.Context) ([]domain.CategoryCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CategoryCount), args.Error(1)
+}
+
+func (m *mockProductService) List(ctx context.Context, input productusecase.ListProductsInput) ([]domain.Product, productusecase.ListPage, error) {
 	args := m.Called(ctx, input)
 	if args.Get(0) == nil {
-		return nil, 0, args.Error(2)
+		return nil, productusecase.ListPage{}, args.Error(2)
+	}
+	return args.Get(0).([]domain.Product), args.Get(1).(productusecase.ListPage), args.Error(2)
+}
+
+func (m *mockProductService) ListByCategory(ctx context.Context, categoryID uuid.UUID, input productusecase.ListProductsInput) ([]domain.Product, productusecase.ListPage, error) {
+	args := m.Called(ctx, categoryID, input)
+	if args.Get(0) == nil {
+		return nil, productusecase.ListPage{}, args.Error(2)
 	}
-	return args.Get(0).([]domain.Product), args.Get(1).(int64), args.Error(2)
+	return args.Get(0).([]domain.Product), args.Get(1).(productusecase.ListPage), args.Error(2)
+}
+
+func (m *mockProductService) CountByCategory(ctx context.Context) ([]domain.CategoryCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CategoryCount), args.Error(1)
+}
+
+// Export hands each configured batch to emit, then returns the configured error.
+func (m *mockProductService) Export(ctx context.Context, emit func([]domain.Product) error) error {
+	args := m.Called(ctx)
+	for _, batch := range args.Get(0).([][]domain.Product) {
+		if err := emit(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
 }
 
 func TestProductHandler_List(t *testing.T) {
@@ -67,9 +137,9 @@ func TestProductHandler_List(t *testing.T) {
 
 		input := productusecase.ListProductsInput{Page: 1, PageSize: 10}
 		products := []domain.Product{}
-		total := int64(0)
+		page := productusecase.ListPage{Page: 1, PageSize: 10}
 
-		mockSvc.On("List", mock.Anything, input).Return(products, total, nil)
+		mockSvc.On("List", mock.Anything, input).Return(products, page, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/products?page=1&limit=10", nil)
 		w := httptest.NewRecorder()
@@ -81,5 +151,395 @@ func TestProductHandler_List(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("fields projects each product", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		id := uuid.New()
+		products := []domain.Product{{ID: id, Name: "Mug", Price: 8.5, Stock: 3, Description: "Stoneware", ExternalID: "sku-42"}}
+		mockSvc.On("List", mock.Anything, productusecase.ListProductsInput{Page: 1}).Return(products, productusecase.ListPage{Page: 1, PageSize: 10, Total: 1}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?fields=id,%20Name,price,externalId", nil)
+
+		handler.List(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data, 1)
+		assert.Equal(t, map[string]interface{}{"ID": id.String(), "Name": "Mug", "Price": 8.5, "externalId": "sku-42"}, body.Data[0])
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("created range parses dates and times", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		input := productusecase.ListProductsInput{
+			Page:        1,
+			CreatedFrom: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			CreatedTo:   time.Date(2025, 1, 31, 23, 59, 59, 999999000, time.UTC),
+		}
+		mockSvc.On("List", mock.Anything, input).Return([]domain.Product{}, productusecase.ListPage{Page: 1, PageSize: 10}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?created_from=2025-01-02T03:04:05Z&created_to=2025-01-31", nil)
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("invalid created_from", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?created_from=yesterday", nil)
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("count=false omits totals", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		products := []domain.Product{{ID: uuid.New()}, {ID: uuid.New()}}
+		mockSvc.On("List", mock.Anything, productusecase.ListProductsInput{Page: 1, PageSize: 2, SkipCount: true}).Return(products, productusecase.ListPage{Page: 1, PageSize: 2, Total: 3}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?limit=2&count=false", nil)
+
+		handler.List(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.NotContains(t, body, "totalPages")
+		assert.NotContains(t, body, "totalProducts")
+		assert.Equal(t, true, body["hasNext"])
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("unknown field is rejected", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?fields=id,password", nil)
+
+		handler.List(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "password")
+		mockSvc.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProductHandler_Delete(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := zap.NewNop()
+
+	t.Run("success returns empty data object", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		id := uuid.New()
+		mockSvc.On("Delete", mock.Anything, id).Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/products/"+id.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+		handler.Delete(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"success":true,"message":"product deleted","data":{}}`, w.Body.String())
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not found omits data", func(t *testing.T) {
+		mockSvc := new(mockProductService)
+		handler := NewProductHandler(mockSvc, logger)
+
+		id := uuid.New()
+		mockSvc.On("Delete", mock.Anything, id).Return(domain.ErrProductNotFound)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/products/"+id.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: id.String()}}
+
+		handler.Delete(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), `"data"`)
+	})
+}
+
+func TestProductHandler_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	export := func(batches [][]domain.Product, err error) *httptest.ResponseRecorder {
+		mockSvc := new(mockProductService)
+		mockSvc.On("Export", mock.Anything).Return(batches, err)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/products/export", nil)
+		NewProductHandler(mockSvc, zap.NewNop()).Export(c)
+		return w
+	}
+
+	t.Run("streams every batch as one array", func(t *testing.T) {
+		batches := [][]domain.Product{
+			{{ID: uuid.New(), Name: "A"}, {ID: uuid.New(), Name: "B"}},
+			{{ID: uuid.New(), Name: "C"}},
+		}
+		w := export(batches, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "products.json")
+		assert.True(t, w.Flushed)
+		var products []domain.Product
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+		require.Len(t, products, 3)
+		assert.Equal(t, []string{"A", "B", "C"}, []string{products[0].Name, products[1].Name, products[2].Name})
+	})
+
+	t.Run("empty catalog", func(t *testing.T) {
+		w := export(nil, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("error before first batch", func(t *testing.T) {
+		w := export(nil, errors.New("db down"))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("error mid-stream leaves array open", func(t *testing.T) {
+		w := export([][]domain.Product{{{ID: uuid.New(), Name: "A"}}}, errors.New("db down"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var products []domain.Product
+		assert.Error(t, json.Unmarshal(w.Body.Bytes(), &products))
+	})
+}
+
+// outageCache fails every operation, like a remote cache that is down.
+type outageCache struct{}
+
+func (outageCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (outageCache) Set(ctx context.Context, key string, value interface{}) error {
+	return errors.New("connection refused")
+}
+func (outageCache) Delete(ctx context.Context, key string) error {
+	return errors.New("connection refused")
+}
+func (outageCache) Flush(ctx context.Context) (int, error) {
+	return 0, errors.New("connection refused")
+}
+
+// countingProductRepo serves a single product and counts reads.
+type countingProductRepo struct {
+	repository.ProductRepository
+	product domain.Product
+	reads   int
+}
+
+func (r *countingProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	r.reads++
+	if id != r.product.ID {
+		return nil, domain.ErrProductNotFound
+	}
+	p := r.product
+	return &p, nil
+}
+
+func (r *countingProductRepo) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Product, int64, error) {
+	r.reads++
+	return []domain.Product{r.product}, 1, nil
+}
+
+func TestProductHandler_CacheOutage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &countingProductRepo{product: domain.Product{ID: uuid.New(), Name: "Lamp"}}
+	svc := productusecase.NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), outageCache{})
+	h := NewProductHandler(svc, zap.NewNop())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products/"+repo.product.ID.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: repo.product.ID.String()}}
+	h.Get(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+	h.List(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, 2, repo.reads, "both requests were served from the database")
 }
 
+// pagedProductRepo serves products in order, honouring the filter's limit and offset.
+type pagedProductRepo struct {
+	repository.ProductRepository
+	products []domain.Product
+}
+
+func (r *pagedProductRepo) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Product, int64, error) {
+	out := r.products[min(filter.Offset, len(r.products)):]
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, int64(len(r.products)), nil
+}
+
+func TestProductHandler_List_ClampedPaging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &pagedProductRepo{products: make([]domain.Product, 150)}
+	for i := range repo.products {
+		repo.products[i] = domain.Product{ID: uuid.New(), Name: fmt.Sprintf("Product %d", i)}
+	}
+	svc := productusecase.NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+	h := NewProductHandler(svc, zap.NewNop())
+
+	list := func(query string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/products?"+query, nil)
+		h.List(c)
+		require.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	body := list("limit=500&count=false")
+	assert.Equal(t, float64(100), body["pageSize"])
+	assert.Len(t, body["data"], 100)
+	assert.Equal(t, true, body["hasNext"], "only 100 of the 150 products fit on the first page")
+
+	body = list("page=2&limit=500&count=false")
+	assert.Equal(t, float64(2), body["currentPage"])
+	assert.Len(t, body["data"], 50)
+	assert.Equal(t, false, body["hasNext"])
+
+	body = list("page=0&limit=500")
+	assert.Equal(t, float64(1), body["currentPage"])
+	assert.Equal(t, float64(100), body["pageSize"])
+	assert.Equal(t, float64(2), body["totalPages"])
+}
+
+func TestProductHandler_UploadBulkImages_Status(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ok := productusecase.BulkUploadResult{ProductID: uuid.New(), Images: []domain.ProductImage{{URL: "https://res.cloudinary.com/demo/image/upload/lamp.jpg"}}}
+	failed := productusecase.BulkUploadResult{ProductID: uuid.New(), Error: "product not found"}
+
+	post := func(results ...productusecase.BulkUploadResult) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		manifest := make([]uuid.UUID, len(results))
+		for i, r := range results {
+			manifest[i] = r.ProductID
+			part, err := mw.CreateFormFile("files", "lamp.jpg")
+			require.NoError(t, err)
+			_, err = part.Write([]byte("img"))
+			require.NoError(t, err)
+		}
+		encoded, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		require.NoError(t, mw.WriteField("manifest", string(encoded)))
+		require.NoError(t, mw.Close())
+
+		h := NewProductHandler(nil, zap.NewNop()).WithImageService(&stubImageService{bulk: results})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/products/images/bulk", &body)
+		c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+		c.Set("currentUser", middleware.UserClaims{UserID: uuid.New(), Role: domain.RoleAdmin})
+		h.UploadBulkImages(c)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post(ok).Code)
+	assert.Equal(t, http.StatusMultiStatus, post(ok, failed).Code)
+
+	none := post(failed)
+	assert.Equal(t, http.StatusUnprocessableEntity, none.Code)
+	assert.Contains(t, none.Body.String(), `"code":"bulk_upload_failed"`)
+	assert.Contains(t, none.Body.String(), "product not found")
+}
+
+func TestProductHandler_UploadImages_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	productID := uuid.New()
+
+	for name, images := range map[string]productusecase.ImageService{
+		"no image service":        nil,
+		"uploader not configured": &stubImageService{disabled: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := NewProductHandler(nil, zap.NewNop()).WithImageService(images)
+			r := gin.New()
+			r.POST("/products/:id/images", h.UploadImages)
+			r.POST("/products/images/bulk", h.UploadBulkImages)
+
+			for _, path := range []string{"/products/" + productID.String() + "/images", "/products/images/bulk"} {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+				var body struct {
+					Code string `json:"code"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "images_disabled", body.Code, path)
+			}
+		})
+	}
+}
+
+func TestProductHandler_ListDocuments_NoImageService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/products/:id/documents", NewProductHandler(nil, zap.NewNop()).ListDocuments)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/"+uuid.NewString()+"/documents", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"images_disabled"`)
+}
+
+func TestProductHandler_DeleteImages_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewProductHandler(nil, zap.NewNop()).WithImageService(&stubImageService{disabled: true})
+	r := gin.New()
+	r.DELETE("/products/:id/images", h.DeleteImages)
+
+	req := httptest.NewRequest(http.MethodDelete, "/products/"+uuid.NewString()+"/images", bytes.NewBufferString(`{"ids":["`+uuid.NewString()+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"images_disabled"`)
+}
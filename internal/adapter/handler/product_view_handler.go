@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// ProductViewHandler serves the caller's saved product views.
+type ProductViewHandler struct {
+	views  productusecase.ViewService
+	logger *zap.Logger
+}
+
+func NewProductViewHandler(views productusecase.ViewService, logger *zap.Logger) *ProductViewHandler {
+	return &ProductViewHandler{views: views, logger: logger}
+}
+
+func (h *ProductViewHandler) List(c *gin.Context) {
+	// @Summary List saved product views
+	// @Description List the caller's saved product views (admin only)
+	// @Tags Product Views
+	// @Produce json
+	// @Success 200 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/product-views [get]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+	views, err := h.views.List(c.Request.Context(), claims.UserID)
+	if err != nil {
+		h.logger.Error("list product views failed", zap.Error(err))
+		respondError(c, err, "failed to list product views")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("product views retrieved", toProductViewResponses(views)))
+}
+
+func (h *ProductViewHandler) Get(c *gin.Context) {
+	// @Summary Get saved product view
+	// @Description Get one of the caller's saved product views (admin only)
+	// @Tags Product Views
+	// @Produce json
+	// @Param id path string true "View ID"
+	// @Success 200 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/product-views/{id} [get]
+	claims, id, ok := h.ownerAndID(c)
+	if !ok {
+		return
+	}
+	view, err := h.views.Get(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		respondError(c, err, "failed to fetch product view")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("product view retrieved", toProductViewResponse(view)))
+}
+
+func (h *ProductViewHandler) Create(c *gin.Context) {
+	// @Summary Save product view
+	// @Description Save a named product listing filter for later use with GET /products?view= (admin only)
+	// @Tags Product Views
+	// @Accept json
+	// @Produce json
+	// @Param payload body productusecase.SaveViewInput true "View"
+	// @Success 201 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/product-views [post]
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+	var input productusecase.SaveViewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	view, err := h.views.Create(c.Request.Context(), claims.UserID, input)
+	if err != nil {
+		h.logger.Warn("create product view failed", zap.Error(err))
+		respondError(c, err, "failed to save product view")
+		return
+	}
+	c.JSON(http.StatusCreated, response.SuccessBase("product view saved", toProductViewResponse(view)))
+}
+
+func (h *ProductViewHandler) Update(c *gin.Context) {
+	// @Summary Replace product view
+	// @Description Replace the name and filter of one of the caller's saved product views (admin only)
+	// @Tags Product Views
+	// @Accept json
+	// @Produce json
+	// @Param id path string true "View ID"
+	// @Param payload body productusecase.SaveViewInput true "View"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/product-views/{id} [put]
+	claims, id, ok := h.ownerAndID(c)
+	if !ok {
+		return
+	}
+	var input productusecase.SaveViewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	view, err := h.views.Update(c.Request.Context(), claims.UserID, id, input)
+	if err != nil {
+		h.logger.Warn("update product view failed", zap.Error(err))
+		respondError(c, err, "failed to update product view")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessBase("product view updated", toProductViewResponse(view)))
+}
+
+func (h *ProductViewHandler) Delete(c *gin.Context) {
+	// @Summary Delete product view
+	// @Description Delete one of the caller's saved product views (admin only)
+	// @Tags Product Views
+	// @Produce json
+	// @Param id path string true "View ID"
+	// @Success 200 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /admin/product-views/{id} [delete]
+	claims, id, ok := h.ownerAndID(c)
+	if !ok {
+		return
+	}
+	if err := h.views.Delete(c.Request.Context(), claims.UserID, id); err != nil {
+		respondError(c, err, "failed to delete product view")
+		return
+	}
+	c.JSON(http.StatusOK, response.SuccessEmpty("product view deleted"))
+}
+
+// ownerAndID reads the caller and the :id path parameter, writing the error response itself.
+func (h *ProductViewHandler) ownerAndID(c *gin.Context) (middleware.UserClaims, uuid.UUID, bool) {
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return claims, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid view id", []string{err.Error()}))
+		return claims, uuid.Nil, false
+	}
+	return claims, id, true
+}
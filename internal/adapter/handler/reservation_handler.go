@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
+	orderusecase "github.com/minilik/ecommerce/internal/usecase/order"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+type ReservationHandler struct {
+	service orderusecase.ReservationService
+	logger  *zap.Logger
+}
+
+func NewReservationHandler(service orderusecase.ReservationService, logger *zap.Logger) *ReservationHandler {
+	return &ReservationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type reserveRequest struct {
+	ProductID uuid.UUID `json:"productId" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required"`
+	// TTL is a Go duration such as 10m; empty uses the configured default.
+	TTL string `json:"ttl"`
+}
+
+func (h *ReservationHandler) Reserve(c *gin.Context) {
+	// @Summary Reserve stock
+	// @Description Hold stock of a product for the current user while they check out. Pass the reservation id to POST /orders to spend it; unspent reservations are released when they expire
+	// @Tags Orders
+	// @Accept json
+	// @Produce json
+	// @Param payload body handler.reserveRequest true "Reservation payload"
+	// @Success 201 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Security BearerAuth
+	// @Router /reservations [post]
+	var req reserveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid input", []string{err.Error()}))
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, response.ErrorBase("invalid ttl", []string{"ttl must be a positive duration such as 10m"}))
+			return
+		}
+		ttl = d
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	reservation, err := h.service.Reserve(c.Request.Context(), claims.UserID, req.ProductID, req.Quantity, ttl)
+	if err != nil {
+		h.logger.Warn("failed to reserve stock", zap.Error(err))
+		respondError(c, err, "failed to reserve stock")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.SuccessBase("stock reserved", toReservationResponse(reservation)))
+}
+
+func (h *ReservationHandler) Release(c *gin.Context) {
+	// @Summary Release reservation
+	// @Description Give the current user's reserved stock back before it expires
+	// @Tags Orders
+	// @Produce json
+	// @Param id path string true "Reservation ID"
+	// @Success 200 {object} response.Base
+	// @Failure 400 {object} response.Base
+	// @Failure 404 {object} response.Base
+	// @Failure 409 {object} response.Base
+	// @Security BearerAuth
+	// @Router /reservations/{id} [delete]
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorBase("invalid reservation id", []string{err.Error()}))
+		return
+	}
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.ErrorBase("unauthorized", []string{"authentication required"}))
+		return
+	}
+
+	if err := h.service.Release(c.Request.Context(), claims.UserID, id); err != nil {
+		h.logger.Warn("failed to release reservation", zap.Error(err))
+		respondError(c, err, "failed to release reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessEmpty("reservation released"))
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"github.com/minilik/ecommerce/internal/domain"
+	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	orderusecase "github.com/minilik/ecommerce/internal/usecase/order"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// The response types below embed a value and shadow its time fields with response.Time, so those
+// are written in server.timestamp_format while every other field keeps its own encoding. A field
+// of the outer struct wins over the embedded field of the same JSON name.
+
+type productResponse struct {
+	*domain.Product
+	Images    []assetResponse `json:"images,omitempty"`
+	CreatedAt response.Time
+	UpdatedAt response.Time
+}
+
+func toProductResponse(p *domain.Product) *productResponse {
+	if p == nil {
+		return nil
+	}
+	return &productResponse{
+		Product:   p,
+		Images:    toAssetResponses(p.Images),
+		CreatedAt: response.NewTime(p.CreatedAt),
+		UpdatedAt: response.NewTime(p.UpdatedAt),
+	}
+}
+
+func toProductResponses(products []domain.Product) []productResponse {
+	if products == nil {
+		return nil
+	}
+	out := make([]productResponse, len(products))
+	for i := range products {
+		out[i] = *toProductResponse(&products[i])
+	}
+	return out
+}
+
+type assetResponse struct {
+	domain.ProductAsset
+	CreatedAt response.Time
+}
+
+func toAssetResponse(asset domain.ProductAsset) assetResponse {
+	return assetResponse{ProductAsset: asset, CreatedAt: response.NewTime(asset.CreatedAt)}
+}
+
+// toAssetResponses keeps an empty slice empty rather than nil, so it is still written as [].
+func toAssetResponses(assets []domain.ProductAsset) []assetResponse {
+	if assets == nil {
+		return nil
+	}
+	out := make([]assetResponse, len(assets))
+	for i := range assets {
+		out[i] = toAssetResponse(assets[i])
+	}
+	return out
+}
+
+type batchGetResponse struct {
+	*productusecase.BatchGetResult
+	Products []productResponse `json:"products"`
+}
+
+type bulkUploadResponse struct {
+	productusecase.BulkUploadResult
+	Images []assetResponse `json:"images,omitempty"`
+}
+
+func toBulkUploadResponses(results []productusecase.BulkUploadResult) []bulkUploadResponse {
+	out := make([]bulkUploadResponse, len(results))
+	for i := range results {
+		out[i] = bulkUploadResponse{BulkUploadResult: results[i], Images: toAssetResponses(results[i].Images)}
+	}
+	return out
+}
+
+type signedImageURLResponse struct {
+	productusecase.SignedImageURL
+	ExpiresAt response.Time `json:"expiresAt"`
+}
+
+func toSignedImageURLResponses(urls []productusecase.SignedImageURL) []signedImageURLResponse {
+	out := make([]signedImageURLResponse, len(urls))
+	for i := range urls {
+		out[i] = signedImageURLResponse{SignedImageURL: urls[i], ExpiresAt: response.NewTime(urls[i].ExpiresAt)}
+	}
+	return out
+}
+
+type orderResponse struct {
+	*domain.Order
+	Items      []orderItemResponse
+	CreatedAt  response.Time
+	UpdatedAt  response.Time
+	ArchivedAt *response.Time
+}
+
+func toOrderResponse(o *domain.Order) *orderResponse {
+	if o == nil {
+		return nil
+	}
+	var items []orderItemResponse
+	if o.Items != nil {
+		items = make([]orderItemResponse, len(o.Items))
+		for i, item := range o.Items {
+			items[i] = orderItemResponse{
+				OrderItem: item,
+				CreatedAt: response.NewTime(item.CreatedAt),
+				UpdatedAt: response.NewTime(item.UpdatedAt),
+			}
+		}
+	}
+	return &orderResponse{
+		Order:      o,
+		Items:      items,
+		CreatedAt:  response.NewTime(o.CreatedAt),
+		UpdatedAt:  response.NewTime(o.UpdatedAt),
+		ArchivedAt: response.NewTimePtr(o.ArchivedAt),
+	}
+}
+
+func toOrderResponses(orders []domain.Order) []orderResponse {
+	if orders == nil {
+		return nil
+	}
+	out := make([]orderResponse, len(orders))
+	for i := range orders {
+		out[i] = *toOrderResponse(&orders[i])
+	}
+	return out
+}
+
+type orderItemResponse struct {
+	domain.OrderItem
+	CreatedAt response.Time
+	UpdatedAt response.Time
+}
+
+type reorderResponse struct {
+	*orderusecase.ReorderResult
+	Order *orderResponse `json:"order"`
+}
+
+type statusChangeResponse struct {
+	domain.OrderStatusChange
+	CreatedAt response.Time
+}
+
+func toStatusChangeResponses(history []domain.OrderStatusChange) []statusChangeResponse {
+	if history == nil {
+		return nil
+	}
+	out := make([]statusChangeResponse, len(history))
+	for i := range history {
+		out[i] = statusChangeResponse{OrderStatusChange: history[i], CreatedAt: response.NewTime(history[i].CreatedAt)}
+	}
+	return out
+}
+
+type reservationResponse struct {
+	*domain.Reservation
+	ExpiresAt response.Time `json:"expiresAt"`
+	CreatedAt response.Time `json:"createdAt"`
+	UpdatedAt response.Time `json:"updatedAt"`
+}
+
+func toReservationResponse(r *domain.Reservation) *reservationResponse {
+	if r == nil {
+		return nil
+	}
+	return &reservationResponse{
+		Reservation: r,
+		ExpiresAt:   response.NewTime(r.ExpiresAt),
+		CreatedAt:   response.NewTime(r.CreatedAt),
+		UpdatedAt:   response.NewTime(r.UpdatedAt),
+	}
+}
+
+type productViewResponse struct {
+	*domain.ProductView
+	CreatedAt response.Time `json:"createdAt"`
+	UpdatedAt response.Time `json:"updatedAt"`
+}
+
+func toProductViewResponse(v *domain.ProductView) *productViewResponse {
+	if v == nil {
+		return nil
+	}
+	return &productViewResponse{
+		ProductView: v,
+		CreatedAt:   response.NewTime(v.CreatedAt),
+		UpdatedAt:   response.NewTime(v.UpdatedAt),
+	}
+}
+
+func toProductViewResponses(views []domain.ProductView) []productViewResponse {
+	if views == nil {
+		return nil
+	}
+	out := make([]productViewResponse, len(views))
+	for i := range views {
+		out[i] = *toProductViewResponse(&views[i])
+	}
+	return out
+}
+
+type authResponse struct {
+	*authusecase.AuthResponse
+	ExpiresAt response.Time `json:"expiresAt"`
+}
+
+type userResponse struct {
+	*authusecase.UserSummary
+	CreatedAt response.Time `json:"createdAt"`
+	UpdatedAt response.Time `json:"updatedAt"`
+}
+
+func toUserResponse(u *authusecase.UserSummary) *userResponse {
+	if u == nil {
+		return nil
+	}
+	return &userResponse{
+		UserSummary: u,
+		CreatedAt:   response.NewTime(u.CreatedAt),
+		UpdatedAt:   response.NewTime(u.UpdatedAt),
+	}
+}
+
+type emailVerificationResponse struct {
+	*authusecase.EmailVerificationResponse
+	ExpiresAt response.Time `json:"expiresAt"`
+}
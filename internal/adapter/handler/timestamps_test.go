@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+func TestProductResponse_TimestampFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, response.UseTimestampFormat(response.TimestampEpochMillis, ""))
+	t.Cleanup(func() { _ = response.UseTimestampFormat(response.TimestampRFC3339, "") })
+
+	created := time.Date(2024, 6, 1, 9, 0, 0, 123000000, time.UTC)
+	product := &domain.Product{
+		ID:        uuid.New(),
+		Name:      "Lamp",
+		Images:    []domain.ProductImage{{ID: uuid.New(), URL: "https://example.com/lamp.jpg", CreatedAt: created}},
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Second),
+	}
+	svc := new(mockProductService)
+	svc.On("GetByID", mock.Anything, product.ID).Return(product, nil)
+	r := gin.New()
+	r.GET("/products/:id", NewProductHandler(svc, zap.NewNop()).Get)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/"+product.ID.String(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"Name":"Lamp"`)
+	assert.Contains(t, body, `"CreatedAt":1717232400123`)
+	assert.Contains(t, body, `"UpdatedAt":1717232401123`)
+	assert.Contains(t, body, `"url":"https://example.com/lamp.jpg","altText":"","CreatedAt":1717232400123`)
+	assert.NotContains(t, body, "2024-06-01T")
+}
@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,29 +21,72 @@ type UserClaims struct {
 	UserID   uuid.UUID
 	Username string
 	Role     domain.Role
+	// Issuer, IssuedAt and ExpiresAt are copied from the token as-is; zero when the token omits them.
+	Issuer    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
 }
 
+// Default token header and scheme, as in "Authorization: Bearer <token>".
+const (
+	DefaultTokenHeader = "Authorization"
+	DefaultTokenScheme = "Bearer"
+)
+
+// RevocationCheck reports whether the user's token issued at issuedAt has been revoked, for
+// example by a later password change.
+type RevocationCheck func(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error)
+
 type AuthMiddleware struct {
-	logger *zap.Logger
-	jwt    jwtpkg.Manager
+	logger      *zap.Logger
+	jwt         jwtpkg.Manager
+	cookieName  string
+	tokenHeader string
+	tokenScheme string
+	revoked     RevocationCheck
 }
 
 func NewAuthMiddleware(logger *zap.Logger, jwt jwtpkg.Manager) *AuthMiddleware {
 	return &AuthMiddleware{
-		logger: logger,
-		jwt:    jwt,
+		logger:      logger,
+		jwt:         jwt,
+		tokenHeader: DefaultTokenHeader,
+		tokenScheme: DefaultTokenScheme,
+	}
+}
+
+// WithTokenHeader reads the token from header, prefixed with scheme (an empty scheme means the
+// header holds the bare token), for gateways that strip Authorization. "Authorization: Bearer"
+// is still accepted when the custom header is absent. An empty header keeps the default.
+func (a *AuthMiddleware) WithTokenHeader(header, scheme string) *AuthMiddleware {
+	if header != "" {
+		a.tokenHeader = header
+		a.tokenScheme = scheme
 	}
+	return a
+}
+
+// WithRevocationCheck rejects otherwise valid tokens that check reports as revoked. Without it
+// every unexpired token is accepted.
+func (a *AuthMiddleware) WithRevocationCheck(check RevocationCheck) *AuthMiddleware {
+	a.revoked = check
+	return a
+}
+
+// WithTokenCookie makes RequireAuth fall back to the named cookie when no Authorization header is sent.
+func (a *AuthMiddleware) WithTokenCookie(name string) *AuthMiddleware {
+	a.cookieName = name
+	return a
 }
 
 func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := extractToken(c.GetHeader("Authorization"))
+		token := a.token(c)
 		if token == "" {
 			c.JSON(http.StatusUnauthorized, response.ErrorBase("authorization token missing", []string{"authorization header missing"}))
 			c.Abort()
 			return
 		}
-		// TODO: add read token from cookie if not in header
 
 		claims, err := a.jwt.ParseToken(token)
 		if err != nil {
@@ -50,14 +95,35 @@ func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-
-		userClaims := UserClaims{
-			UserID:   claims.UserID,
-			Username: claims.Username,
-			Role:     domain.Role(claims.Role),
+		revoked, err := a.isRevoked(c, claims)
+		if err != nil {
+			a.logger.Error("failed to check token revocation", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, response.ErrorBase("internal server error", []string{}))
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, response.ErrorBase("invalid token", []string{"token has been revoked"}))
+			c.Abort()
+			return
 		}
 
-		c.Set(userContextKey, userClaims)
+		c.Set(userContextKey, userClaimsFrom(claims))
+		c.Next()
+	}
+}
+
+// OptionalAuth sets the user claims when a valid token is sent and otherwise lets the request
+// through anonymously, for public routes with extras for signed-in users.
+func (a *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := a.token(c); token != "" {
+			if claims, err := a.jwt.ParseToken(token); err == nil {
+				if revoked, err := a.isRevoked(c, claims); err == nil && !revoked {
+					c.Set(userContextKey, userClaimsFrom(claims))
+				}
+			}
+		}
 		c.Next()
 	}
 }
@@ -98,6 +164,33 @@ func (a *AuthMiddleware) RequireRoles(roles ...domain.Role) gin.HandlerFunc {
 	}
 }
 
+func (a *AuthMiddleware) isRevoked(c *gin.Context, claims *jwtpkg.Claims) (bool, error) {
+	if a.revoked == nil {
+		return false, nil
+	}
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	return a.revoked(c.Request.Context(), claims.UserID, issuedAt)
+}
+
+func userClaimsFrom(claims *jwtpkg.Claims) UserClaims {
+	userClaims := UserClaims{
+		UserID:   claims.UserID,
+		Username: claims.Username,
+		Role:     domain.Role(claims.Role),
+		Issuer:   claims.Issuer,
+	}
+	if claims.IssuedAt != nil {
+		userClaims.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		userClaims.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return userClaims
+}
+
 func GetUserClaims(c *gin.Context) (UserClaims, bool) {
 	value, exists := c.Get(userContextKey)
 	if !exists {
@@ -108,15 +201,34 @@ func GetUserClaims(c *gin.Context) (UserClaims, bool) {
 	return claims, ok
 }
 
-func extractToken(header string) string {
+// token finds the request's access token: the configured header first, then the standard
+// Authorization header, then the token cookie when one is configured.
+func (a *AuthMiddleware) token(c *gin.Context) string {
+	token := extractToken(c.GetHeader(a.tokenHeader), a.tokenScheme)
+	if token == "" && (!strings.EqualFold(a.tokenHeader, DefaultTokenHeader) || !strings.EqualFold(a.tokenScheme, DefaultTokenScheme)) {
+		token = extractToken(c.GetHeader(DefaultTokenHeader), DefaultTokenScheme)
+	}
+	if token == "" && a.cookieName != "" {
+		token, _ = c.Cookie(a.cookieName)
+	}
+	return token
+}
+
+// extractToken returns the token from a "<scheme> <token>" header value (scheme matched
+// case-insensitively), or the whole trimmed value when scheme is empty.
+func extractToken(header, scheme string) string {
+	header = strings.TrimSpace(header)
 	if header == "" {
 		return ""
 	}
+	if scheme == "" {
+		return header
+	}
 	parts := strings.SplitN(header, " ", 2)
 	if len(parts) != 2 {
 		return ""
 	}
-	if strings.ToLower(parts[0]) != "bearer" {
+	if !strings.EqualFold(parts[0], scheme) {
 		return ""
 	}
 	return strings.TrimSpace(parts[1])
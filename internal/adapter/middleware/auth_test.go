@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
+)
+
+func TestRequireAuth_TokenHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+	userID := uuid.New()
+	token, err := manager.GenerateAccessToken(userID, "alice", "user", time.Minute, "test")
+	require.NoError(t, err)
+
+	newRouter := func(a *AuthMiddleware) *gin.Engine {
+		r := gin.New()
+		r.GET("/me", a.RequireAuth(), func(c *gin.Context) {
+			claims, ok := GetUserClaims(c)
+			require.True(t, ok)
+			assert.Equal(t, userID, claims.UserID)
+			c.Status(http.StatusOK)
+		})
+		return r
+	}
+	serve := func(r *gin.Engine, header, value string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("default Authorization Bearer", func(t *testing.T) {
+		r := newRouter(NewAuthMiddleware(zap.NewNop(), manager))
+		assert.Equal(t, http.StatusOK, serve(r, "Authorization", "Bearer "+token))
+		assert.Equal(t, http.StatusOK, serve(r, "Authorization", "bearer "+token), "scheme is case-insensitive")
+		assert.Equal(t, http.StatusUnauthorized, serve(r, "Authorization", token), "scheme is required")
+		assert.Equal(t, http.StatusUnauthorized, serve(r, "X-Access-Token", token))
+	})
+
+	t.Run("custom header with a bare token", func(t *testing.T) {
+		r := newRouter(NewAuthMiddleware(zap.NewNop(), manager).WithTokenHeader("X-Access-Token", ""))
+		assert.Equal(t, http.StatusOK, serve(r, "X-Access-Token", token))
+		assert.Equal(t, http.StatusOK, serve(r, "Authorization", "Bearer "+token), "the standard header still works")
+		assert.Equal(t, http.StatusUnauthorized, serve(r, "", ""))
+	})
+
+	t.Run("custom header and scheme", func(t *testing.T) {
+		r := newRouter(NewAuthMiddleware(zap.NewNop(), manager).WithTokenHeader("X-Auth", "Token"))
+		assert.Equal(t, http.StatusOK, serve(r, "X-Auth", "Token "+token))
+		assert.Equal(t, http.StatusUnauthorized, serve(r, "X-Auth", "Bearer "+token))
+	})
+}
+
+func TestRequireAuth_RevocationCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+	userID := uuid.New()
+	token, err := manager.GenerateAccessToken(userID, "alice", "user", time.Minute, "test")
+	require.NoError(t, err)
+
+	serve := func(check RevocationCheck) int {
+		r := gin.New()
+		r.GET("/me", NewAuthMiddleware(zap.NewNop(), manager).WithRevocationCheck(check).RequireAuth(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(func(ctx context.Context, id uuid.UUID, issuedAt time.Time) (bool, error) {
+		assert.Equal(t, userID, id)
+		assert.False(t, issuedAt.IsZero())
+		return false, nil
+	}))
+	assert.Equal(t, http.StatusUnauthorized, serve(func(context.Context, uuid.UUID, time.Time) (bool, error) {
+		return true, nil
+	}))
+	assert.Equal(t, http.StatusInternalServerError, serve(func(context.Context, uuid.UUID, time.Time) (bool, error) {
+		return false, errors.New("database unavailable")
+	}))
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConcurrencyLimitMiddleware caps how many requests one client IP may have in flight at once. The
+// rate limiter counts requests as they arrive, so a client can stay within it while holding many
+// slow requests (e.g. uploads) open; this limit covers that case and is meant to run alongside it.
+type ConcurrencyLimitMiddleware struct {
+	shards [rateLimitShards]concurrencyShard
+	max    int
+}
+
+// concurrencyShard holds the in-flight counts of the clients whose IP hashes to it.
+type concurrencyShard struct {
+	mutex    sync.Mutex
+	inFlight map[string]int
+}
+
+// NewConcurrencyLimitMiddleware allows max requests in flight per client IP.
+func NewConcurrencyLimitMiddleware(max int) *ConcurrencyLimitMiddleware {
+	m := &ConcurrencyLimitMiddleware{max: max}
+	for i := range m.shards {
+		m.shards[i].inFlight = make(map[string]int)
+	}
+	return m
+}
+
+// Limit rejects a request with 429 while its client already has max requests in flight. The slot
+// is released when the rest of the chain returns, panics included.
+func (m *ConcurrencyLimitMiddleware) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientIP := c.ClientIP()
+		if !m.acquire(clientIP) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Concurrency limit exceeded",
+				"message": "Too many requests in progress, please wait for one to finish",
+			})
+			c.Abort()
+			return
+		}
+		defer m.release(clientIP)
+		c.Next()
+	}
+}
+
+func (m *ConcurrencyLimitMiddleware) acquire(clientIP string) bool {
+	shard := m.shard(clientIP)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	if shard.inFlight[clientIP] >= m.max {
+		return false
+	}
+	shard.inFlight[clientIP]++
+	return true
+}
+
+// release frees a slot, forgetting clients with nothing in flight so the map does not grow.
+func (m *ConcurrencyLimitMiddleware) release(clientIP string) {
+	shard := m.shard(clientIP)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	if shard.inFlight[clientIP] <= 1 {
+		delete(shard.inFlight, clientIP)
+		return
+	}
+	shard.inFlight[clientIP]--
+}
+
+func (m *ConcurrencyLimitMiddleware) shard(clientIP string) *concurrencyShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(clientIP))
+	return &m.shards[h.Sum32()%rateLimitShards]
+}
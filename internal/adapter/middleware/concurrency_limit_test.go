@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit_RejectsExcessInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const max, clients = 3, 8
+
+	started := make(chan struct{}, clients)
+	unblock := make(chan struct{})
+	r := gin.New()
+	r.Use(NewConcurrencyLimitMiddleware(max).Limit())
+	r.GET("/upload", func(c *gin.Context) {
+		started <- struct{}{}
+		<-unblock
+		c.Status(http.StatusOK)
+	})
+	serve := func(ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/upload", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	codes := make(chan int, clients)
+	var wg sync.WaitGroup
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve("1.1.1.1")
+		}()
+	}
+	for i := 0; i < max; i++ {
+		<-started
+	}
+
+	// the limit is per IP: another client still gets through while 1.1.1.1 is saturated
+	other := make(chan int, 1)
+	go func() { other <- serve("2.2.2.2") }()
+	<-started
+
+	rejected := 0
+	for i := 0; i < clients-max; i++ {
+		assert.Equal(t, http.StatusTooManyRequests, <-codes)
+		rejected++
+	}
+	close(unblock)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, http.StatusOK, <-other)
+	assert.Equal(t, clients-max, rejected)
+
+	// finished requests give their slots back
+	assert.Equal(t, http.StatusOK, serve("1.1.1.1"))
+}
+
+func TestConcurrencyLimit_ReleasesOnPanic(t *testing.T) {
+	m := NewConcurrencyLimitMiddleware(1)
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) { c.AbortWithStatus(http.StatusInternalServerError) }))
+	r.Use(m.Limit())
+	r.GET("/", func(c *gin.Context) { panic("boom") })
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code, "the slot is freed after the panic")
+	}
+	assert.Empty(t, m.shard("192.0.2.1").inFlight)
+}
@@ -1,17 +1,38 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"strconv"
+	"strings"
+	"time"
 
-func CorsMiddleware() gin.HandlerFunc {
+	"github.com/gin-gonic/gin"
+)
+
+// CorsMiddleware sets the CORS headers. A positive maxAge lets browsers cache preflight results.
+// extraHeaders are allowed in addition to the standard set, e.g. a custom token header.
+func CorsMiddleware(maxAge time.Duration, extraHeaders ...string) gin.HandlerFunc {
+	allowHeaders := "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, Cache-Control, X-Requested-With, X-Forwarded-Proto"
+	for _, h := range extraHeaders {
+		if h != "" && !strings.EqualFold(h, DefaultTokenHeader) {
+			allowHeaders += ", " + h
+		}
+	}
+	maxAgeSeconds := ""
+	if maxAge > 0 {
+		maxAgeSeconds = strconv.Itoa(int(maxAge.Seconds()))
+	}
 	return func(ctx *gin.Context) {
 
 		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		// ctx.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, Cache-Control, X-Requested-With, X-Forwarded-Proto")
-		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		ctx.Writer.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, DELETE, OPTIONS")
 
 		if ctx.Request.Method == "OPTIONS" {
+			if maxAgeSeconds != "" {
+				ctx.Writer.Header().Set("Access-Control-Max-Age", maxAgeSeconds)
+			}
 			ctx.AbortWithStatus(200)
 			return
 		}
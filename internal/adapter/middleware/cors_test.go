@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorsMiddleware_MaxAge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(maxAge time.Duration) *gin.Engine {
+		r := gin.New()
+		r.Use(CorsMiddleware(maxAge))
+		r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+
+	t.Run("preflight carries configured max age", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(10*time.Minute).ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/ping", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("non-preflight omits max age", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(10*time.Minute).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("zero disables header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(0).ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/ping", nil))
+
+		assert.Empty(t, w.Header().Get("Access-Control-Max-Age"))
+	})
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// debugCaptureLimit bounds how much of a body is buffered for logging.
+	debugCaptureLimit = 64 << 10
+	// debugLogLimit bounds how much of a (redacted) body ends up in the log entry.
+	debugLogLimit = 4 << 10
+	redacted      = "[REDACTED]"
+)
+
+// DebugLog logs request and response bodies for the routes it is attached to when enabled.
+// Bodies are size-capped, and credentials (see sensitiveKey) are redacted from JSON fields and
+// request headers.
+// When disabled it is a no-op, so it can stay wired on a route group and be toggled by config.
+func DebugLog(logger *zap.Logger, enabled bool) gin.HandlerFunc {
+	if !enabled || logger == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		var reqBody []byte
+		if c.Request.Body != nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, debugCaptureLimit+1))
+			// hand the handler the captured prefix followed by whatever was not read
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(reqBody), c.Request.Body))
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		logger.Info("http exchange",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", writer.Status()),
+			zap.Any("headers", redactHeaders(c.Request.Header)),
+			zap.String("request_body", redactBody(reqBody)),
+			zap.String("response_body", redactBody(writer.body.Bytes())),
+		)
+	}
+}
+
+// bodyCaptureWriter tees the response into a buffer, keeping at most debugCaptureLimit+1 bytes.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	if room := debugCaptureLimit + 1 - w.body.Len(); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		w.body.Write(b[:room])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// sensitiveMarkers are matched against lower-cased names with "-" and "_" removed, so
+// "refreshToken", "reset_token", "X-Access-Token" and "Set-Cookie" are all caught.
+var sensitiveMarkers = []string{"password", "token", "secret", "cookie", "authorization", "apikey"}
+
+// sensitiveKey reports whether a JSON field or header name looks like it carries a credential.
+func sensitiveKey(name string) bool {
+	name = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for _, marker := range sensitiveMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if sensitiveKey(k) {
+			out[k] = redacted
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
+
+// redactBody masks credential fields in JSON bodies. Bodies that cannot be parsed (non-JSON or over
+// the capture limit) are summarized by size only, so nothing unredacted can leak into the log.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if len(body) > debugCaptureLimit {
+		return fmt.Sprintf("[body omitted: larger than %d bytes]", debugCaptureLimit)
+	}
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Sprintf("[non-JSON body omitted: %d bytes]", len(body))
+	}
+	out, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return fmt.Sprintf("[body omitted: %d bytes]", len(body))
+	}
+	if len(out) > debugLogLimit {
+		return string(out[:debugLogLimit]) + "...(truncated)"
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, inner := range val {
+			if sensitiveKey(k) {
+				val[k] = redacted
+				continue
+			}
+			val[k] = redactValue(inner)
+		}
+		return val
+	case []interface{}:
+		for i, inner := range val {
+			val[i] = redactValue(inner)
+		}
+		return val
+	default:
+		return v
+	}
+}
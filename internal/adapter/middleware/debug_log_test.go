@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDebugLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(enabled bool) (*gin.Engine, *observer.ObservedLogs) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		r := gin.New()
+		r.Use(DebugLog(zap.New(core), enabled))
+		r.POST("/login", func(c *gin.Context) {
+			var body map[string]string
+			_ = c.ShouldBindJSON(&body)
+			c.JSON(http.StatusOK, gin.H{"user": body["email"]})
+		})
+		return r, logs
+	}
+	send := func(r *gin.Engine) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"a@b.com","password":"S3cret!pass"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer token-value")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("enabled logs redacted bodies", func(t *testing.T) {
+		r, logs := newRouter(true)
+		w := send(r)
+		assert.JSONEq(t, `{"user":"a@b.com"}`, w.Body.String(), "handler still sees the full request body")
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		reqBody := fields["request_body"].(string)
+		assert.Contains(t, reqBody, "a@b.com")
+		assert.Contains(t, reqBody, redacted)
+		assert.NotContains(t, reqBody, "S3cret!pass")
+		assert.Contains(t, fields["response_body"], "a@b.com")
+
+		headers := fields["headers"].(map[string]string)
+		assert.Equal(t, redacted, headers["Authorization"])
+	})
+
+	t.Run("tokens, secrets and cookies are redacted", func(t *testing.T) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		r := gin.New()
+		r.Use(DebugLog(zap.New(core), true))
+		r.POST("/auth/password/reset", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"data": gin.H{"accessToken": "access-value", "refresh_token": "refresh-value", "userId": "u-1"}})
+		})
+		req := httptest.NewRequest(http.MethodPost, "/auth/password/reset",
+			strings.NewReader(`{"token":"reset-value","verifyToken":"verify-value","apiSecret":"secret-value","items":[{"resetToken":"nested-value"}]}`))
+		req.Header.Set("Cookie", "access_token=cookie-value")
+		req.Header.Set("X-Access-Token", "header-value")
+		req.Header.Set("X-Api-Key", "key-value")
+		req.Header.Set("X-Request-Id", "req-1")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.All()[0].ContextMap()
+		logged := fmt.Sprint(fields)
+		for _, secret := range []string{"access-value", "refresh-value", "reset-value", "verify-value", "secret-value", "nested-value", "cookie-value", "header-value", "key-value"} {
+			assert.NotContains(t, logged, secret)
+		}
+		assert.Contains(t, fields["response_body"], "u-1", "other fields are kept")
+		headers := fields["headers"].(map[string]string)
+		assert.Equal(t, redacted, headers["Cookie"])
+		assert.Equal(t, "req-1", headers["X-Request-Id"])
+	})
+
+	t.Run("disabled logs nothing", func(t *testing.T) {
+		r, logs := newRouter(false)
+		send(r)
+		assert.Zero(t, logs.Len())
+	})
+}
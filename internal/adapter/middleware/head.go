@@ -0,0 +1,26 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// HeadOnly lets a GET handler serve HEAD requests: status and headers are written as usual but the
+// body is dropped. Register it in front of the GET handler on the HEAD route.
+func HeadOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &headResponseWriter{ResponseWriter: c.Writer}
+		c.Next()
+	}
+}
+
+type headResponseWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	return len(b), nil
+}
+
+func (w *headResponseWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return len(s), nil
+}
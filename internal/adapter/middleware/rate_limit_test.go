@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_SlidingWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	m := NewRateLimitMiddleware(2, 10*time.Second)
+	m.now = func() time.Time { return now }
+
+	assert.True(t, m.allow("1.1.1.1"))
+	now = start.Add(4 * time.Second)
+	assert.True(t, m.allow("1.1.1.1"))
+	assert.False(t, m.allow("1.1.1.1"), "third request inside the window")
+	assert.True(t, m.allow("2.2.2.2"), "other clients have their own window")
+
+	now = start.Add(10 * time.Second)
+	assert.True(t, m.allow("1.1.1.1"), "the first request has left the window")
+	assert.False(t, m.allow("1.1.1.1"), "the second request is still in it")
+
+	now = start.Add(14 * time.Second)
+	assert.True(t, m.allow("1.1.1.1"))
+}
+
+func TestRateLimit_ConcurrentRequestsNeverExceedLimit(t *testing.T) {
+	m := NewRateLimitMiddleware(50, time.Minute)
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if m.allow("1.1.1.1") {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 50, allowed.Load())
+}
+
+func TestRateLimit_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(NewRateLimitMiddleware(1, time.Minute).RateLimit())
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, http.StatusTooManyRequests, serve())
+}
+
+// singleLockLimiter is the previous design, one mutex around every client, kept as a baseline.
+type singleLockLimiter struct {
+	mutex    sync.Mutex
+	requests map[string][]time.Time
+	limit    int
+	window   time.Duration
+}
+
+func (l *singleLockLimiter) allow(clientIP string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	windowStart := now.Add(-l.window)
+	var valid []time.Time
+	for _, t := range l.requests[clientIP] {
+		if t.After(windowStart) {
+			valid = append(valid, t)
+		}
+	}
+	if len(valid) >= l.limit {
+		l.requests[clientIP] = valid
+		return false
+	}
+	l.requests[clientIP] = append(valid, now)
+	return true
+}
+
+// BenchmarkRateLimit compares the sharded limiter with the single-lock baseline when many clients
+// are served in parallel; run with -cpu 1,8 to see the contention difference.
+func BenchmarkRateLimit(b *testing.B) {
+	ips := make([]string, 1024)
+	for i := range ips {
+		ips[i] = fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+	}
+	run := func(b *testing.B, allow func(string) bool) {
+		var next atomic.Uint64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				allow(ips[next.Add(1)%uint64(len(ips))])
+			}
+		})
+	}
+
+	b.Run("sharded", func(b *testing.B) {
+		m := NewRateLimitMiddleware(100, time.Second)
+		run(b, m.allow)
+	})
+	b.Run("single-lock", func(b *testing.B) {
+		l := &singleLockLimiter{requests: make(map[string][]time.Time), limit: 100, window: time.Second}
+		run(b, l.allow)
+	})
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// ReadOnly rejects every request that could write (anything but GET, HEAD and OPTIONS) with 503
+// while enabled, so the storefront stays browsable during maintenance. Routes whose pattern is in
+// exempt, such as login, stay open. It is a no-op when disabled.
+func ReadOnly(enabled bool, exempt ...string) gin.HandlerFunc {
+	if !enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if slices.Contains(exempt, c.FullPath()) {
+			c.Next()
+			return
+		}
+		rejectReadOnly(c)
+	}
+}
+
+// ReadOnlyWrite rejects every request while read-only mode is enabled. It goes on GET routes that
+// still write, such as the email verification link, which ReadOnly lets through by method.
+func ReadOnlyWrite(enabled bool) gin.HandlerFunc {
+	if !enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return rejectReadOnly
+}
+
+func rejectReadOnly(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.ErrorCode("read_only", "the service is in read-only mode for maintenance; please try again later", []string{}))
+}
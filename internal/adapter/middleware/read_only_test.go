@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newRouter := func(enabled bool) *gin.Engine {
+		r := gin.New()
+		r.Use(ReadOnly(enabled, "/auth/login"))
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		r.GET("/products", ok)
+		r.POST("/products", ok)
+		r.PUT("/products/:id", ok)
+		r.DELETE("/products/:id", ok)
+		r.POST("/auth/login", ok)
+		r.GET("/auth/verify", ReadOnlyWrite(enabled), ok)
+		return r
+	}
+	serve := func(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		r := newRouter(true)
+		assert.Equal(t, http.StatusOK, serve(r, http.MethodGet, "/products").Code)
+		assert.Equal(t, http.StatusOK, serve(r, http.MethodPost, "/auth/login").Code, "login stays open")
+		for _, req := range []struct{ method, path string }{
+			{http.MethodPost, "/products"},
+			{http.MethodPut, "/products/1"},
+			{http.MethodDelete, "/products/1"},
+		} {
+			w := serve(r, req.method, req.path)
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code, req.method)
+			assert.Contains(t, w.Body.String(), "read_only")
+		}
+		assert.Equal(t, http.StatusServiceUnavailable, serve(r, http.MethodGet, "/auth/verify").Code, "a GET that writes")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r := newRouter(false)
+		assert.Equal(t, http.StatusOK, serve(r, http.MethodPost, "/products").Code)
+		assert.Equal(t, http.StatusOK, serve(r, http.MethodDelete, "/products/1").Code)
+		assert.Equal(t, http.StatusOK, serve(r, http.MethodGet, "/auth/verify").Code)
+	})
+}
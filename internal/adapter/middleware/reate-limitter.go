@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"hash/fnv"
 	"net/http"
 	"sync"
 	"time"
@@ -8,50 +9,43 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// rateLimitShards is the number of independently locked buckets clients are spread over, so
+// requests from unrelated IPs rarely wait on each other.
+const rateLimitShards = 64
+
 // RateLimitMiddleware handles rate limiting : we don't use redis or other external services for this just for simplicity we keep it in memory
 //
 //	Here recommend to use centralized rate limiting service to handle rate limiting for production environment / like in distributed system setup
 type RateLimitMiddleware struct {
+	shards [rateLimitShards]rateLimitShard
+	limit  int
+	window time.Duration
+	now    func() time.Time
+}
+
+// rateLimitShard holds the request times of the clients whose IP hashes to it.
+type rateLimitShard struct {
+	mutex    sync.Mutex
 	requests map[string][]time.Time
-	mutex    sync.RWMutex
-	limit    int
-	window   time.Duration
 }
 
 // NewRateLimitMiddleware creates a new rate limit middleware
 func NewRateLimitMiddleware(limit int, window time.Duration) *RateLimitMiddleware {
-	return &RateLimitMiddleware{
-		requests: make(map[string][]time.Time),
-		limit:    limit,
-		window:   window,
+	m := &RateLimitMiddleware{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
 	}
+	for i := range m.shards {
+		m.shards[i].requests = make(map[string][]time.Time)
+	}
+	return m
 }
 
 // RateLimit middleware that limits requests per IP
 func (m *RateLimitMiddleware) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get client IP
-		clientIP := c.ClientIP()
-
-		m.mutex.Lock()
-		defer m.mutex.Unlock()
-
-		now := time.Now()
-		windowStart := now.Add(-m.window)
-
-		// Clean old requests
-		if requests, exists := m.requests[clientIP]; exists {
-			var validRequests []time.Time
-			for _, reqTime := range requests {
-				if reqTime.After(windowStart) {
-					validRequests = append(validRequests, reqTime)
-				}
-			}
-			m.requests[clientIP] = validRequests
-		}
-
-		// Check if limit exceeded
-		if len(m.requests[clientIP]) >= m.limit {
+		if !m.allow(c.ClientIP()) {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "Rate limit exceeded",
 				"message": "Too many requests, please try again later",
@@ -59,10 +53,39 @@ func (m *RateLimitMiddleware) RateLimit() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// the shard lock is released before the handler runs
+		c.Next()
+	}
+}
 
-		// Add current request
-		m.requests[clientIP] = append(m.requests[clientIP], now)
+// allow records a request from clientIP and reports whether it fits in the sliding window: at most
+// limit requests within the last window.
+func (m *RateLimitMiddleware) allow(clientIP string) bool {
+	shard := m.shard(clientIP)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
 
-		c.Next()
+	now := m.now()
+	windowStart := now.Add(-m.window)
+
+	// Drop requests that left the window; times are appended in order, so they are all at the front.
+	requests := shard.requests[clientIP]
+	expired := 0
+	for expired < len(requests) && !requests[expired].After(windowStart) {
+		expired++
 	}
+	requests = requests[expired:]
+
+	if len(requests) >= m.limit {
+		shard.requests[clientIP] = requests
+		return false
+	}
+	shard.requests[clientIP] = append(requests, now)
+	return true
+}
+
+func (m *RateLimitMiddleware) shard(clientIP string) *rateLimitShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(clientIP))
+	return &m.shards[h.Sum32()%rateLimitShards]
 }
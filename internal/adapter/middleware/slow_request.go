@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SlowRequest logs a warning for every request that takes longer than threshold to serve.
+// It is independent of the database slow-query log and is a no-op when threshold is zero.
+func SlowRequest(logger *zap.Logger, threshold time.Duration) gin.HandlerFunc {
+	if threshold <= 0 || logger == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		latency := time.Since(start)
+		if latency <= threshold {
+			return
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", latency),
+			zap.Duration("threshold", threshold),
+		}
+		// auth runs on route groups after this middleware, so claims are read once the chain returns
+		if claims, ok := GetUserClaims(c); ok {
+			fields = append(fields, zap.String("user_id", claims.UserID.String()))
+		}
+		logger.Warn("slow request", fields...)
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlowRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(SlowRequest(zap.New(core), 20*time.Millisecond))
+	r.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(40 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	r.GET("/slow-auth", func(c *gin.Context) {
+		c.Set(userContextKey, UserClaims{UserID: userID})
+		time.Sleep(40 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	serve := func(path string) {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	serve("/fast")
+	assert.Equal(t, 0, logs.Len(), "fast requests are not logged")
+
+	serve("/slow")
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Equal(t, http.MethodGet, fields["method"])
+	assert.Equal(t, "/slow", fields["path"])
+	assert.GreaterOrEqual(t, fields["latency"].(time.Duration), 40*time.Millisecond)
+	assert.NotContains(t, fields, "user_id")
+
+	serve("/slow-auth")
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, userID.String(), logs.All()[1].ContextMap()["user_id"])
+}
+
+func TestSlowRequest_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(SlowRequest(zap.New(core), 0))
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, 0, logs.Len())
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout puts a deadline of d on every request's context. Routes listed in overrides, keyed by
+// RouteKey, get their own deadline instead, so slow routes such as uploads can be given more time
+// without loosening the rest. A zero duration means no deadline. Handlers see the expiry through
+// the context and answer with domain.ErrRequestTimeout; nothing is written on their behalf.
+func Timeout(d time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := d
+		if override, ok := overrides[RouteKey(c.Request.Method, c.FullPath())]; ok {
+			limit = override
+		}
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limit)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RouteKey identifies a route for Timeout overrides, e.g. "POST /api/v1/products/:id/images".
+func RouteKey(method, fullPath string) string {
+	return method + " " + fullPath
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout_UploadRouteGetsLongerDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// slow stands in for a handler doing context-aware work that takes 50ms
+	slow := func(c *gin.Context) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			c.Status(http.StatusOK)
+		case <-c.Request.Context().Done():
+			c.Status(http.StatusServiceUnavailable)
+		}
+	}
+	r := gin.New()
+	r.Use(Timeout(10*time.Millisecond, map[string]time.Duration{
+		RouteKey(http.MethodPost, "/products/:id/images"): time.Second,
+		RouteKey(http.MethodGet, "/export"):               0,
+	}))
+	r.POST("/products/:id/images", slow)
+	r.POST("/products/:id", slow)
+	r.GET("/export", slow)
+
+	for _, tc := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, "/products/42", http.StatusServiceUnavailable},
+		{http.MethodPost, "/products/42/images", http.StatusOK},
+		{http.MethodGet, "/export", http.StatusOK},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		assert.Equal(t, tc.want, w.Code, tc.path)
+	}
+}
+
+func TestTimeout_SetsDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var hasDeadline bool
+	r := gin.New()
+	r.Use(Timeout(time.Minute, nil))
+	r.GET("/", func(c *gin.Context) { _, hasDeadline = c.Request.Context().Deadline() })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, hasDeadline)
+
+	hasDeadline = false
+	r = gin.New()
+	r.Use(Timeout(0, nil))
+	r.GET("/", func(c *gin.Context) { _, hasDeadline = c.Request.Context().Deadline() })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, hasDeadline, "zero disables the deadline")
+}
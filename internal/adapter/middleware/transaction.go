@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+const txContextKey = "txRepositories"
+
+// errRollback makes UnitOfWork.Execute roll back a request that answered with an error status.
+var errRollback = errors.New("request failed, rolling back")
+
+// Transaction runs the rest of the chain inside one UnitOfWork transaction so a handler can use
+// several repositories atomically without calling Execute itself. Attach it only to the routes
+// that need it; handlers read the repositories with TxRepositories. The transaction commits when
+// the response status is below 400 and rolls back otherwise (or on panic). The response is held
+// back until then: a failed commit replaces it with a 500, so the client is never told that a
+// change succeeded when it was not stored. The error is also attached to c.Errors for the request log.
+func Transaction(uow repository.UnitOfWork) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &txResponseWriter{ResponseWriter: c.Writer, header: c.Writer.Header().Clone(), status: http.StatusOK}
+		c.Writer = writer
+		// restored before a panic unwinds further, so the recovery middleware answers the client
+		defer func() { c.Writer = writer.ResponseWriter }()
+
+		err := uow.Execute(c.Request.Context(), func(repos repository.RepositoryProvider) error {
+			c.Set(txContextKey, repos)
+			c.Next()
+			if writer.Status() >= http.StatusBadRequest {
+				return errRollback
+			}
+			return nil
+		})
+		c.Writer = writer.ResponseWriter
+		if err != nil && !errors.Is(err, errRollback) {
+			_ = c.Error(err)
+			c.JSON(http.StatusInternalServerError, response.ErrorBase("internal server error", nil))
+			return
+		}
+		writer.flush()
+	}
+}
+
+// txResponseWriter holds the status, headers and body a handler writes until the transaction has
+// finished, so nothing reaches the client before the commit.
+type txResponseWriter struct {
+	gin.ResponseWriter
+	header  http.Header
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *txResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *txResponseWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *txResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *txResponseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *txResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *txResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *txResponseWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *txResponseWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op: the body is only sent once the transaction is over.
+func (w *txResponseWriter) Flush() {}
+
+// flush sends what the handler wrote to the client.
+func (w *txResponseWriter) flush() {
+	header := w.ResponseWriter.Header()
+	for k := range header {
+		delete(header, k)
+	}
+	for k, v := range w.header {
+		header[k] = v
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// TxRepositories returns the repositories bound to the request's transaction, if Transaction ran.
+func TxRepositories(c *gin.Context) (repository.RepositoryProvider, bool) {
+	value, exists := c.Get(txContextKey)
+	if !exists {
+		return nil, false
+	}
+	repos, ok := value.(repository.RepositoryProvider)
+	return repos, ok
+}
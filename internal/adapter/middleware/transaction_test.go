@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+// recordingUnitOfWork reports whether the callback asked for a commit (nil) or a rollback. A
+// commitErr is returned in place of a successful commit.
+type recordingUnitOfWork struct {
+	repository.RepositoryProvider
+	results   []error
+	commitErr error
+}
+
+func (u *recordingUnitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
+	err := fn(u)
+	u.results = append(u.results, err)
+	if err == nil {
+		return u.commitErr
+	}
+	return err
+}
+
+func TestTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uow := &recordingUnitOfWork{}
+	r := gin.New()
+	tx := Transaction(uow)
+	handler := func(status int) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			repos, ok := TxRepositories(c)
+			require.True(t, ok)
+			assert.Same(t, uow, repos)
+			c.Status(status)
+		}
+	}
+	r.POST("/ok", tx, handler(http.StatusCreated))
+	r.POST("/fail", tx, handler(http.StatusInternalServerError))
+	r.POST("/plain", func(c *gin.Context) {
+		_, ok := TxRepositories(c)
+		assert.False(t, ok, "routes without the middleware get no transaction")
+		c.Status(http.StatusOK)
+	})
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, serve("/ok"))
+	require.Len(t, uow.results, 1)
+	assert.NoError(t, uow.results[0], "2xx commits")
+
+	assert.Equal(t, http.StatusInternalServerError, serve("/fail"))
+	require.Len(t, uow.results, 2)
+	assert.ErrorIs(t, uow.results[1], errRollback, "5xx rolls back")
+
+	assert.Equal(t, http.StatusOK, serve("/plain"))
+	assert.Len(t, uow.results, 2)
+}
+
+func TestTransaction_CommitFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	commitErr := errors.New("could not serialize access")
+	uow := &recordingUnitOfWork{commitErr: commitErr}
+	r := gin.New()
+	var logged []*gin.Error
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		logged = c.Errors
+	})
+	r.POST("/orders", Transaction(uow), func(c *gin.Context) {
+		c.Header("Location", "/orders/1")
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "a failed commit is not reported as created")
+	assert.JSONEq(t, `{"success":false,"message":"internal server error"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Location"), "headers of the discarded response are dropped")
+	require.Len(t, logged, 1)
+	assert.ErrorIs(t, logged[0].Err, commitErr)
+}
+
+func TestTransaction_ResponseSentAfterCommit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uow := &recordingUnitOfWork{}
+	r := gin.New()
+	r.POST("/orders", Transaction(uow), func(c *gin.Context) {
+		c.Header("Location", "/orders/1")
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+		assert.Empty(t, uow.results, "nothing is committed while the handler runs")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":1}`, w.Body.String())
+	assert.Equal(t, "/orders/1", w.Header().Get("Location"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
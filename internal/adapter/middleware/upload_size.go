@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minilik/ecommerce/pkg/metrics"
+)
+
+// UploadSize records how many request body bytes each upload route actually read, labeled by the
+// route pattern. A nil histogram makes it a no-op.
+func UploadSize(h *metrics.Histogram) gin.HandlerFunc {
+	if h == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		body := &countingReader{ReadCloser: c.Request.Body}
+		c.Request.Body = body
+
+		c.Next()
+
+		h.Observe(c.FullPath(), float64(body.n))
+	}
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
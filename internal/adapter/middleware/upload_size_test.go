@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/pkg/metrics"
+)
+
+func TestUploadSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := metrics.NewHistogram(metrics.UploadSizeBuckets)
+
+	r := gin.New()
+	r.POST("/products/:id/images", UploadSize(h), func(c *gin.Context) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"files": len(form.File["files"])})
+	})
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("files", "photo.jpg")
+	require.NoError(t, err)
+	_, err = part.Write(bytes.Repeat([]byte{0xff}, 100<<10))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	size := buf.Len()
+
+	req := httptest.NewRequest(http.MethodPost, "/products/123/images", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	sample, ok := h.Snapshot()["/products/:id/images"]
+	require.True(t, ok, "labeled by route pattern, not the concrete path")
+	assert.Equal(t, uint64(1), sample.Count)
+	assert.Equal(t, float64(size), sample.Sum)
+	assert.Equal(t, uint64(0), sample.Buckets["65536"])
+	assert.Equal(t, uint64(1), sample.Buckets["262144"])
+}
@@ -0,0 +1,91 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) repository.CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	model := models.CategoryFromDomain(category)
+	if model.ID == uuid.Nil {
+		model.ID = uuid.New()
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	category.ID = model.ID
+	return nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Category{}).
+		Where("id = ?", category.ID).
+		Updates(map[string]interface{}{
+			"name":        category.Name,
+			"description": category.Description,
+			"updated_at":  category.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	var model models.Category
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *categoryRepository) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Category, int64, error) {
+	var (
+		records []models.Category
+		total   int64
+	)
+	tx := r.db.WithContext(ctx).Model(&models.Category{})
+	if filter.Search != "" {
+		tx = tx.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Search)+"%")
+	}
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit > 0 {
+		tx = tx.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		tx = tx.Offset(filter.Offset)
+	}
+	if err := tx.Order("name ASC").Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	categories := make([]domain.Category, 0, len(records))
+	for _, model := range records {
+		categories = append(categories, *model.ToDomain())
+	}
+	return categories, total, nil
+}
@@ -0,0 +1,32 @@
+package gorm
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE for a unique constraint violation.
+const pgUniqueViolation = "23505"
+
+// uniqueViolation returns the constraint named by a Postgres unique violation in err's chain.
+// Application-level "already exists" checks race with concurrent writers, so repositories translate
+// the violation itself into the matching domain error.
+func uniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// translateUnique replaces a unique violation on one of the given constraints with its domain
+// error and returns any other error unchanged.
+func translateUnique(err error, constraints map[string]error) error {
+	if name, ok := uniqueViolation(err); ok {
+		if domainErr, known := constraints[name]; known {
+			return domainErr
+		}
+	}
+	return err
+}
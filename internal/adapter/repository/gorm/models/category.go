@@ -8,9 +8,9 @@ import (
 )
 
 type Category struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description" binding:"required"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name        string    `gorm:"size:100;not null"`
+	Description string    `gorm:"type:text"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -20,15 +20,12 @@ func (Category) TableName() string {
 }
 
 func (c *Category) ToDomain() *domain.Category {
-	// images := make([]domain.ProductImage, 0, len(p.Images))
-	// for _, im := range p.Images {
-	// 	images = append(images, im.ToDomain())
-	// }
 	return &domain.Category{
-		ID:        c.ID,
-		Name:      c.Name,
-		CreatedAt: c.CreatedAt,
-		UpdatedAt: c.UpdatedAt,
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
 	}
 }
 
@@ -37,9 +34,10 @@ func CategoryFromDomain(cat *domain.Category) *Category {
 		return nil
 	}
 	return &Category{
-		ID:        cat.ID,
-		Name:      cat.Name,
-		CreatedAt: cat.CreatedAt,
-		UpdatedAt: cat.UpdatedAt,
+		ID:          cat.ID,
+		Name:        cat.Name,
+		Description: cat.Description,
+		CreatedAt:   cat.CreatedAt,
+		UpdatedAt:   cat.UpdatedAt,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"github.com/minilik/ecommerce/internal/domain"
 )
@@ -17,6 +18,12 @@ type Order struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Items       []OrderItem `gorm:"foreignKey:OrderID"`
+	// OrderNumber is NULL for orders placed before order numbers existed; NULLs never collide in the
+	// unique index.
+	OrderNumber *string `gorm:"size:32;uniqueIndex"`
+	// DeletedAt archives the order: GORM's soft delete hides it from every scoped query. Its items
+	// are left as they are, since they are only ever read through their order.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 func (Order) TableName() string {
@@ -24,13 +31,16 @@ func (Order) TableName() string {
 }
 
 type OrderItem struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	OrderID   uuid.UUID `gorm:"type:uuid;not null"`
-	ProductID uuid.UUID `gorm:"type:uuid;not null"`
-	Quantity  int       `gorm:"not null"`
-	UnitPrice float64   `gorm:"not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrderID             uuid.UUID `gorm:"type:uuid;not null"`
+	ProductID           uuid.UUID `gorm:"type:uuid;not null"`
+	ProductName         string    `gorm:"size:100;not null;default:''"`
+	Quantity            int       `gorm:"not null"`
+	BackorderedQuantity int       `gorm:"not null;default:0"`
+	Status              string    `gorm:"size:50;not null;default:'fulfilled'"`
+	UnitPrice           float64   `gorm:"not null"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
 }
 
 func (OrderItem) TableName() string {
@@ -41,17 +51,20 @@ func (o *Order) ToDomain() *domain.Order {
 	items := make([]domain.OrderItem, 0, len(o.Items))
 	for _, item := range o.Items {
 		items = append(items, domain.OrderItem{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			OrderID:   item.OrderID,
-			Quantity:  item.Quantity,
-			UnitPrice: item.UnitPrice,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:                  item.ID,
+			ProductID:           item.ProductID,
+			OrderID:             item.OrderID,
+			ProductName:         item.ProductName,
+			Quantity:            item.Quantity,
+			BackorderedQuantity: item.BackorderedQuantity,
+			Status:              domain.OrderItemStatus(item.Status),
+			UnitPrice:           item.UnitPrice,
+			CreatedAt:           item.CreatedAt,
+			UpdatedAt:           item.UpdatedAt,
 		})
 	}
 
-	return &domain.Order{
+	order := &domain.Order{
 		ID:          o.ID,
 		UserID:      o.UserID,
 		Description: o.Description,
@@ -60,7 +73,13 @@ func (o *Order) ToDomain() *domain.Order {
 		Items:       items,
 		CreatedAt:   o.CreatedAt,
 		UpdatedAt:   o.UpdatedAt,
+		OrderNumber: derefString(o.OrderNumber),
 	}
+	if o.DeletedAt.Valid {
+		archivedAt := o.DeletedAt.Time
+		order.ArchivedAt = &archivedAt
+	}
+	return order
 }
 
 func OrderFromDomain(order *domain.Order) *Order {
@@ -71,13 +90,16 @@ func OrderFromDomain(order *domain.Order) *Order {
 	items := make([]OrderItem, 0, len(order.Items))
 	for _, item := range order.Items {
 		items = append(items, OrderItem{
-			ID:        item.ID,
-			OrderID:   order.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			UnitPrice: item.UnitPrice,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
+			ID:                  item.ID,
+			OrderID:             order.ID,
+			ProductID:           item.ProductID,
+			ProductName:         item.ProductName,
+			Quantity:            item.Quantity,
+			BackorderedQuantity: item.BackorderedQuantity,
+			Status:              string(item.Status),
+			UnitPrice:           item.UnitPrice,
+			CreatedAt:           item.CreatedAt,
+			UpdatedAt:           item.UpdatedAt,
 		})
 	}
 
@@ -90,5 +112,42 @@ func OrderFromDomain(order *domain.Order) *Order {
 		Items:       items,
 		CreatedAt:   order.CreatedAt,
 		UpdatedAt:   order.UpdatedAt,
+		OrderNumber: nullableString(order.OrderNumber),
+	}
+}
+
+// OrderStatusChange is a row of order_status_history. Rows are removed with their order.
+type OrderStatusChange struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	OrderID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_order_status_history_order"`
+	FromStatus string     `gorm:"size:50;not null;default:''"`
+	ToStatus   string     `gorm:"size:50;not null"`
+	ActorID    *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
+}
+
+func (OrderStatusChange) TableName() string {
+	return "order_status_history"
+}
+
+func (c *OrderStatusChange) ToDomain() domain.OrderStatusChange {
+	return domain.OrderStatusChange{
+		ID:         c.ID,
+		OrderID:    c.OrderID,
+		FromStatus: domain.OrderStatus(c.FromStatus),
+		ToStatus:   domain.OrderStatus(c.ToStatus),
+		ActorID:    c.ActorID,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+func OrderStatusChangeFromDomain(change *domain.OrderStatusChange) *OrderStatusChange {
+	return &OrderStatusChange{
+		ID:         change.ID,
+		OrderID:    change.OrderID,
+		FromStatus: string(change.FromStatus),
+		ToStatus:   string(change.ToStatus),
+		ActorID:    change.ActorID,
+		CreatedAt:  change.CreatedAt,
 	}
 }
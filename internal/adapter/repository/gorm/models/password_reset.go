@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+type PasswordReset struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (PasswordReset) TableName() string {
+	return "password_resets"
+}
+
+func (p *PasswordReset) ToDomain() *domain.PasswordReset {
+	return &domain.PasswordReset{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		ExpiresAt: p.ExpiresAt,
+		UsedAt:    p.UsedAt,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func PasswordResetFromDomain(reset *domain.PasswordReset) *PasswordReset {
+	if reset == nil {
+		return nil
+	}
+	return &PasswordReset{
+		ID:        reset.ID,
+		UserID:    reset.UserID,
+		ExpiresAt: reset.ExpiresAt,
+		UsedAt:    reset.UsedAt,
+		CreatedAt: reset.CreatedAt,
+	}
+}
@@ -10,16 +10,18 @@ import (
 
 type Product struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Name        string    `gorm:"size:100;not null"`
+	Name        string    `gorm:"size:100;not null;index:idx_products_name_prefix,expression:lower(name) text_pattern_ops"`
 	Description string    `gorm:"type:text;not null"`
 	Price       float64   `gorm:"not null"`
 	Stock       int       `gorm:"not null"`
 	Category    string    `gorm:"size:100;not null"`
-	UserID      uuid.UUID `gorm:"type:uuid;not null"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_products_owner_external_id,priority:1"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Images      []ProductImage `gorm:"foreignKey:ProductID"`
-	CategoryId  uuid.UUID      `gorm:"foreignKey:ID"`
+	CategoryId  uuid.UUID      `gorm:"type:uuid;index"`
+	// ExternalID is NULL when not supplied; NULLs never collide in the unique index.
+	ExternalID *string `gorm:"size:100;uniqueIndex:idx_products_owner_external_id,priority:2"`
 }
 
 func (Product) TableName() string {
@@ -39,9 +41,11 @@ func (p *Product) ToDomain() *domain.Product {
 		Stock:       p.Stock,
 		Category:    p.Category,
 		UserID:      p.UserID,
+		ExternalID:  derefString(p.ExternalID),
 		Images:      images,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
+		CategoryId:  p.CategoryId,
 	}
 }
 
@@ -57,7 +61,23 @@ func ProductFromDomain(product *domain.Product) *Product {
 		Stock:       product.Stock,
 		Category:    product.Category,
 		UserID:      product.UserID,
+		ExternalID:  nullableString(product.ExternalID),
 		CreatedAt:   product.CreatedAt,
 		UpdatedAt:   product.UpdatedAt,
+		CategoryId:  product.CategoryId,
 	}
 }
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
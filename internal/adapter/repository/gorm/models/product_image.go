@@ -8,11 +8,18 @@ import (
 	"github.com/minilik/ecommerce/internal/domain"
 )
 
+// ProductImage is a row of product_images, which holds every product asset; Type tells images
+// from documents. Rows stored before documents existed default to images.
 type ProductImage struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	ProductID uuid.UUID `gorm:"type:uuid;index;not null"`
-	URL       string    `gorm:"type:text;not null"`
-	CreatedAt time.Time
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ProductID   uuid.UUID `gorm:"type:uuid;index;not null"`
+	Type        string    `gorm:"type:varchar(20);not null;default:'image'"`
+	URL         string    `gorm:"type:text;not null"`
+	AltText     string    `gorm:"type:varchar(250);not null;default:''"`
+	Caption     string    `gorm:"type:varchar(500);not null;default:''"`
+	Filename    string    `gorm:"type:varchar(255);not null;default:''"`
+	ContentHash string    `gorm:"type:varchar(64);not null;default:''"`
+	CreatedAt   time.Time
 }
 
 func (ProductImage) TableName() string {
@@ -21,9 +28,14 @@ func (ProductImage) TableName() string {
 
 func (m *ProductImage) ToDomain() domain.ProductImage {
 	return domain.ProductImage{
-		ID:        m.ID,
-		ProductID: m.ProductID,
-		URL:       m.URL,
-		CreatedAt: m.CreatedAt,
+		ID:          m.ID,
+		ProductID:   m.ProductID,
+		Type:        domain.AssetType(m.Type),
+		URL:         m.URL,
+		AltText:     m.AltText,
+		Caption:     m.Caption,
+		Filename:    m.Filename,
+		ContentHash: m.ContentHash,
+		CreatedAt:   m.CreatedAt,
 	}
 }
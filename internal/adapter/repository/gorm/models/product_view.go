@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// ProductView keeps the filter as JSON so new listing options do not need a migration.
+type ProductView struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OwnerID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_product_views_owner_name"`
+	Name      string    `gorm:"size:100;not null;uniqueIndex:idx_product_views_owner_name"`
+	Filter    string    `gorm:"type:text;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (v *ProductView) ToDomain() (*domain.ProductView, error) {
+	view := &domain.ProductView{
+		ID:        v.ID,
+		OwnerID:   v.OwnerID,
+		Name:      v.Name,
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+	if v.Filter != "" {
+		if err := json.Unmarshal([]byte(v.Filter), &view.Filter); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+func ProductViewFromDomain(view *domain.ProductView) (*ProductView, error) {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return &ProductView{
+		ID:        view.ID,
+		OwnerID:   view.OwnerID,
+		Name:      view.Name,
+		Filter:    string(filter),
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}, nil
+}
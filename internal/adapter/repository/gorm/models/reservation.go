@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+type Reservation struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Quantity  int       `gorm:"not null"`
+	Status    string    `gorm:"size:20;not null;index:idx_reservations_status_expires"`
+	ExpiresAt time.Time `gorm:"not null;index:idx_reservations_status_expires"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Reservation) TableName() string {
+	return "reservations"
+}
+
+func (r *Reservation) ToDomain() *domain.Reservation {
+	return &domain.Reservation{
+		ID:        r.ID,
+		ProductID: r.ProductID,
+		UserID:    r.UserID,
+		Quantity:  r.Quantity,
+		Status:    domain.ReservationStatus(r.Status),
+		ExpiresAt: r.ExpiresAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func ReservationFromDomain(reservation *domain.Reservation) *Reservation {
+	if reservation == nil {
+		return nil
+	}
+	return &Reservation{
+		ID:        reservation.ID,
+		ProductID: reservation.ProductID,
+		UserID:    reservation.UserID,
+		Quantity:  reservation.Quantity,
+		Status:    string(reservation.Status),
+		ExpiresAt: reservation.ExpiresAt,
+		CreatedAt: reservation.CreatedAt,
+		UpdatedAt: reservation.UpdatedAt,
+	}
+}
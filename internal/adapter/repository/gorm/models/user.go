@@ -9,13 +9,15 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Username  string    `gorm:"uniqueIndex;size:100;not null"`
-	Email     string    `gorm:"uniqueIndex;size:255;not null"`
-	Password  string    `gorm:"not null"`
-	Role      string    `gorm:"size:20;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Username          string    `gorm:"uniqueIndex:idx_users_username;size:100;not null"`
+	Email             string    `gorm:"uniqueIndex:idx_users_email;size:255;not null"`
+	Password          string    `gorm:"not null"`
+	Role              string    `gorm:"size:20;not null"`
+	EmailVerified     bool      `gorm:"not null;default:false"`
+	PasswordChangedAt *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 
 	Products []Product
 	Orders   []Order
@@ -27,13 +29,15 @@ func (User) TableName() string {
 
 func (u *User) ToDomain() *domain.User {
 	return &domain.User{
-		ID:        u.ID,
-		Username:  u.Username,
-		Email:     u.Email,
-		Password:  u.Password,
-		Role:      domain.Role(u.Role),
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:                u.ID,
+		Username:          u.Username,
+		Email:             u.Email,
+		Password:          u.Password,
+		Role:              domain.Role(u.Role),
+		EmailVerified:     u.EmailVerified,
+		PasswordChangedAt: u.PasswordChangedAt,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
 	}
 }
 
@@ -42,12 +46,14 @@ func UserFromDomain(user *domain.User) *User {
 		return nil
 	}
 	return &User{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Password:  user.Password,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Password:          user.Password,
+		Role:              string(user.Role),
+		EmailVerified:     user.EmailVerified,
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 }
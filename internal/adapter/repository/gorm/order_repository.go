@@ -2,9 +2,12 @@ package gorm
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
 	"github.com/minilik/ecommerce/internal/domain"
@@ -32,11 +35,28 @@ func (r *orderRepository) Create(ctx context.Context, order *domain.Order) error
 	return nil
 }
 
-func (r *orderRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Order, error) {
+func (r *orderRepository) CountByUserAndStatus(ctx context.Context, userID uuid.UUID, status domain.OrderStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("user_id = ? AND status = ?", userID, string(status)).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *orderRepository) ListByUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error) {
 	var records []models.Order
-	if err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx)
+	if includeArchived {
+		query = query.Unscoped()
+	}
+	query = query.
 		Preload("Items").
-		Where("user_id = ?", userID).
+		Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	if err := query.
 		Order("created_at DESC").
 		Find(&records).Error; err != nil {
 		return nil, err
@@ -56,10 +76,195 @@ func (r *orderRepository) HasPendingOrdersByProductID(ctx context.Context, produ
 	err := r.db.WithContext(ctx).
 		Model(&models.OrderItem{}).
 		Joins("INNER JOIN orders ON order_items.order_id = orders.id").
-		Where("order_items.product_id = ? AND orders.status = ?", productID, string(domain.OrderStatusPending)).
+		Where("order_items.product_id = ? AND orders.status IN ?", productID, []string{string(domain.OrderStatusPending), string(domain.OrderStatusReviewRequired)}).
 		Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+func (r *orderRepository) ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]domain.Order, error) {
+	var records []models.Order
+	// lock the batch so a concurrent expiry run skips it instead of waiting
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Items").
+		Where("status = ? AND updated_at < ?", string(domain.OrderStatusPending), before).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	orders := make([]domain.Order, 0, len(records))
+	for _, rec := range records {
+		if o := rec.ToDomain(); o != nil {
+			orders = append(orders, *o)
+		}
+	}
+	return orders, nil
+}
+
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error {
+	res := r.db.WithContext(ctx).
+		Model(&models.Order{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": string(status), "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *orderRepository) AddStatusChange(ctx context.Context, change *domain.OrderStatusChange) error {
+	model := models.OrderStatusChangeFromDomain(change)
+	if model.ID == uuid.Nil {
+		model.ID = uuid.New()
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	change.ID = model.ID
+	return nil
+}
+
+func (r *orderRepository) ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	var records []models.OrderStatusChange
+	if err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	changes := make([]domain.OrderStatusChange, 0, len(records))
+	for _, rec := range records {
+		changes = append(changes, rec.ToDomain())
+	}
+	return changes, nil
+}
+
+func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	var record models.Order
+	if err := r.db.WithContext(ctx).Unscoped().Preload("Items").First(&record, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return record.ToDomain(), nil
+}
+
+// orderItemWithProduct is an order_items row with the columns joined in from its product.
+type orderItemWithProduct struct {
+	models.OrderItem
+	CurrentProductName *string
+	ProductImageURL    *string
+}
+
+func (r *orderRepository) GetByIDWithProducts(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	db := r.db.WithContext(ctx)
+	var record models.Order
+	if err := db.Unscoped().First(&record, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	// one query for all items: the product is left-joined so items of deleted products still
+	// show, and the first image is picked per product in the same statement
+	var rows []orderItemWithProduct
+	if err := db.Table("order_items").
+		Select(`order_items.*, products.name AS current_product_name,
+			(SELECT product_images.url FROM product_images
+				WHERE product_images.product_id = order_items.product_id AND product_images.type = ?
+				ORDER BY product_images.created_at LIMIT 1) AS product_image_url`, string(domain.AssetTypeImage)).
+		Joins("LEFT JOIN products ON products.id = order_items.product_id").
+		Where("order_items.order_id = ?", id).
+		Order("order_items.created_at").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	record.Items = make([]models.OrderItem, len(rows))
+	for i, row := range rows {
+		record.Items[i] = row.OrderItem
+	}
+	order := record.ToDomain()
+	for i, row := range rows {
+		if row.CurrentProductName == nil {
+			continue
+		}
+		product := &domain.OrderItemProduct{Name: *row.CurrentProductName}
+		if row.ProductImageURL != nil {
+			product.ImageURL = *row.ProductImageURL
+		}
+		order.Items[i].Product = product
+	}
+	return order, nil
+}
+
+func (r *orderRepository) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
+	var record models.Order
+	if err := r.db.WithContext(ctx).Unscoped().Preload("Items").First(&record, "order_number = ?", number).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return record.ToDomain(), nil
+}
+
+func (r *orderRepository) NextOrderNumber(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.db.WithContext(ctx).Raw("SELECT nextval('order_number_seq')").Scan(&seq).Error
+	return seq, err
+}
+
+func (r *orderRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	var record models.Order
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		First(&record, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return record.ToDomain(), nil
+}
+
+func (r *orderRepository) Archive(ctx context.Context, id uuid.UUID, at time.Time) error {
+	// same as a soft Delete, but with the caller's clock
+	res := r.db.WithContext(ctx).Model(&models.Order{}).Where("id = ?", id).Update("deleted_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (r *orderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("order_id = ?", id).Delete(&models.OrderItem{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("order_id = ?", id).Delete(&models.OrderStatusChange{}).Error; err != nil {
+		return err
+	}
+	res := db.Unscoped().Delete(&models.Order{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrOrderNotFound
+	}
+	return nil
+}
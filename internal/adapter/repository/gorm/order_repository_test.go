@@ -0,0 +1,171 @@
+package gorm
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+func TestOrderRepository_Delete_RemovesItemsAndHistory(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_items" WHERE order_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_status_history" WHERE order_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "orders" WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Delete(context.Background(), id))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Delete_NotFound(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+	id := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_items" WHERE order_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "order_status_history" WHERE order_id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "orders" WHERE id = $1`)).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), id), domain.ErrOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_NextOrderNumber(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT nextval('order_number_seq')`)).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(123))
+
+	seq, err := repo.NextOrderNumber(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), seq)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_GetByNumber_NotFound(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE order_number = $1`)).
+		WithArgs("ORD-2024-000001", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByNumber(context.Background(), "ORD-2024-000001")
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ListByUser_Archived(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+	userID, orderID := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE user_id = $1 AND "orders"."deleted_at" IS NULL ORDER BY created_at DESC`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	orders, err := repo.ListByUser(ctx, userID, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, orders, "archived orders are hidden by default")
+
+	archivedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE user_id = $1 ORDER BY created_at DESC`)).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status", "deleted_at"}).AddRow(orderID, userID, "completed", archivedAt))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_items" WHERE "order_items"."order_id" = $1`)).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id"}).AddRow(uuid.New(), orderID))
+	orders, err = repo.ListByUser(ctx, userID, "", true)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.NotNil(t, orders[0].ArchivedAt)
+	assert.True(t, archivedAt.Equal(*orders[0].ArchivedAt))
+	assert.Len(t, orders[0].Items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Archive(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+	id := uuid.New()
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	update := regexp.QuoteMeta(`UPDATE "orders" SET "deleted_at"=$1,"updated_at"=$2 WHERE id = $3 AND "orders"."deleted_at" IS NULL`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(update).WithArgs(at, sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(update).WithArgs(at, sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Archive(context.Background(), id, at))
+	assert.ErrorIs(t, repo.Archive(context.Background(), id, at), domain.ErrOrderNotFound, "already archived")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_GetByIDWithProducts(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewOrderRepository(db)
+	orderID, userID := uuid.New(), uuid.New()
+	renamed, deleted := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "orders" WHERE id = $1 ORDER BY "orders"."id" LIMIT $2`)).
+		WithArgs(orderID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(orderID, userID, "completed"))
+	// both items come back from a single query, whatever the number of items
+	mock.ExpectQuery(`SELECT order_items\.\*, products\.name AS current_product_name,.*FROM "order_items" LEFT JOIN products ON products\.id = order_items\.product_id WHERE order_items\.order_id = \$2 ORDER BY order_items\.created_at`).
+		WithArgs("image", orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "product_id", "product_name", "quantity", "unit_price", "current_product_name", "product_image_url"}).
+			AddRow(uuid.New(), orderID, renamed, "Mug", 2, 8.0, "Stoneware Mug", "https://cdn.example.com/mug.jpg").
+			AddRow(uuid.New(), orderID, deleted, "Teapot", 1, 18.0, nil, nil))
+
+	order, err := repo.GetByIDWithProducts(context.Background(), orderID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, order.UserID)
+	require.Len(t, order.Items, 2)
+
+	assert.Equal(t, "Mug", order.Items[0].ProductName, "the snapshot is kept as bought")
+	assert.Equal(t, 8.0, order.Items[0].UnitPrice)
+	require.NotNil(t, order.Items[0].Product)
+	assert.Equal(t, "Stoneware Mug", order.Items[0].Product.Name)
+	assert.Equal(t, "https://cdn.example.com/mug.jpg", order.Items[0].Product.ImageURL)
+
+	assert.Equal(t, "Teapot", order.Items[1].ProductName)
+	assert.Nil(t, order.Items[1].Product, "the product was deleted")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) repository.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	return r.db.WithContext(ctx).Create(models.PasswordResetFromDomain(reset)).Error
+}
+
+func (r *passwordResetRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PasswordReset, error) {
+	var model models.PasswordReset
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *passwordResetRepository) Consume(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	res := r.db.WithContext(ctx).
+		Model(&models.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrResetTokenUsed
+	}
+	return nil
+}
+
+func (r *passwordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", usedAt).Error
+}
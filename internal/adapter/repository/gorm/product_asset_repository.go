@@ -0,0 +1,184 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+type productAssetRepository struct {
+	db *gorm.DB
+}
+
+func NewProductAssetRepository(db *gorm.DB) repository.ProductAssetRepository {
+	return &productAssetRepository{db: db}
+}
+
+// imagesOnly scopes a product's Images preload to images, leaving its documents out.
+func imagesOnly(db *gorm.DB) *gorm.DB {
+	return db.Where("type = ?", string(domain.AssetTypeImage))
+}
+
+// touchProducts moves updated_at on products whose images changed. Images are part of the product
+// response, so its Last-Modified has to move with them.
+func touchProducts(tx *gorm.DB, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Product{}).Where("id IN ?", ids).UpdateColumn("updated_at", at).Error
+}
+
+func (r *productAssetRepository) AddMany(ctx context.Context, assets []domain.ProductAsset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	rows := make([]models.ProductImage, 0, len(assets))
+	var touched []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	now := time.Now()
+	for _, asset := range assets {
+		id := asset.ID
+		if id == uuid.Nil {
+			id = uuid.New()
+		}
+		assetType := asset.Type
+		if assetType == "" {
+			assetType = domain.AssetTypeImage
+		}
+		if assetType == domain.AssetTypeImage && !seen[asset.ProductID] {
+			seen[asset.ProductID] = true
+			touched = append(touched, asset.ProductID)
+		}
+		rows = append(rows, models.ProductImage{
+			ID:          id,
+			ProductID:   asset.ProductID,
+			Type:        string(assetType),
+			URL:         asset.URL,
+			AltText:     asset.AltText,
+			Caption:     asset.Caption,
+			Filename:    asset.Filename,
+			ContentHash: asset.ContentHash,
+			CreatedAt:   now,
+		})
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		return touchProducts(tx, touched, now)
+	})
+}
+
+func (r *productAssetRepository) ListByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) ([]domain.ProductAsset, error) {
+	var rows []models.ProductImage
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND type = ?", productID, string(assetType)).
+		Order("created_at").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.ProductAsset, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.ToDomain())
+	}
+	return out, nil
+}
+
+func (r *productAssetRepository) CountByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ProductImage{}).
+		Where("product_id = ? AND type = ?", productID, string(assetType)).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *productAssetRepository) GetImage(ctx context.Context, productID, imageID uuid.UUID) (*domain.ProductImage, error) {
+	var row models.ProductImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND product_id = ? AND type = ?", imageID, productID, string(domain.AssetTypeImage)).
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	img := row.ToDomain()
+	return &img, nil
+}
+
+func (r *productAssetRepository) UpdateMetadata(ctx context.Context, image domain.ProductImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.ProductImage{}).
+			Where("id = ? AND product_id = ? AND type = ?", image.ID, image.ProductID, string(domain.AssetTypeImage)).
+			Updates(map[string]interface{}{"alt_text": image.AltText, "caption": image.Caption})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrImageNotFound
+		}
+		return touchProducts(tx, []uuid.UUID{image.ProductID}, time.Now())
+	})
+}
+
+func (r *productAssetRepository) DeleteByIDs(ctx context.Context, productID uuid.UUID, assetType domain.AssetType, ids []uuid.UUID) ([]domain.ProductAsset, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []models.ProductImage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND type = ? AND id IN ?", productID, string(assetType), ids).
+			Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		found := make([]uuid.UUID, len(rows))
+		for i, row := range rows {
+			found[i] = row.ID
+		}
+		if err := tx.Where("id IN ?", found).Delete(&models.ProductImage{}).Error; err != nil {
+			return err
+		}
+		if assetType != domain.AssetTypeImage {
+			return nil
+		}
+		return touchProducts(tx, []uuid.UUID{productID}, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.ProductAsset, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.ToDomain())
+	}
+	return out, nil
+}
+
+func (r *productAssetRepository) ExistingProducts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	var found []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&models.Product{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
@@ -0,0 +1,92 @@
+package gorm
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+func TestProductAssetRepository_DeleteByIDs_MixedIDs(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductAssetRepository(db)
+	productID, own, foreign := uuid.New(), uuid.New(), uuid.New()
+
+	// only the product's own image comes back from the locked select, so only it is deleted
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE product_id = $1 AND type = $2 AND id IN ($3,$4) FOR UPDATE`)).
+		WithArgs(productID, "image", own, foreign).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}).AddRow(own, productID, "https://res.example.com/a.jpg"))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "product_images" WHERE id IN ($1)`)).
+		WithArgs(own).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "updated_at"=$1 WHERE id IN ($2)`)).
+		WithArgs(sqlmock.AnyArg(), productID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteByIDs(context.Background(), productID, domain.AssetTypeImage, []uuid.UUID{own, foreign})
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, own, deleted[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductAssetRepository_UpdateMetadata_TouchesProduct(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductAssetRepository(db)
+	productID, imageID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "product_images" SET "alt_text"=$1,"caption"=$2 WHERE id = $3 AND product_id = $4 AND type = $5`)).
+		WithArgs("Front", "", imageID, productID, "image").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "updated_at"=$1 WHERE id IN ($2)`)).
+		WithArgs(sqlmock.AnyArg(), productID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.UpdateMetadata(context.Background(), domain.ProductImage{ID: imageID, ProductID: productID, AltText: "Front"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductAssetRepository_ListByProduct_ScopedToType(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductAssetRepository(db)
+	productID, manual := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE product_id = $1 AND type = $2 ORDER BY created_at`)).
+		WithArgs(productID, "document").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "type", "url", "filename"}).
+			AddRow(manual, productID, "document", "https://res.example.com/raw/upload/manual.pdf", "manual.pdf"))
+
+	docs, err := repo.ListByProduct(context.Background(), productID, domain.AssetTypeDocument)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, domain.AssetTypeDocument, docs[0].Type)
+	assert.Equal(t, "manual.pdf", docs[0].Filename)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductAssetRepository_ExistingProducts(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductAssetRepository(db)
+	known, unknown := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id" FROM "products" WHERE id IN ($1,$2)`)).
+		WithArgs(known, unknown).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(known))
+
+	existing, err := repo.ExistingProducts(context.Background(), []uuid.UUID{known, unknown})
+	require.NoError(t, err)
+	assert.True(t, existing[known])
+	assert.False(t, existing[unknown])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
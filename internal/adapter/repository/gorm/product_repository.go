@@ -1,8 +1,13 @@
 package gorm
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -12,6 +17,11 @@ import (
 	"github.com/minilik/ecommerce/internal/domain/repository"
 )
 
+// productUniqueErrors maps the unique indexes of the products table to domain errors.
+var productUniqueErrors = map[string]error{
+	"idx_products_owner_external_id": domain.ErrExternalIDExists,
+}
+
 type productRepository struct {
 	db *gorm.DB
 }
@@ -26,12 +36,27 @@ func (r *productRepository) Create(ctx context.Context, product *domain.Product)
 		model.ID = uuid.New()
 	}
 	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return err
+		return translateUnique(err, productUniqueErrors)
 	}
 	product.ID = model.ID
 	return nil
 }
 
+func (r *productRepository) GetByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*domain.Product, error) {
+	var model models.Product
+	err := r.db.WithContext(ctx).
+		Preload("Images", imagesOnly).
+		Where("user_id = ? AND external_id = ?", ownerID, externalID).
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
 func (r *productRepository) Update(ctx context.Context, product *domain.Product) error {
 	data := map[string]interface{}{
 		"name":        product.Name,
@@ -39,6 +64,7 @@ func (r *productRepository) Update(ctx context.Context, product *domain.Product)
 		"price":       product.Price,
 		"stock":       product.Stock,
 		"category":    product.Category,
+		"category_id": product.CategoryId,
 		"user_id":     product.UserID,
 		"updated_at":  product.UpdatedAt,
 	}
@@ -68,7 +94,7 @@ func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 	var model models.Product
-	if err := r.db.WithContext(ctx).Preload("Images").First(&model, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Images", imagesOnly).First(&model, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrProductNotFound
 		}
@@ -78,6 +104,152 @@ func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 	return model.ToDomain(), nil
 }
 
+// GetByIDs loads all products whose ids are in the given list using a single query.
+// Ids that do not exist are simply absent from the result; ordering is not guaranteed.
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	if len(ids) == 0 {
+		return []domain.Product{}, nil
+	}
+	var records []models.Product
+	if err := r.db.WithContext(ctx).Preload("Images", imagesOnly).Where("id IN ?", ids).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	products := make([]domain.Product, 0, len(records))
+	for _, model := range records {
+		if domainProduct := model.ToDomain(); domainProduct != nil {
+			products = append(products, *domainProduct)
+		}
+	}
+	return products, nil
+}
+
+// DecrementStockBatch decrements all quantities with one UPDATE joined against a VALUES list.
+// Only rows with sufficient stock are updated; the ids they return tell which products came up short.
+func (r *productRepository) DecrementStockBatch(ctx context.Context, quantities map[uuid.UUID]int) ([]uuid.UUID, error) {
+	if len(quantities) == 0 {
+		return nil, nil
+	}
+	// fixed id order keeps row locks consistent between concurrent batches
+	ids := make([]uuid.UUID, 0, len(quantities))
+	for id, qty := range quantities {
+		if qty <= 0 {
+			return nil, fmt.Errorf("quantity for product %s must be greater than zero", id)
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+
+	values := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids)*2+1)
+	args = append(args, time.Now())
+	for _, id := range ids {
+		values = append(values, "(?::uuid, ?::int)")
+		args = append(args, id, quantities[id])
+	}
+	query := "UPDATE products AS p SET stock = p.stock - v.qty, updated_at = ? " +
+		"FROM (VALUES " + strings.Join(values, ", ") + ") AS v(id, qty) " +
+		"WHERE p.id = v.id AND p.stock >= v.qty RETURNING p.id"
+
+	var updated []uuid.UUID
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&updated).Error; err != nil {
+		return nil, err
+	}
+
+	done := make(map[uuid.UUID]struct{}, len(updated))
+	for _, id := range updated {
+		done[id] = struct{}{}
+	}
+	var short []uuid.UUID
+	for _, id := range ids {
+		if _, ok := done[id]; !ok {
+			short = append(short, id)
+		}
+	}
+	return short, nil
+}
+
+func (r *productRepository) IncrementStock(ctx context.Context, id uuid.UUID, qty int) error {
+	res := r.db.WithContext(ctx).Model(&models.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"stock": gorm.Expr("stock + ?", qty), "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
+}
+
+// CountByCategory groups on products.category, the column product create and update write.
+func (r *productRepository) CountByCategory(ctx context.Context) ([]domain.CategoryCount, error) {
+	var counts []domain.CategoryCount
+	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Select("category AS name, COUNT(*) AS count").
+		Group("category").
+		Order("count DESC, name").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *productRepository) ListAfter(ctx context.Context, after uuid.UUID, limit int) ([]domain.Product, error) {
+	var records []models.Product
+	err := r.db.WithContext(ctx).
+		Preload("Images", imagesOnly).
+		Where("id > ?", after).
+		Order("id").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	products := make([]domain.Product, 0, len(records))
+	for _, model := range records {
+		if domainProduct := model.ToDomain(); domainProduct != nil {
+			products = append(products, *domainProduct)
+		}
+	}
+	return products, nil
+}
+
+// productOrder maps a ProductFilter.Sort to an ORDER BY clause; id breaks ties so pages are stable.
+func productOrder(sort string) string {
+	switch sort {
+	case repository.SortPriceAsc:
+		return "price ASC, id"
+	case repository.SortPriceDesc:
+		return "price DESC, id"
+	case repository.SortName:
+		return "LOWER(name) ASC, id"
+	default:
+		return "created_at DESC, id"
+	}
+}
+
+// likeEscaper makes user input match literally inside a LIKE pattern (backslash is Postgres' default escape).
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SuggestByPrefix matches lower(name) LIKE 'prefix%', which the idx_products_name_prefix
+// expression index (text_pattern_ops) can serve as a range scan.
+func (r *productRepository) SuggestByPrefix(ctx context.Context, prefix string, limit int) ([]domain.ProductSuggestion, error) {
+	var rows []domain.ProductSuggestion
+	err := r.db.WithContext(ctx).
+		Model(&models.Product{}).
+		Select("id", "name").
+		Where("lower(name) LIKE ?", likeEscaper.Replace(strings.ToLower(prefix))+"%").
+		Order("lower(name)").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *productRepository) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Product, int64, error) {
 	var (
 		productList []models.Product
@@ -89,9 +261,22 @@ func (r *productRepository) List(ctx context.Context, filter repository.ProductF
 		search := "%" + strings.ToLower(filter.Search) + "%"
 		tx = tx.Where("LOWER(name) LIKE ?", search)
 	}
+	if filter.CategoryID != uuid.Nil {
+		tx = tx.Where("category_id = ?", filter.CategoryID)
+	}
+	switch from, to := filter.CreatedFrom, filter.CreatedTo; {
+	case !from.IsZero() && !to.IsZero():
+		tx = tx.Where("created_at BETWEEN ? AND ?", from, to)
+	case !from.IsZero():
+		tx = tx.Where("created_at >= ?", from)
+	case !to.IsZero():
+		tx = tx.Where("created_at <= ?", to)
+	}
 
-	if err := tx.Count(&total).Error; err != nil {
-		return nil, 0, err
+	if !filter.SkipCount {
+		if err := tx.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
 	}
 
 	if filter.Limit > 0 {
@@ -103,7 +288,7 @@ func (r *productRepository) List(ctx context.Context, filter repository.ProductF
 
 	//TODO: fetch the from category
 
-	if err := tx.Preload("Images").Order("created_at DESC").Find(&productList).Error; err != nil {
+	if err := tx.Preload("Images", imagesOnly).Order(productOrder(filter.Sort)).Find(&productList).Error; err != nil {
 		return nil, 0, err
 	}
 	// it already under session based execution, so no need to create a new transaction
@@ -0,0 +1,331 @@
+package gorm
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	return db, mock
+}
+
+func TestProductRepository_DecrementStockBatch_OneShort(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+
+	enough := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	short := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	// a single statement carries both items; only the one with enough stock is returned
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE products AS p SET stock = p.stock - v.qty")).
+		WithArgs(sqlmock.AnyArg(), enough, 2, short, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(enough.String()))
+
+	got, err := repo.DecrementStockBatch(context.Background(), map[uuid.UUID]int{short: 5, enough: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{short}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_DecrementStockBatch_RejectsNonPositive(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+
+	_, err := repo.DecrementStockBatch(context.Background(), map[uuid.UUID]int{uuid.New(): 0})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_IncrementStock(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("adds in place", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "stock"=stock + $1,"updated_at"=$2 WHERE id = $3`)).
+			WithArgs(3, sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.IncrementStock(context.Background(), id, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing product", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.ErrorIs(t, repo.IncrementStock(context.Background(), id, 3), domain.ErrProductNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_GetByIDs(t *testing.T) {
+	t.Run("one IN query, missing ids left out", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+		found, missing := uuid.New(), uuid.New()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE id IN ($1,$2)`)).
+			WithArgs(found, missing).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(found, "Mug"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" = $1 AND type = $2`)).
+			WithArgs(found, "image").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}).AddRow(uuid.New(), found, "https://example.com/mug.jpg"))
+
+		products, err := repo.GetByIDs(context.Background(), []uuid.UUID{found, missing})
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.Equal(t, found, products[0].ID)
+		assert.Len(t, products[0].Images, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty input skips the query", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		products, err := repo.GetByIDs(context.Background(), nil)
+		require.NoError(t, err)
+		assert.NotNil(t, products)
+		assert.Empty(t, products)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_List_CategoryScope(t *testing.T) {
+	categoryID := uuid.New()
+	filter := repository.ProductFilter{CategoryID: categoryID, Limit: 10}
+
+	t.Run("only products of the category", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+		productID := uuid.New()
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE category_id = $1`)).
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE category_id = $1 ORDER BY created_at DESC, id`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "category_id"}).AddRow(productID, "Sneaker", categoryID))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" = $1 AND type = $2`)).
+			WithArgs(productID, "image").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}))
+
+		products, total, err := repo.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, products, 1)
+		assert.Equal(t, categoryID, products[0].CategoryId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty category", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE category_id = $1`)).
+			WithArgs(categoryID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE category_id = $1`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		products, total, err := repo.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, products)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_List_SkipCount(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+
+	// sqlmock fails on any query it does not expect, so a count query would fail the test
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" ORDER BY created_at DESC, id LIMIT $1`)).
+		WithArgs(11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	products, total, err := repo.List(context.Background(), repository.ProductFilter{Limit: 11, SkipCount: true})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_List_CreatedRange(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+	newer, older := uuid.New(), uuid.New()
+
+	t.Run("both bounds", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE created_at BETWEEN $1 AND $2`)).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE created_at BETWEEN $1 AND $2 ORDER BY created_at DESC, id LIMIT $3`)).
+			WithArgs(from, to, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).
+				AddRow(newer, "Lamp", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)).
+				AddRow(older, "Desk", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" IN ($1,$2) AND type = $3`)).
+			WithArgs(newer, older, "image").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "url"}))
+
+		products, total, err := repo.List(context.Background(), repository.ProductFilter{Limit: 10, CreatedFrom: from, CreatedTo: to})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, products, 2)
+		assert.Equal(t, newer, products[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("open-ended", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE created_at >= $1 ORDER BY created_at DESC, id`)).
+			WithArgs(from).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		_, _, err := repo.List(context.Background(), repository.ProductFilter{SkipCount: true, CreatedFrom: from})
+		require.NoError(t, err)
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE created_at <= $1 ORDER BY created_at DESC, id`)).
+			WithArgs(to).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		_, _, err = repo.List(context.Background(), repository.ProductFilter{SkipCount: true, CreatedTo: to})
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCategoryRepository_GetByID_NotFound(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCategoryRepository(db)
+	id := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "category" WHERE id = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetByID(context.Background(), id)
+	assert.ErrorIs(t, err, domain.ErrCategoryNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_CountByCategory(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+
+	// counted on the products.category column itself, no join to the category table
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT category AS name, COUNT(*) AS count FROM "products" GROUP BY "category" ORDER BY count DESC, name`)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "count"}).
+			AddRow("Shoes", 3).
+			AddRow("Hats", 2).
+			AddRow("", 1))
+
+	counts, err := repo.CountByCategory(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CategoryCount{
+		{Name: "Shoes", Count: 3},
+		{Name: "Hats", Count: 2},
+		{Name: "", Count: 1},
+	}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListAfter(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+	after := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	next := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE id > $1 ORDER BY id LIMIT $2`)).
+		WithArgs(after, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(next, "Lamp"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_images" WHERE "product_images"."product_id" = $1 AND type = $2`)).
+		WithArgs(next, "image").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}))
+
+	products, err := repo.ListAfter(context.Background(), after, 2)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, next, products[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_SuggestByPrefix(t *testing.T) {
+	lamp := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	lantern := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	query := `SELECT "id","name" FROM "products" WHERE lower(name) LIKE $1 ORDER BY lower(name) LIMIT $2`
+
+	t.Run("prefix match capped at limit", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs("la%", 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(lamp, "Lamp").AddRow(lantern, "Lantern"))
+
+		got, err := repo.SuggestByPrefix(context.Background(), "La", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ProductSuggestion{{ID: lamp, Name: "Lamp"}, {ID: lantern, Name: "Lantern"}}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("wildcards are matched literally", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		repo := NewProductRepository(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(query)).
+			WithArgs(`100\% c\_o%`, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		got, err := repo.SuggestByPrefix(context.Background(), "100% C_o", 10)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductRepository_Create_ExternalIDConflict(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewProductRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "products"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_products_owner_external_id"})
+	mock.ExpectRollback()
+
+	err := repo.Create(context.Background(), &domain.Product{ID: uuid.New(), Name: "Lamp", UserID: uuid.New(), ExternalID: "sku-42"})
+	assert.ErrorIs(t, err, domain.ErrExternalIDExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,113 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+var productViewUniqueErrors = map[string]error{
+	"idx_product_views_owner_name": domain.ErrProductViewExists,
+}
+
+type productViewRepository struct {
+	db *gorm.DB
+}
+
+func NewProductViewRepository(db *gorm.DB) repository.ProductViewRepository {
+	return &productViewRepository{db: db}
+}
+
+func (r *productViewRepository) Create(ctx context.Context, view *domain.ProductView) error {
+	model, err := models.ProductViewFromDomain(view)
+	if err != nil {
+		return err
+	}
+	if model.ID == uuid.Nil {
+		model.ID = uuid.New()
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return translateUnique(err, productViewUniqueErrors)
+	}
+	view.ID = model.ID
+	return nil
+}
+
+func (r *productViewRepository) Update(ctx context.Context, view *domain.ProductView) error {
+	model, err := models.ProductViewFromDomain(view)
+	if err != nil {
+		return err
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.ProductView{}).
+		Where("id = ? AND owner_id = ?", view.ID, view.OwnerID).
+		Updates(map[string]interface{}{
+			"name":       model.Name,
+			"filter":     model.Filter,
+			"updated_at": model.UpdatedAt,
+		})
+	if result.Error != nil {
+		return translateUnique(result.Error, productViewUniqueErrors)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductViewNotFound
+	}
+	return nil
+}
+
+func (r *productViewRepository) Delete(ctx context.Context, ownerID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(&models.ProductView{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrProductViewNotFound
+	}
+	return nil
+}
+
+func (r *productViewRepository) GetByID(ctx context.Context, ownerID, id uuid.UUID) (*domain.ProductView, error) {
+	return r.first(ctx, "id = ? AND owner_id = ?", id, ownerID)
+}
+
+// FindByName returns nil, nil when the owner has no view with that name.
+func (r *productViewRepository) FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*domain.ProductView, error) {
+	view, err := r.first(ctx, "owner_id = ? AND name = ?", ownerID, name)
+	if errors.Is(err, domain.ErrProductViewNotFound) {
+		return nil, nil
+	}
+	return view, err
+}
+
+func (r *productViewRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.ProductView, error) {
+	var records []models.ProductView
+	if err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("name").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	views := make([]domain.ProductView, 0, len(records))
+	for i := range records {
+		view, err := records[i].ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *view)
+	}
+	return views, nil
+}
+
+func (r *productViewRepository) first(ctx context.Context, query string, args ...interface{}) (*domain.ProductView, error) {
+	var model models.ProductView
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProductViewNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain()
+}
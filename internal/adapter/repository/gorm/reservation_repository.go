@@ -0,0 +1,72 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) repository.ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	return r.db.WithContext(ctx).Create(models.ReservationFromDomain(reservation)).Error
+}
+
+func (r *reservationRepository) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	var model models.Reservation
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *reservationRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.Reservation, error) {
+	var records []models.Reservation
+	// lock the batch so a concurrent sweep skips it instead of waiting
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND expires_at < ?", string(domain.ReservationStatusActive), before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+	reservations := make([]domain.Reservation, 0, len(records))
+	for _, rec := range records {
+		reservations = append(reservations, *rec.ToDomain())
+	}
+	return reservations, nil
+}
+
+func (r *reservationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ReservationStatus) error {
+	res := r.db.WithContext(ctx).Model(&models.Reservation{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     string(status),
+		"updated_at": time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrReservationNotFound
+	}
+	return nil
+}
@@ -20,18 +20,22 @@ func NewUnitOfWork(db *gorm.DB) repository.UnitOfWork {
 func (u *unitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
 	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		provider := &repositoryProvider{
-			users:    NewUserRepository(tx),
-			products: NewProductRepository(tx),
-			orders:   NewOrderRepository(tx),
+			users:        NewUserRepository(tx),
+			products:     NewProductRepository(tx),
+			orders:       NewOrderRepository(tx),
+			reservations: NewReservationRepository(tx),
+			resets:       NewPasswordResetRepository(tx),
 		}
 		return fn(provider)
 	})
 }
 
 type repositoryProvider struct {
-	users    repository.UserRepository
-	products repository.ProductRepository
-	orders   repository.OrderRepository
+	users        repository.UserRepository
+	products     repository.ProductRepository
+	orders       repository.OrderRepository
+	reservations repository.ReservationRepository
+	resets       repository.PasswordResetRepository
 }
 
 func (p *repositoryProvider) Users() repository.UserRepository {
@@ -45,3 +49,11 @@ func (p *repositoryProvider) Products() repository.ProductRepository {
 func (p *repositoryProvider) Orders() repository.OrderRepository {
 	return p.orders
 }
+
+func (p *repositoryProvider) Reservations() repository.ReservationRepository {
+	return p.reservations
+}
+
+func (p *repositoryProvider) PasswordResets() repository.PasswordResetRepository {
+	return p.resets
+}
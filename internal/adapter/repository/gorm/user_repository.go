@@ -3,15 +3,23 @@ package gorm
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/minilik/ecommerce/internal/adapter/repository/gorm/models"
 	"github.com/minilik/ecommerce/internal/domain"
 	"github.com/minilik/ecommerce/internal/domain/repository"
 )
 
+// userUniqueErrors maps the unique indexes of the users table to domain errors.
+var userUniqueErrors = map[string]error{
+	"idx_users_email":    domain.ErrEmailAlreadyExists,
+	"idx_users_username": domain.ErrUsernameAlreadyExists,
+}
+
 type userRepository struct {
 	db *gorm.DB
 }
@@ -26,7 +34,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		model.ID = uuid.New()
 	}
 	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return err
+		return translateUnique(err, userUniqueErrors)
 	}
 	user.ID = model.ID
 	return nil
@@ -75,3 +83,74 @@ func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role doma
 	}
 	return nil
 }
+
+// SetRole locks the user and, when an admin is demoted, every admin row before counting them, so
+// two admins demoting each other at once cannot leave the store without an admin.
+func (r *userRepository) SetRole(ctx context.Context, id uuid.UUID, role domain.Role) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if domain.Role(user.Role) == domain.RoleAdmin && role != domain.RoleAdmin {
+			var admins []uuid.UUID
+			if err := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role = ?", string(domain.RoleAdmin)).
+				Pluck("id", &admins).Error; err != nil {
+				return err
+			}
+			if len(admins) <= 1 {
+				return domain.ErrLastAdmin
+			}
+		}
+		return tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"role":       string(role),
+			"updated_at": time.Now(),
+		}).Error
+	})
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashed string) error {
+	now := time.Now()
+	res := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":            hashed,
+		"password_changed_at": now,
+		"updated_at":          now,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	res := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":          email,
+		"email_verified": false,
+		"updated_at":     time.Now(),
+	})
+	if res.Error != nil {
+		return translateUnique(res.Error, userUniqueErrors)
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	res := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND email = ?", id, email).Updates(map[string]interface{}{
+		"email_verified": true,
+		"updated_at":     time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gorm
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// Two registrations for the same email can both pass the service's existence check; the one that
+// loses the race at the unique index must surface as the domain error, not a raw driver error.
+func TestUserRepository_Create_UniqueViolation(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+	insert := regexp.QuoteMeta(`INSERT INTO "users"`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{
+		Code:           "23505",
+		Message:        `duplicate key value violates unique constraint "idx_users_email"`,
+		ConstraintName: "idx_users_email",
+	})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_users_username"})
+	mock.ExpectRollback()
+
+	newUser := func() *domain.User {
+		return &domain.User{ID: uuid.New(), Username: "jane", Email: "jane@example.com", Password: "hash", Role: domain.RoleUser}
+	}
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, newUser()))
+	assert.ErrorIs(t, repo.Create(ctx, newUser()), domain.ErrEmailAlreadyExists)
+	assert.ErrorIs(t, repo.Create(ctx, newUser()), domain.ErrUsernameAlreadyExists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTranslateUnique_OtherErrorsPassThrough(t *testing.T) {
+	other := &pgconn.PgError{Code: "23505", ConstraintName: "idx_something_else"}
+	assert.Same(t, other, translateUnique(other, userUniqueErrors))
+
+	notNull := &pgconn.PgError{Code: "23502", ConstraintName: "idx_users_email"}
+	assert.Same(t, notNull, translateUnique(notNull, userUniqueErrors))
+}
+
+func TestUserRepository_SetRole_LastAdmin(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+	adminID := uuid.New()
+	lockUser := regexp.QuoteMeta(`SELECT * FROM "users" WHERE id = $1 ORDER BY "users"."id" LIMIT $2 FOR UPDATE`)
+	lockAdmins := regexp.QuoteMeta(`SELECT "id" FROM "users" WHERE role = $1 FOR UPDATE`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockUser).WithArgs(adminID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(adminID, "admin"))
+	mock.ExpectQuery(lockAdmins).WithArgs("admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(adminID))
+	mock.ExpectRollback()
+
+	err := repo.SetRole(context.Background(), adminID, domain.RoleUser)
+	require.ErrorIs(t, err, domain.ErrLastAdmin)
+
+	otherAdmin := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockUser).WithArgs(adminID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(adminID, "admin"))
+	mock.ExpectQuery(lockAdmins).WithArgs("admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(adminID).AddRow(otherAdmin))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "role"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs("user", sqlmock.AnyArg(), adminID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SetRole(context.Background(), adminID, domain.RoleUser))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
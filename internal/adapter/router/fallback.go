@@ -0,0 +1,68 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+// notFound answers unmatched paths in the same envelope as every other error.
+func notFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, response.ErrorCode("not_found", "resource not found", []string{c.Request.Method + " " + c.Request.URL.Path + " does not exist"}))
+}
+
+// methodNotAllowed answers a known path requested with the wrong method, listing the methods it
+// does serve in Allow. The API-wide OPTIONS preflight route is left out: it matches every path,
+// so a path that only it matches is really unknown and gets 404.
+func methodNotAllowed(routes gin.RoutesInfo) gin.HandlerFunc {
+	preflight := APIBasePath + "/*path"
+	return func(c *gin.Context) {
+		var allowed []string
+		for _, route := range routes {
+			if route.Path == preflight || !routeMatches(route.Path, c.Request.URL.Path) {
+				continue
+			}
+			if !slices.Contains(allowed, route.Method) {
+				allowed = append(allowed, route.Method)
+			}
+		}
+		if len(allowed) == 0 {
+			notFound(c)
+			return
+		}
+		if strings.HasPrefix(c.Request.URL.Path, APIBasePath+"/") && !slices.Contains(allowed, http.MethodOptions) {
+			allowed = append(allowed, http.MethodOptions)
+		}
+		slices.Sort(allowed)
+		c.Header("Allow", strings.Join(allowed, ", "))
+		c.JSON(http.StatusMethodNotAllowed, response.ErrorCode("method_not_allowed", "method not allowed", []string{c.Request.Method + " is not supported here; use " + strings.Join(allowed, ", ")}))
+	}
+}
+
+// routeMatches reports whether path fits a gin route pattern with :param and *catch-all segments.
+func routeMatches(pattern, path string) bool {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range want {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(got) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != got[i] {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
@@ -1,13 +1,20 @@
 package router
 
 import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
 
 	"github.com/minilik/ecommerce/internal/adapter/handler"
 	"github.com/minilik/ecommerce/internal/adapter/middleware"
 	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/metrics"
 	"github.com/minilik/ecommerce/pkg/response"
 
 	// Import usecase packages for Swagger type references
@@ -24,12 +31,48 @@ const (
 )
 
 type Dependencies struct {
-	AuthHandler    *handler.AuthHandler
-	ProductHandler *handler.ProductHandler
-	OrderHandler   *handler.OrderHandler
-	AdminHandler   *handler.AdminHandler
-	AuthMiddleware *middleware.AuthMiddleware
-	RateLimiter    *middleware.RateLimitMiddleware
+	AuthHandler        *handler.AuthHandler
+	ProductHandler     *handler.ProductHandler
+	OrderHandler       *handler.OrderHandler
+	AdminHandler       *handler.AdminHandler
+	ProductViewHandler *handler.ProductViewHandler
+	ReservationHandler *handler.ReservationHandler
+	AuthMiddleware     *middleware.AuthMiddleware
+	RateLimiter        *middleware.RateLimitMiddleware
+	// ConcurrencyLimiter caps in-flight requests per IP; nil disables it.
+	ConcurrencyLimiter *middleware.ConcurrencyLimitMiddleware
+	CorsMaxAge         time.Duration
+	Logger             *zap.Logger
+	// DebugRoutes lists route groups (auth, products, categories, orders, admin) whose bodies are debug-logged.
+	DebugRoutes []string
+	// SlowRequestThreshold emits a warning for requests slower than this; zero disables it.
+	SlowRequestThreshold time.Duration
+	// UploadMetrics records request body sizes on upload routes; nil disables it.
+	UploadMetrics *metrics.Histogram
+	// Transaction wraps a route in one database transaction; add it per route for handlers that
+	// read middleware.TxRepositories.
+	Transaction gin.HandlerFunc
+	// ReadOnly rejects writes other than login with 503.
+	ReadOnly bool
+	// TokenHeader is the custom access-token header, if any, so CORS preflights allow it.
+	TokenHeader string
+	// RequestTimeout bounds each request's context; UploadTimeout replaces it on upload routes.
+	// Zero means no deadline.
+	RequestTimeout time.Duration
+	UploadTimeout  time.Duration
+	// HealthHandler serves /health/ready; nil leaves only the liveness check.
+	HealthHandler *handler.HealthHandler
+}
+
+// timeoutOverrides gives upload routes their own deadline and exempts the streaming export.
+func timeoutOverrides(deps Dependencies) map[string]time.Duration {
+	return map[string]time.Duration{
+		middleware.RouteKey(http.MethodPost, APIBasePath+"/products/:id/images"):    deps.UploadTimeout,
+		middleware.RouteKey(http.MethodPost, APIBasePath+"/products/:id/documents"): deps.UploadTimeout,
+		middleware.RouteKey(http.MethodPost, APIBasePath+"/products/images/bulk"):   deps.UploadTimeout,
+		// streams the whole catalog, however long that takes
+		middleware.RouteKey(http.MethodGet, APIBasePath+"/admin/products/export"): 0,
+	}
 }
 
 // COMMENTS ARE FOR SWAGGER DOCS PURPOSES TO ENABLE AUTOMATICALLY GENERATING THE DOCS FROM THE CODE
@@ -44,24 +87,34 @@ type Dependencies struct {
 func Setup(deps Dependencies) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery())
-	r.Use(middleware.CorsMiddleware())
+	r.Use(middleware.CorsMiddleware(deps.CorsMaxAge, deps.TokenHeader))
+	r.Use(middleware.SlowRequest(deps.Logger, deps.SlowRequestThreshold))
+	r.Use(middleware.Timeout(deps.RequestTimeout, timeoutOverrides(deps)))
 
 	// Swagger UI - register before rate limiter to exclude it
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Apply rate limiter only to API routes (excludes Swagger)
+	// Apply rate and concurrency limits only to API routes (excludes Swagger)
 	if deps.RateLimiter != nil {
-		r.Use(func(c *gin.Context) {
-			// Skip rate limiting for Swagger routes
-			if c.Request.URL.Path == "/swagger" || len(c.Request.URL.Path) > 8 && c.Request.URL.Path[:9] == "/swagger/" {
-				c.Next()
-				return
-			}
-			deps.RateLimiter.RateLimit()(c)
-		})
+		r.Use(skipSwagger(deps.RateLimiter.RateLimit()))
+	}
+	if deps.ConcurrencyLimiter != nil {
+		r.Use(skipSwagger(deps.ConcurrencyLimiter.Limit()))
 	}
 
+	// Maintenance mode: reads and login keep working, every other write gets 503. GET routes that
+	// write add middleware.ReadOnlyWrite themselves
+	r.Use(middleware.ReadOnly(deps.ReadOnly,
+		APIBasePath+"/auth/login",
+		// reads that take their input as a POST body
+		APIBasePath+"/products/batch",
+		APIBasePath+"/products/availability",
+	))
+
 	v1 := r.Group(APIBasePath) // versioning apis
+	debugLog := func(group string) gin.HandlerFunc {
+		return middleware.DebugLog(deps.Logger, slices.Contains(deps.DebugRoutes, group))
+	}
 	v1.GET("/health", func(c *gin.Context) {
 		// @Summary Health check
 		// @Description Check API health status
@@ -69,19 +122,33 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Produce json
 		// @Success 200 {object} response.Base
 		// @Router /health [get]
-		c.JSON(200, response.SuccessBase("ok", nil))
+		c.JSON(200, response.SuccessEmpty("ok"))
 	})
+	if deps.HealthHandler != nil {
+		// @Summary Readiness check
+		// @Description Report the status of each dependency; 503 when a critical one is down
+		// @Tags Health
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Router /health/ready [get]
+		v1.GET("/health/ready", deps.HealthHandler.Ready)
+	}
+	// CORS preflight for every API route; the CORS middleware answers it before auth or rate limits run
+	v1.OPTIONS("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
 	// auth endpoints: public access
 	auth := v1.Group("/auth")
+	auth.Use(debugLog("auth"))
 	{
 		// @Summary Register a new user
-		// @Description Create a new user account (role=user)
+		// @Description Create a new user account (role=user). Refused with 403 when auth.registration_enabled is false
 		// @Tags Auth
 		// @Accept json
 		// @Produce json
 		// @Param payload body authusecase.RegisterInput true "Register payload"
 		// @Success 201 {object} response.Base
 		// @Failure 400 {object} response.Base
+		// @Failure 403 {object} response.Base
 		// @Router /auth/register [post]
 		auth.POST("/register", deps.AuthHandler.Register)
 
@@ -96,9 +163,72 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Failure 401 {object} response.Base
 		// @Router /auth/login [post]
 		auth.POST("/login", deps.AuthHandler.Login)
+
+		// @Summary Update email
+		// @Description Change the authenticated user's email; requires the current password
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Security BearerAuth
+		// @Param payload body authusecase.UpdateEmailInput true "Update email payload"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 401 {object} response.Base
+		// @Router /auth/email [put]
+		auth.PUT("/email", deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin, domain.RoleUser), deps.AuthHandler.UpdateEmail)
+		// @Summary Token claims
+		// @Description Return the claims parsed from the caller's token (no database lookup)
+		// @Tags Auth
+		// @Produce json
+		// @Security BearerAuth
+		// @Success 200 {object} response.Base
+		// @Failure 401 {object} response.Base
+		// @Router /auth/whoami [get]
+		auth.GET("/whoami", deps.AuthMiddleware.RequireAuth(), deps.AuthHandler.WhoAmI)
+		// @Summary Send email verification
+		// @Description Send a signed, expiring verification link to the authenticated user's email
+		// @Tags Auth
+		// @Produce json
+		// @Security BearerAuth
+		// @Success 202 {object} response.Base
+		// @Failure 401 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Router /auth/verify/send [post]
+		auth.POST("/verify/send", deps.AuthMiddleware.RequireAuth(), deps.AuthHandler.SendEmailVerification)
+		// @Summary Verify email
+		// @Description Mark an email as verified using the token from a verification link
+		// @Tags Auth
+		// @Produce json
+		// @Param token query string true "Verification token"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Router /auth/verify [get]
+		auth.GET("/verify", middleware.ReadOnlyWrite(deps.ReadOnly), deps.AuthHandler.VerifyEmail)
+		// @Summary Request password reset
+		// @Description Send a single-use, expiring password reset link. The response is the same whether or not the email is registered
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Param payload body authusecase.ForgotPasswordInput true "Account email"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Router /auth/password/forgot [post]
+		auth.POST("/password/forgot", deps.AuthHandler.ForgotPassword)
+		// @Summary Reset password
+		// @Description Set a new password using the token from a reset link; the token works once
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Param payload body authusecase.ResetPasswordInput true "Reset token and new password"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Router /auth/password/reset [post]
+		auth.POST("/password/reset", deps.AuthHandler.ResetPassword)
 	}
 	// Query endpoints: Public access
 	product := v1.Group("/products")
+	product.Use(debugLog("products"))
 	{
 		// @Summary List products
 		// @Description List products with pagination (public)
@@ -107,23 +237,135 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Param page query int false "Page number"
 		// @Param limit query int false "Page size"
 		// @Param search query string false "Search term"
+		// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+		// @Param view query string false "Saved view ID; its filter fills parameters not given (admin only)"
+		// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+		// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+		// @Param created_from query string false "Only products created at or after this time: RFC 3339 or YYYY-MM-DD (start of day, UTC)"
+		// @Param created_to query string false "Only products created at or before this time: RFC 3339 or YYYY-MM-DD (whole day, UTC)"
 		// @Success 200 {object} response.Paginated
+		// @Failure 400 {object} response.Base
 		// @Router /products [get]
-		product.GET("", deps.ProductHandler.List)
+		product.GET("", deps.AuthMiddleware.OptionalAuth(), deps.ProductHandler.List)
+		product.HEAD("", middleware.HeadOnly(), deps.AuthMiddleware.OptionalAuth(), deps.ProductHandler.List)
+
+		// @Summary Suggest products
+		// @Description Typeahead: id and name of up to product.suggest_limit products whose name starts with q (public)
+		// @Tags Products
+		// @Produce json
+		// @Param q query string true "Name prefix"
+		// @Success 200 {object} response.Base
+		// @Success 304 "Not modified"
+		// @Failure 400 {object} response.Base
+		// @Router /products/suggest [get]
+		product.GET("/suggest", deps.ProductHandler.Suggest)
+
+		// @Summary List product categories
+		// @Description Distinct categories that products are filed under, by name, each with its number of products (public)
+		// @Tags Products
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Success 304 "Not modified"
+		// @Router /products/categories [get]
+		product.GET("/categories", deps.ProductHandler.ListCategories)
 
 		// @Summary Get product
-		// @Description Get product details (public)
+		// @Description Get product details (public). Responses carry a weak ETag and Last-Modified; send If-None-Match or If-Modified-Since to get 304 when unchanged
 		// @Tags Products
 		// @Produce json
 		// @Param id path string true "Product ID"
+		// @Param If-None-Match header string false "ETag from a previous response"
+		// @Param If-Modified-Since header string false "Last-Modified from a previous response; ignored when If-None-Match is sent"
 		// @Success 200 {object} response.Base
+		// @Success 304 "Not modified"
 		// @Failure 404 {object} response.Base
 		// @Router /products/{id} [get]
 		product.GET("/:id", deps.ProductHandler.Get)
+		product.HEAD("/:id", middleware.HeadOnly(), deps.ProductHandler.Get)
+
+		// @Summary Get products by ids
+		// @Description Resolve many products in one call, preserving request order (public)
+		// @Tags Products
+		// @Accept json
+		// @Produce json
+		// @Param payload body productusecase.BatchGetInput true "Product ids"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Router /products/batch [post]
+		product.POST("/batch", deps.ProductHandler.BatchGet)
+
+		// @Summary Check cart availability
+		// @Description Check a cart against current stock without placing an order (public). Returns per-item available quantity and whether it is in stock
+		// @Tags Products
+		// @Accept json
+		// @Produce json
+		// @Param payload body []productusecase.AvailabilityItemInput true "Cart lines"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Router /products/availability [post]
+		product.POST("/availability", deps.ProductHandler.CheckAvailability)
+
+		// @Summary Signed image URLs
+		// @Description Time-limited signed delivery URLs for a product's images (authenticated)
+		// @Tags Products
+		// @Produce json
+		// @Security BearerAuth
+		// @Param id path string true "Product ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 401 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Router /products/{id}/images/signed [get]
+		product.GET("/:id/images/signed", deps.AuthMiddleware.RequireAuth(), deps.ProductHandler.SignedImageURLs)
+
+		// @Summary List product images
+		// @Description A product's stored images (public). Responses carry an ETag and Cache-Control max-age; send If-None-Match to get 304 when unchanged
+		// @Tags Products
+		// @Produce json
+		// @Param id path string true "Product ID"
+		// @Param If-None-Match header string false "ETag from a previous response"
+		// @Success 200 {object} response.Base
+		// @Success 304 "Not modified"
+		// @Failure 400 {object} response.Base
+		// @Router /products/{id}/images [get]
+		product.GET("/:id/images", deps.ProductHandler.ListImages)
+
+		// @Summary List product documents
+		// @Description A product's documents such as spec sheets and manuals (public)
+		// @Tags Products
+		// @Produce json
+		// @Param id path string true "Product ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Router /products/{id}/documents [get]
+		product.GET("/:id/documents", deps.ProductHandler.ListDocuments)
+	}
+	// Category browsing: public access
+	categories := v1.Group("/categories")
+	categories.Use(debugLog("categories"))
+	{
+		// @Summary List products in a category
+		// @Description List products of a category with pagination (public)
+		// @Tags Products
+		// @Produce json
+		// @Param id path string true "Category ID"
+		// @Param page query int false "Page number"
+		// @Param limit query int false "Page size"
+		// @Param search query string false "Search term"
+		// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+		// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+		// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+		// @Success 200 {object} response.Paginated
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Router /categories/{id}/products [get]
+		categories.GET("/:id/products", deps.ProductHandler.ListByCategory)
+		categories.HEAD("/:id/products", middleware.HeadOnly(), deps.ProductHandler.ListByCategory)
 	}
 	// Mutation endpoints for admin
 	adminProducts := v1.Group("/products")
-	adminProducts.Use(deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin))
+	adminProducts.Use(debugLog("products"), deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin))
 	{
 		// @Summary Create product
 		// @Description Create a product (admin only)
@@ -170,15 +412,81 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Produce json
 		// @Param id path string true "Product ID"
 		// @Param files formData file true "Image files"
+		// @Param altText formData []string false "Alt text per file, in file order"
+		// @Param caption formData []string false "Caption per file, in file order"
 		// @Success 201 {object} response.Base
+		// @Failure 413 {object} response.Base
+		// @Failure 503 {object} response.Base
 		// @Security BearerAuth
 		// @Router /products/{id}/images [post]
-		adminProducts.POST("/:id/images", deps.ProductHandler.UploadImages)
+		adminProducts.POST("/:id/images", middleware.UploadSize(deps.UploadMetrics), deps.ProductHandler.UploadImages)
+
+		// @Summary Upload product documents
+		// @Description Upload up to 10 documents (spec sheets, manuals) for a product; they are listed apart from its images (admin only)
+		// @Tags Products
+		// @Accept multipart/form-data
+		// @Produce json
+		// @Param id path string true "Product ID"
+		// @Param files formData file true "Document files"
+		// @Success 201 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 413 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Security BearerAuth
+		// @Router /products/{id}/documents [post]
+		adminProducts.POST("/:id/documents", middleware.UploadSize(deps.UploadMetrics), deps.ProductHandler.UploadDocuments)
+
+		// @Summary Update product image metadata
+		// @Description Edit an image's alt text and caption; omitted fields are left unchanged (admin only)
+		// @Tags Products
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "Product ID"
+		// @Param imageId path string true "Image ID"
+		// @Param payload body productusecase.UpdateImageInput true "Image metadata"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /products/{id}/images/{imageId} [patch]
+		adminProducts.PATCH("/:id/images/:imageId", deps.ProductHandler.UpdateImage)
+
+		// @Summary Delete product images
+		// @Description Delete several images of a product at once (admin only). Ids that are not images of the product are skipped and listed in skipped; the stored files are removed from Cloudinary after the rows are deleted
+		// @Tags Products
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "Product ID"
+		// @Param payload body productusecase.DeleteImagesInput true "Image ids"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Security BearerAuth
+		// @Router /products/{id}/images [delete]
+		adminProducts.DELETE("/:id/images", deps.ProductHandler.DeleteImages)
+
+		// @Summary Bulk upload product images
+		// @Description Upload images for multiple products in one request (admin only). Each product succeeds or fails on its own: 201 when all succeed, 207 when some fail, 422 with the per-product results in details when none does
+		// @Tags Products
+		// @Accept multipart/form-data
+		// @Produce json
+		// @Param manifest formData string true "JSON array of product ids, one per file"
+		// @Param files formData file true "Image files"
+		// @Success 201 {object} response.Base
+		// @Success 207 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 413 {object} response.Base
+		// @Failure 422 {object} response.Base
+		// @Failure 503 {object} response.Base
+		// @Security BearerAuth
+		// @Router /products/images/bulk [post]
+		adminProducts.POST("/images/bulk", middleware.UploadSize(deps.UploadMetrics), deps.ProductHandler.UploadBulkImages)
 	}
 
 	// Mutation endpoints for user and admin role
 	orders := v1.Group("/orders")
-	orders.Use(deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin, domain.RoleUser))
+	orders.Use(debugLog("orders"), deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin, domain.RoleUser))
 	{
 		// @Summary Create order
 		// @Description Place a new order (user or admin)
@@ -188,6 +496,7 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Param payload body orderusecase.CreateOrderInput true "Order payload"
 		// @Success 201 {object} response.Base
 		// @Failure 400 {object} response.Base
+		// @Failure 409 {object} response.Base
 		// @Security BearerAuth
 		// @Router /orders [post]
 		orders.POST("", deps.OrderHandler.Create)
@@ -196,16 +505,173 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Description Get current user's orders
 		// @Tags Orders
 		// @Produce json
+		// @Param status query string false "Only orders in this status: pending, review_required, completed or cancelled"
+		// @Param include_archived query bool false "Also list archived orders"
 		// @Success 200 {object} response.Base
 		// @Security BearerAuth
 		// @Router /orders [get]
 		orders.GET("", deps.OrderHandler.List)
+
+		// @Summary Reorder
+		// @Description Place a new order with the items of one of your previous orders, at current prices and subject to current stock. priceChanges lists items whose price differs from the original order
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Previous order ID"
+		// @Success 201 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id}/reorder [post]
+		orders.POST("/:id/reorder", deps.OrderHandler.Reorder)
+
+		// @Summary Get order
+		// @Description Get an order with its items (owner or admin). Each item keeps the product name and price it was bought at; Product holds the product's current name and first image, and is null once the product has been deleted
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id} [get]
+		orders.GET("/:id", deps.OrderHandler.Get)
+
+		// @Summary Order status history
+		// @Description List the status transitions of an order, oldest first, with who made each change (owner or admin). FromStatus is empty for the creation entry and ActorID is null for changes made by the system
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id}/history [get]
+		orders.GET("/:id/history", deps.OrderHandler.History)
+
+		// @Summary Get order by number
+		// @Description Look up an order with its items by its human-friendly order number, e.g. ORD-2024-000123 (owner or admin). Matching ignores case
+		// @Tags Orders
+		// @Produce json
+		// @Param number path string true "Order number"
+		// @Success 200 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/by-number/{number} [get]
+		orders.GET("/by-number/:number", deps.OrderHandler.GetByNumber)
+
+		// @Summary Delete order
+		// @Description Permanently delete an order and its items, archived or not (admin only). Cancelled orders are deleted as-is; other orders need force=true and are restocked first
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Param force query bool false "Restock and delete an order that is not cancelled"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id} [delete]
+		orders.DELETE("/:id", deps.AuthMiddleware.RequireRoles(domain.RoleAdmin), deps.OrderHandler.Delete)
+
+		// @Summary Archive order
+		// @Description Hide a completed or cancelled order from order listings without deleting it; list with include_archived=true to see it again (admin only)
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id}/archive [post]
+		orders.POST("/:id/archive", deps.AuthMiddleware.RequireRoles(domain.RoleAdmin), deps.OrderHandler.Archive)
+
+		// @Summary Approve reviewed order
+		// @Description Release an order held as review_required; it becomes pending and keeps its stock (admin only)
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id}/approve [post]
+		orders.POST("/:id/approve", deps.AuthMiddleware.RequireRoles(domain.RoleAdmin), deps.OrderHandler.ApproveReview)
+
+		// @Summary Reject reviewed order
+		// @Description Cancel an order held as review_required and return its stock (admin only)
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Order ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /orders/{id}/reject [post]
+		orders.POST("/:id/reject", deps.AuthMiddleware.RequireRoles(domain.RoleAdmin), deps.OrderHandler.RejectReview)
+	}
+
+	// Checkout reservations share the orders debug-log group
+	reservations := v1.Group("/reservations")
+	reservations.Use(debugLog("orders"), deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin, domain.RoleUser))
+	{
+		// @Summary Reserve stock
+		// @Description Hold stock of a product for the current user while they check out. Pass the reservation id to POST /orders to spend it; unspent reservations are released when they expire
+		// @Tags Orders
+		// @Accept json
+		// @Produce json
+		// @Param payload body handler.reserveRequest true "Reservation payload"
+		// @Success 201 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /reservations [post]
+		reservations.POST("", deps.ReservationHandler.Reserve)
+
+		// @Summary Release reservation
+		// @Description Give the current user's reserved stock back before it expires
+		// @Tags Orders
+		// @Produce json
+		// @Param id path string true "Reservation ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /reservations/{id} [delete]
+		reservations.DELETE("/:id", deps.ReservationHandler.Release)
 	}
 
 	// Admin endpoints
 	admin := v1.Group("/admin")
-	admin.Use(deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin))
+	admin.Use(debugLog("admin"), deps.AuthMiddleware.RequireAuth(), deps.AuthMiddleware.RequireRoles(domain.RoleAdmin))
 	{
+		// @Summary Create user
+		// @Description Create an account with a chosen role (default user), also while self-registration is disabled (admin only)
+		// @Tags Admin
+		// @Accept json
+		// @Produce json
+		// @Param payload body authusecase.CreateUserInput true "New user"
+		// @Success 201 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/users [post]
+		admin.POST("/users", deps.AdminHandler.CreateUser)
+
+		// @Summary Get user
+		// @Description Get a user's details by id (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Param id path string true "User ID"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/users/{id} [get]
+		admin.GET("/users/:id", deps.AdminHandler.GetUser)
+
 		// @Summary Promote user to admin
 		// @Description Promote a user to admin role (admin only)
 		// @Tags Admin
@@ -216,7 +682,165 @@ func Setup(deps Dependencies) *gin.Engine {
 		// @Security BearerAuth
 		// @Router /admin/users/{id}/admin [post]
 		admin.POST("/users/:id/admin", deps.AdminHandler.PromoteUserToAdmin)
+
+		// @Summary Set user role
+		// @Description Give a user one of the supported roles (admin, user). Demoting the last admin is refused with 409 (admin only)
+		// @Tags Admin
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "User ID"
+		// @Param payload body authusecase.SetRoleInput true "Role"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/users/{id}/role [put]
+		admin.PUT("/users/:id/role", deps.AdminHandler.SetUserRole)
+
+		// @Summary Promote user to admin by email
+		// @Description Promote a user to admin role by email lookup (admin only)
+		// @Tags Admin
+		// @Accept json
+		// @Produce json
+		// @Param payload body authusecase.PromoteByEmailInput true "User email"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/users/promote [post]
+		admin.POST("/users/promote", deps.AdminHandler.PromoteUserToAdminByEmail)
+
+		// @Summary Expire stale pending orders
+		// @Description Cancel pending orders older than olderThan and restock their items (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Param olderThan query string false "Minimum order age as a Go duration, e.g. 48h"
+		// @Success 200 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/orders/expire [post]
+		admin.POST("/orders/expire", deps.OrderHandler.ExpireStale)
+
+		// @Summary Product counts per category
+		// @Description Number of products in each category, largest first (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/products/category-counts [get]
+		admin.GET("/products/category-counts", deps.ProductHandler.CategoryCounts)
+
+		// @Summary Export products
+		// @Description Stream every product as a JSON array, read from the database in batches (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Success 200 {array} domain.Product
+		// @Security BearerAuth
+		// @Router /admin/products/export [get]
+		admin.GET("/products/export", deps.ProductHandler.Export)
+
+		// @Summary Upload size metrics
+		// @Description Request body sizes observed on upload routes, bucketed per route (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/metrics/uploads [get]
+		admin.GET("/metrics/uploads", deps.AdminHandler.UploadMetrics)
+
+		// @Summary Flush caches
+		// @Description Clear all cached product data, e.g. after a direct database edit (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/cache/flush [post]
+		admin.POST("/cache/flush", deps.AdminHandler.FlushCache)
+
+		// @Summary Cache stats
+		// @Description Product cache size, capacity and hit, miss and rejected-insert counters (admin only)
+		// @Tags Admin
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/cache/stats [get]
+		admin.GET("/cache/stats", deps.AdminHandler.CacheStats)
+
+		// @Summary List saved product views
+		// @Description List the caller's saved product views (admin only)
+		// @Tags Product Views
+		// @Produce json
+		// @Success 200 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/product-views [get]
+		admin.GET("/product-views", deps.ProductViewHandler.List)
+
+		// @Summary Save product view
+		// @Description Save a named product listing filter for later use with GET /products?view= (admin only)
+		// @Tags Product Views
+		// @Accept json
+		// @Produce json
+		// @Param payload body productusecase.SaveViewInput true "View"
+		// @Success 201 {object} response.Base
+		// @Failure 400 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/product-views [post]
+		admin.POST("/product-views", deps.ProductViewHandler.Create)
+
+		// @Summary Get saved product view
+		// @Description Get one of the caller's saved product views (admin only)
+		// @Tags Product Views
+		// @Produce json
+		// @Param id path string true "View ID"
+		// @Success 200 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/product-views/{id} [get]
+		admin.GET("/product-views/:id", deps.ProductViewHandler.Get)
+
+		// @Summary Replace product view
+		// @Description Replace the name and filter of one of the caller's saved product views (admin only)
+		// @Tags Product Views
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "View ID"
+		// @Param payload body productusecase.SaveViewInput true "View"
+		// @Success 200 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Failure 409 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/product-views/{id} [put]
+		admin.PUT("/product-views/:id", deps.ProductViewHandler.Update)
+
+		// @Summary Delete product view
+		// @Description Delete one of the caller's saved product views (admin only)
+		// @Tags Product Views
+		// @Produce json
+		// @Param id path string true "View ID"
+		// @Success 200 {object} response.Base
+		// @Failure 404 {object} response.Base
+		// @Security BearerAuth
+		// @Router /admin/product-views/{id} [delete]
+		admin.DELETE("/product-views/:id", deps.ProductViewHandler.Delete)
 	}
 
+	// unmatched routes answer in the response.Base shape instead of gin's plain text
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(notFound)
+	r.NoMethod(methodNotAllowed(r.Routes()))
+
 	return r
 }
+
+// skipSwagger runs limit for every route except the Swagger UI.
+func skipSwagger(limit gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.URL.Path == "/swagger" || strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
+			c.Next()
+			return
+		}
+		limit(c)
+	}
+}
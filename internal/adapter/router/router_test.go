@@ -0,0 +1,143 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/adapter/handler"
+	"github.com/minilik/ecommerce/internal/adapter/middleware"
+	"github.com/minilik/ecommerce/internal/domain"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
+	"github.com/minilik/ecommerce/pkg/response"
+)
+
+type stubProductService struct {
+	productusecase.Service
+	product domain.Product
+}
+
+func (s stubProductService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	if id != s.product.ID {
+		return nil, domain.ErrProductNotFound
+	}
+	p := s.product
+	return &p, nil
+}
+
+func newTestRouter(t *testing.T, product domain.Product) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	jwt, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	return Setup(Dependencies{
+		AuthHandler:    handler.NewAuthHandler(nil, logger),
+		ProductHandler: handler.NewProductHandler(stubProductService{product: product}, logger),
+		OrderHandler:   handler.NewOrderHandler(nil, logger),
+		AdminHandler:   handler.NewAdminHandler(nil, logger),
+		AuthMiddleware: middleware.NewAuthMiddleware(logger, jwt),
+		Logger:         logger,
+	})
+}
+
+func TestRouter_HeadProduct(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Price: 25}
+	r := newTestRouter(t, product)
+
+	get := httptest.NewRecorder()
+	r.ServeHTTP(get, httptest.NewRequest(http.MethodGet, APIBasePath+"/products/"+product.ID.String(), nil))
+	require.Equal(t, http.StatusOK, get.Code)
+
+	head := httptest.NewRecorder()
+	r.ServeHTTP(head, httptest.NewRequest(http.MethodHead, APIBasePath+"/products/"+product.ID.String(), nil))
+	assert.Equal(t, http.StatusOK, head.Code)
+	assert.Empty(t, head.Body.String())
+	assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+
+	missing := httptest.NewRecorder()
+	r.ServeHTTP(missing, httptest.NewRequest(http.MethodHead, APIBasePath+"/products/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Empty(t, missing.Body.String())
+}
+
+func TestRouter_PreflightOrders(t *testing.T) {
+	r := newTestRouter(t, domain.Product{})
+
+	req := httptest.NewRequest(http.MethodOptions, APIBasePath+"/orders", nil)
+	req.Header.Set("Origin", "https://shop.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, "preflight needs no token")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodDelete)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+}
+
+func TestRouter_SignedImageURLsRequireAuth(t *testing.T) {
+	r := newTestRouter(t, domain.Product{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, APIBasePath+"/products/"+uuid.NewString()+"/images/signed", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRouter_TimeoutOverridesMatchRoutes(t *testing.T) {
+	r := newTestRouter(t, domain.Product{})
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		registered[middleware.RouteKey(route.Method, route.Path)] = true
+	}
+	for key := range timeoutOverrides(Dependencies{}) {
+		assert.True(t, registered[key], "timeout override %q does not match a route", key)
+	}
+}
+
+func TestRouter_UnknownPath(t *testing.T) {
+	r := newTestRouter(t, domain.Product{})
+
+	for _, path := range []string{APIBasePath + "/nope", "/nope"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+		assert.JSONEq(t, `{"success":false,"code":"not_found","message":"resource not found","errors":["GET `+path+` does not exist"]}`, w.Body.String())
+	}
+}
+
+func TestRouter_WrongMethod(t *testing.T) {
+	r := newTestRouter(t, domain.Product{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, APIBasePath+"/products/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS, PUT", w.Header().Get("Allow"))
+	var body response.Base
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.False(t, body.Success)
+	assert.Equal(t, "method_not_allowed", body.Code)
+}
+
+func TestRouteMatches(t *testing.T) {
+	assert.True(t, routeMatches("/api/v1/products/:id", "/api/v1/products/42"))
+	assert.True(t, routeMatches("/api/v1/products/:id/images", "/api/v1/products/42/images/"))
+	assert.True(t, routeMatches("/swagger/*any", "/swagger/index.html"))
+	assert.False(t, routeMatches("/api/v1/products/:id", "/api/v1/products/42/images"))
+	assert.False(t, routeMatches("/api/v1/products/:id/images", "/api/v1/products/42"))
+	assert.False(t, routeMatches("/api/v1/orders", "/api/v1/products"))
+}
@@ -8,13 +8,14 @@ package router
 // These dummy functions with annotations ensure Swaggo can generate the docs
 
 // @Summary Register a new user
-// @Description Create a new user account (role=user)
+// @Description Create a new user account (role=user). Refused with 403 when auth.registration_enabled is false
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param payload body auth.RegisterInput true "Register payload"
 // @Success 201 {object} response.Base
 // @Failure 400 {object} response.Base
+// @Failure 403 {object} response.Base
 // @Router /auth/register [post]
 func _() {}
 
@@ -30,6 +31,73 @@ func _() {}
 // @Router /auth/login [post]
 func _() {}
 
+// @Summary Update email
+// @Description Change the authenticated user's email; requires the current password
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payload body auth.UpdateEmailInput true "Update email payload"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 401 {object} response.Base
+// @Router /auth/email [put]
+func _() {}
+
+// @Summary Token claims
+// @Description Return the claims parsed from the caller's token (no database lookup)
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Base
+// @Failure 401 {object} response.Base
+// @Router /auth/whoami [get]
+func _() {}
+
+// @Summary Send email verification
+// @Description Send a signed, expiring verification link to the authenticated user's email
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} response.Base
+// @Failure 401 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Router /auth/verify/send [post]
+func _() {}
+
+// @Summary Verify email
+// @Description Mark an email as verified using the token from a verification link
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Router /auth/verify [get]
+func _() {}
+
+// @Summary Request password reset
+// @Description Send a single-use, expiring password reset link. The response is the same whether or not the email is registered
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body authusecase.ForgotPasswordInput true "Account email"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Router /auth/password/forgot [post]
+func _() {}
+
+// @Summary Reset password
+// @Description Set a new password using the token from a reset link; the token works once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body authusecase.ResetPasswordInput true "Reset token and new password"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Router /auth/password/reset [post]
+func _() {}
+
 // @Summary List products
 // @Description List products with pagination (public)
 // @Tags Products
@@ -37,20 +105,108 @@ func _() {}
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
 // @Param search query string false "Search term"
+// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+// @Param view query string false "Saved view ID; its filter fills parameters not given (admin only)"
+// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+// @Param created_from query string false "Only products created at or after this time: RFC 3339 or YYYY-MM-DD (start of day, UTC)"
+// @Param created_to query string false "Only products created at or before this time: RFC 3339 or YYYY-MM-DD (whole day, UTC)"
 // @Success 200 {object} response.Paginated
+// @Failure 400 {object} response.Base
 // @Router /products [get]
 func _() {}
 
 // @Summary Get product
-// @Description Get product details (public)
+// @Description Get product details (public). Responses carry a weak ETag and Last-Modified; send If-None-Match or If-Modified-Since to get 304 when unchanged
 // @Tags Products
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response; ignored when If-None-Match is sent"
 // @Success 200 {object} response.Base
+// @Success 304 "Not modified"
 // @Failure 404 {object} response.Base
 // @Router /products/{id} [get]
 func _() {}
 
+// @Summary Get products by ids
+// @Description Resolve many products in one call, preserving request order (public)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param payload body product.BatchGetInput true "Product ids"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Router /products/batch [post]
+func _() {}
+
+// @Summary Suggest products
+// @Description Typeahead: id and name of up to product.suggest_limit products whose name starts with q (public)
+// @Tags Products
+// @Produce json
+// @Param q query string true "Name prefix"
+// @Success 200 {object} response.Base
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.Base
+// @Router /products/suggest [get]
+func _() {}
+
+// @Summary List product categories
+// @Description Distinct categories that products are filed under, by name, each with its number of products (public)
+// @Tags Products
+// @Produce json
+// @Success 200 {object} response.Base
+// @Success 304 "Not modified"
+// @Router /products/categories [get]
+func _() {}
+
+// @Summary Check cart availability
+// @Description Check a cart against current stock without placing an order (public). Returns per-item available quantity and whether it is in stock
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param payload body []product.AvailabilityItemInput true "Cart lines"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Router /products/availability [post]
+func _() {}
+
+// @Summary Signed image URLs
+// @Description Time-limited signed delivery URLs for a product's images (authenticated)
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Product ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 401 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Router /products/{id}/images/signed [get]
+func _() {}
+
+// @Summary List product images
+// @Description A product's stored images (public). Responses carry an ETag and Cache-Control max-age; send If-None-Match to get 304 when unchanged
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} response.Base
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.Base
+// @Router /products/{id}/images [get]
+func _() {}
+
+// @Summary List product documents
+// @Description A product's documents such as spec sheets and manuals (public)
+// @Tags Products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Router /products/{id}/documents [get]
+func _() {}
+
 // @Summary Create product
 // @Description Create a product (admin only)
 // @Tags Products
@@ -89,6 +245,23 @@ func _() {}
 // @Router /products/{id} [delete]
 func _() {}
 
+// @Summary List products in a category
+// @Description List products of a category with pagination (public)
+// @Tags Products
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Param search query string false "Search term"
+// @Param sort query string false "Sort order: newest, price_asc, price_desc or name"
+// @Param fields query string false "Comma-separated product fields to return, e.g. id,name,price; all fields when omitted"
+// @Param count query bool false "Count matching products for totalPages and totalProducts; false returns only hasNext (default from product.list_totals)"
+// @Success 200 {object} response.Paginated
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Router /categories/{id}/products [get]
+func _() {}
+
 // @Summary Upload product images
 // @Description Upload up to 4 images for a product (admin only)
 // @Tags Products
@@ -96,11 +269,77 @@ func _() {}
 // @Produce json
 // @Param id path string true "Product ID"
 // @Param files formData file true "Image files"
+// @Param altText formData []string false "Alt text per file, in file order"
+// @Param caption formData []string false "Caption per file, in file order"
 // @Success 201 {object} response.Base
+// @Failure 413 {object} response.Base
+// @Failure 503 {object} response.Base
 // @Security BearerAuth
 // @Router /products/{id}/images [post]
 func _() {}
 
+// @Summary Upload product documents
+// @Description Upload up to 10 documents (spec sheets, manuals) for a product; they are listed apart from its images (admin only)
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param files formData file true "Document files"
+// @Success 201 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 413 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Security BearerAuth
+// @Router /products/{id}/documents [post]
+func _() {}
+
+// @Summary Update product image metadata
+// @Description Edit an image's alt text and caption; omitted fields are left unchanged (admin only)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param imageId path string true "Image ID"
+// @Param payload body product.UpdateImageInput true "Image metadata"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /products/{id}/images/{imageId} [patch]
+func _() {}
+
+// @Summary Delete product images
+// @Description Delete several images of a product at once (admin only). Ids that are not images of the product are skipped and listed in skipped; the stored files are removed from Cloudinary after the rows are deleted
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param payload body product.DeleteImagesInput true "Image ids"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Security BearerAuth
+// @Router /products/{id}/images [delete]
+func _() {}
+
+// @Summary Bulk upload product images
+// @Description Upload images for multiple products in one request (admin only). Each product succeeds or fails on its own: 201 when all succeed, 207 when some fail, 422 with the per-product results in details when none does
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Param manifest formData string true "JSON array of product ids, one per file"
+// @Param files formData file true "Image files"
+// @Success 201 {object} response.Base
+// @Success 207 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 413 {object} response.Base
+// @Failure 422 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Security BearerAuth
+// @Router /products/images/bulk [post]
+func _() {}
+
 // @Summary Create order
 // @Description Place a new order (user or admin)
 // @Tags Orders
@@ -109,6 +348,7 @@ func _() {}
 // @Param payload body order.CreateOrderInput true "Order payload"
 // @Success 201 {object} response.Base
 // @Failure 400 {object} response.Base
+// @Failure 409 {object} response.Base
 // @Security BearerAuth
 // @Router /orders [post]
 func _() {}
@@ -117,11 +357,163 @@ func _() {}
 // @Description Get current user's orders
 // @Tags Orders
 // @Produce json
+// @Param status query string false "Only orders in this status: pending, review_required, completed or cancelled"
+// @Param include_archived query bool false "Also list archived orders"
 // @Success 200 {object} response.Base
 // @Security BearerAuth
 // @Router /orders [get]
 func _() {}
 
+// @Summary Reorder
+// @Description Place a new order with the items of one of your previous orders, at current prices and subject to current stock. priceChanges lists items whose price differs from the original order
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Previous order ID"
+// @Success 201 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id}/reorder [post]
+func _() {}
+
+// @Summary Get order
+// @Description Get an order with its items (owner or admin). Each item keeps the product name and price it was bought at; Product holds the product's current name and first image, and is null once the product has been deleted
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id} [get]
+func _() {}
+
+// @Summary Order status history
+// @Description List the status transitions of an order, oldest first, with who made each change (owner or admin). FromStatus is empty for the creation entry and ActorID is null for changes made by the system
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id}/history [get]
+func _() {}
+
+// @Summary Get order by number
+// @Description Look up an order with its items by its human-friendly order number, e.g. ORD-2024-000123 (owner or admin). Matching ignores case
+// @Tags Orders
+// @Produce json
+// @Param number path string true "Order number"
+// @Success 200 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/by-number/{number} [get]
+func _() {}
+
+// @Summary Delete order
+// @Description Permanently delete an order and its items, archived or not (admin only). Cancelled orders are deleted as-is; other orders need force=true and are restocked first
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param force query bool false "Restock and delete an order that is not cancelled"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id} [delete]
+func _() {}
+
+// @Summary Archive order
+// @Description Hide a completed or cancelled order from order listings without deleting it; list with include_archived=true to see it again (admin only)
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id}/archive [post]
+func _() {}
+
+// @Summary Approve reviewed order
+// @Description Release an order held as review_required; it becomes pending and keeps its stock (admin only)
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id}/approve [post]
+func _() {}
+
+// @Summary Reject reviewed order
+// @Description Cancel an order held as review_required and return its stock (admin only)
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /orders/{id}/reject [post]
+func _() {}
+
+// @Summary Reserve stock
+// @Description Hold stock of a product for the current user while they check out. Pass the reservation id to POST /orders to spend it; unspent reservations are released when they expire
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param payload body handler.reserveRequest true "Reservation payload"
+// @Success 201 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /reservations [post]
+func _() {}
+
+// @Summary Release reservation
+// @Description Give the current user's reserved stock back before it expires
+// @Tags Orders
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /reservations/{id} [delete]
+func _() {}
+
+// @Summary Create user
+// @Description Create an account with a chosen role (default user), also while self-registration is disabled (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param payload body auth.CreateUserInput true "New user"
+// @Success 201 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/users [post]
+func _() {}
+
+// @Summary Get user
+// @Description Get a user's details by id (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/users/{id} [get]
+func _() {}
+
 // @Summary Promote user to admin
 // @Description Promote a user to admin role (admin only)
 // @Tags Admin
@@ -132,3 +524,154 @@ func _() {}
 // @Security BearerAuth
 // @Router /admin/users/{id}/admin [post]
 func _() {}
+
+// @Summary Set user role
+// @Description Give a user one of the supported roles (admin, user). Demoting the last admin is refused with 409 (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param payload body auth.SetRoleInput true "Role"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [put]
+func _() {}
+
+// @Summary Promote user to admin by email
+// @Description Promote a user to admin role by email lookup (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param payload body auth.PromoteByEmailInput true "User email"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/users/promote [post]
+func _() {}
+
+// @Summary Expire stale pending orders
+// @Description Cancel pending orders older than olderThan and restock their items (admin only)
+// @Tags Admin
+// @Produce json
+// @Param olderThan query string false "Minimum order age as a Go duration, e.g. 48h"
+// @Success 200 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/orders/expire [post]
+func _() {}
+
+// @Summary Product counts per category
+// @Description Number of products in each category, largest first (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/products/category-counts [get]
+func _() {}
+
+// @Summary Export products
+// @Description Stream every product as a JSON array, read from the database in batches (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} domain.Product
+// @Security BearerAuth
+// @Router /admin/products/export [get]
+func _() {}
+
+// @Summary Upload size metrics
+// @Description Request body sizes observed on upload routes, bucketed per route (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/metrics/uploads [get]
+func _() {}
+
+// @Summary Flush caches
+// @Description Clear all cached product data, e.g. after a direct database edit (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/cache/flush [post]
+func _() {}
+
+// @Summary Cache stats
+// @Description Product cache size, capacity and hit, miss and rejected-insert counters (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/cache/stats [get]
+func _() {}
+
+// @Summary List saved product views
+// @Description List the caller's saved product views (admin only)
+// @Tags Product Views
+// @Produce json
+// @Success 200 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/product-views [get]
+func _() {}
+
+// @Summary Save product view
+// @Description Save a named product listing filter for later use with GET /products?view= (admin only)
+// @Tags Product Views
+// @Accept json
+// @Produce json
+// @Param payload body productusecase.SaveViewInput true "View"
+// @Success 201 {object} response.Base
+// @Failure 400 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/product-views [post]
+func _() {}
+
+// @Summary Get saved product view
+// @Description Get one of the caller's saved product views (admin only)
+// @Tags Product Views
+// @Produce json
+// @Param id path string true "View ID"
+// @Success 200 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/product-views/{id} [get]
+func _() {}
+
+// @Summary Replace product view
+// @Description Replace the name and filter of one of the caller's saved product views (admin only)
+// @Tags Product Views
+// @Accept json
+// @Produce json
+// @Param id path string true "View ID"
+// @Param payload body productusecase.SaveViewInput true "View"
+// @Success 200 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Failure 409 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/product-views/{id} [put]
+func _() {}
+
+// @Summary Delete product view
+// @Description Delete one of the caller's saved product views (admin only)
+// @Tags Product Views
+// @Produce json
+// @Param id path string true "View ID"
+// @Success 200 {object} response.Base
+// @Failure 404 {object} response.Base
+// @Security BearerAuth
+// @Router /admin/product-views/{id} [delete]
+func _() {}
+
+// @Summary Readiness check
+// @Description Report the status of each dependency; 503 when a critical one is down
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response.Base
+// @Failure 503 {object} response.Base
+// @Router /health/ready [get]
+func _() {}
@@ -13,3 +13,10 @@ type Category struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+// CategoryCount is the number of products filed under a category name. Products without a
+// category are reported under an empty Name.
+type CategoryCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
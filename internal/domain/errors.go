@@ -1,17 +1,131 @@
 package domain
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// StatusClientClosedRequest is the non-standard status (from nginx) for a request the client
+// abandoned before the server answered.
+const StatusClientClosedRequest = 499
+
+// AppError is an error that knows how it should be reported to API clients.
+// Handlers write Status and Message; Code is a stable machine-readable identifier.
+type AppError struct {
+	Code    string
+	Message string
+	Status  int
+	Err     error
+}
+
+// NewAppError builds an AppError; cause may be nil.
+func NewAppError(status int, code, message string, cause error) *AppError {
+	return &AppError{Code: code, Message: message, Status: status, Err: cause}
+}
+
+// NewValidationError reports invalid client input as a 400.
+func NewValidationError(format string, args ...interface{}) *AppError {
+	msg := fmt.Sprintf(format, args...)
+	return &AppError{Code: "validation_failed", Message: msg, Status: http.StatusBadRequest}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error { return e.Err }
+
+// ContextError reports a context cancellation or deadline anywhere in err's chain as
+// ErrRequestCanceled or ErrRequestTimeout, dropping the driver's message. It returns nil for
+// any other error.
+func ContextError(err error) *AppError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrRequestCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrRequestTimeout
+	}
+	return nil
+}
 
 var (
-	ErrEmailAlreadyExists      = errors.New("email already exists")
-	ErrUsernameAlreadyExists   = errors.New("username already exists")
-	ErrInvalidCredentials      = errors.New("invalid credentials")
-	ErrProductNotFound         = errors.New("product not found")
-	ErrInsufficientStock       = errors.New("insufficient stock")
-	ErrInvalidPasswordFormat   = errors.New("invalid password format")
-	ErrInvalidUsernameFormat   = errors.New("invalid username format: username must be alphanumeric without spaces")
-	ErrInvalidEmailFormat      = errors.New("invalid email format")
-	ErrEmailCannotEmpty        = errors.New("email cannot be empty")
-	ErrProductHasPendingOrders = errors.New("cannot delete product: product has pending orders")
-	ErrUserNotFound            = errors.New("user not found")
+	ErrEmailAlreadyExists      = NewAppError(http.StatusBadRequest, "email_exists", "email already exists", nil)
+	ErrUsernameAlreadyExists   = NewAppError(http.StatusBadRequest, "username_exists", "username already exists", nil)
+	ErrInvalidCredentials      = NewAppError(http.StatusUnauthorized, "invalid_credentials", "invalid credentials", nil)
+	ErrRegistrationDisabled    = NewAppError(http.StatusForbidden, "registration_disabled", "registration is closed: accounts are created by an administrator", nil)
+	ErrProductNotFound         = NewAppError(http.StatusNotFound, "product_not_found", "product not found", nil)
+	ErrInsufficientStock       = NewAppError(http.StatusBadRequest, "insufficient_stock", "insufficient stock", nil)
+	ErrInvalidPasswordFormat   = NewAppError(http.StatusBadRequest, "invalid_password", "invalid password format", nil)
+	ErrInvalidUsernameFormat   = NewAppError(http.StatusBadRequest, "invalid_username", "invalid username format: username must be alphanumeric without spaces", nil)
+	ErrInvalidEmailFormat      = NewAppError(http.StatusBadRequest, "invalid_email", "invalid email format", nil)
+	ErrEmailCannotEmpty        = NewAppError(http.StatusBadRequest, "email_required", "email cannot be empty", nil)
+	ErrDisposableEmail         = NewAppError(http.StatusBadRequest, "disposable_email", "disposable email addresses are not allowed", nil)
+	ErrProductHasPendingOrders = NewAppError(http.StatusBadRequest, "product_has_pending_orders", "cannot delete product: product has pending orders", nil)
+	ErrUserNotFound            = NewAppError(http.StatusNotFound, "user_not_found", "user not found", nil)
+	ErrInvalidRole             = NewAppError(http.StatusBadRequest, "invalid_role", "unknown role", nil)
+	ErrLastAdmin               = NewAppError(http.StatusConflict, "last_admin", "cannot remove the last admin", nil)
+	ErrExternalIDExists        = NewAppError(http.StatusConflict, "external_id_exists", "a product with this external id already exists", nil)
+	ErrTooManyProductIDs       = NewAppError(http.StatusBadRequest, "too_many_product_ids", "too many product ids requested", nil)
+	ErrCategoryNotFound        = NewAppError(http.StatusNotFound, "category_not_found", "category not found", nil)
+	ErrUnsupportedImage        = NewAppError(http.StatusBadRequest, "unsupported_image", "unsupported image file", nil)
+	ErrImageNotFound           = NewAppError(http.StatusNotFound, "image_not_found", "image not found", nil)
+	ErrUnsupportedDocument     = NewAppError(http.StatusBadRequest, "unsupported_document", "unsupported document file", nil)
+	ErrUploadTooLarge          = NewAppError(http.StatusRequestEntityTooLarge, "upload_too_large", "upload is too large", nil)
+	ErrImagesDisabled          = NewAppError(http.StatusServiceUnavailable, "images_disabled", "image uploads are disabled: cloudinary is not configured", nil)
+	ErrUploadsUnavailable      = NewAppError(http.StatusServiceUnavailable, "uploads_unavailable", "uploads are temporarily unavailable, please retry later", nil)
+	ErrPriceChanged            = NewAppError(http.StatusConflict, "price_changed", "order total changed, please review and confirm", nil)
+	ErrOrderNotFound           = NewAppError(http.StatusNotFound, "order_not_found", "order not found", nil)
+	ErrOrderNotCancelled       = NewAppError(http.StatusConflict, "order_not_cancelled", "order must be cancelled before it can be deleted", nil)
+	ErrOrderNotArchivable      = NewAppError(http.StatusConflict, "order_not_archivable", "only completed or cancelled orders can be archived", nil)
+	ErrOrderNotUnderReview     = NewAppError(http.StatusConflict, "order_not_under_review", "only orders held for review can be approved or rejected", nil)
+	ErrProductViewNotFound     = NewAppError(http.StatusNotFound, "product_view_not_found", "product view not found", nil)
+	ErrProductViewExists       = NewAppError(http.StatusConflict, "product_view_exists", "a product view with this name already exists", nil)
+	ErrOrderTotalExceeded      = NewAppError(http.StatusUnprocessableEntity, "order_total_exceeded", "order total exceeds the allowed maximum", nil)
+	ErrTooManyOpenOrders       = NewAppError(http.StatusConflict, "too_many_open_orders", "too many pending orders, complete or cancel one first", nil)
+	ErrSelfPurchase            = NewAppError(http.StatusForbidden, "self_purchase", "you cannot order your own product", nil)
+	ErrEmailAlreadyVerified    = NewAppError(http.StatusConflict, "email_already_verified", "email is already verified", nil)
+	ErrInvalidVerification     = NewAppError(http.StatusBadRequest, "invalid_verification_token", "verification link is invalid or has expired", nil)
+	ErrEmailNotVerified        = NewAppError(http.StatusForbidden, "email_not_verified", "verify your email address before placing orders", nil)
+	ErrInvalidResetToken       = NewAppError(http.StatusBadRequest, "invalid_reset_token", "password reset link is invalid or has expired", nil)
+	ErrResetTokenUsed          = NewAppError(http.StatusBadRequest, "reset_token_used", "password reset link has already been used", nil)
+	ErrReservationNotFound     = NewAppError(http.StatusNotFound, "reservation_not_found", "reservation not found", nil)
+	ErrReservationInactive     = NewAppError(http.StatusConflict, "reservation_not_active", "reservation has expired or was already used", nil)
+	ErrRequestCanceled         = NewAppError(StatusClientClosedRequest, "request_canceled", "request was canceled", nil)
+	ErrRequestTimeout          = NewAppError(http.StatusServiceUnavailable, "request_timeout", "request timed out, please retry", nil)
 )
+
+// StockShortage is one order line that asked for more units than are available. Available counts
+// the product's stock plus any units the buyer holds in reservations for it.
+type StockShortage struct {
+	ProductID   uuid.UUID `json:"productId"`
+	ProductName string    `json:"productName"`
+	Requested   int       `json:"requested"`
+	Available   int       `json:"available"`
+}
+
+// InsufficientStockError lists every short line of a request. It matches ErrInsufficientStock with
+// errors.Is and errors.As, and ErrorDetails lets handlers return the shortages to the client.
+type InsufficientStockError struct {
+	Shortages []StockShortage
+}
+
+func (e *InsufficientStockError) Error() string {
+	names := make([]string, len(e.Shortages))
+	for i, s := range e.Shortages {
+		names[i] = s.ProductName
+	}
+	return ErrInsufficientStock.Message + ": " + strings.Join(names, ", ")
+}
+
+func (e *InsufficientStockError) Unwrap() error { return ErrInsufficientStock }
+
+// ErrorDetails returns the shortages for the response's details field.
+func (e *InsufficientStockError) ErrorDetails() interface{} { return e.Shortages }
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventProductOutOfStock   = "product.out_of_stock"
+	EventOrderReviewRequired = "order.review_required"
+	EventEmailVerification   = "user.email_verification_requested"
+	EventPasswordReset       = "user.password_reset_requested"
+)
+
+// Event is a domain event emitted by usecases after a state change has been committed.
+type Event interface {
+	Name() string
+}
+
+// EventPublisher delivers domain events to interested parties (logs, queues, notifiers).
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// OutOfStockEvent is emitted once when an order drives a product's stock from positive to zero.
+type OutOfStockEvent struct {
+	ProductID   uuid.UUID
+	ProductName string
+	OrderID     uuid.UUID
+	OccurredAt  time.Time
+}
+
+func (OutOfStockEvent) Name() string {
+	return EventProductOutOfStock
+}
+
+// OrderReviewRequiredEvent is emitted when an order is held for review because its total exceeded
+// the configured maximum.
+type OrderReviewRequiredEvent struct {
+	OrderID    uuid.UUID
+	UserID     uuid.UUID
+	Total      float64
+	MaxTotal   float64
+	OccurredAt time.Time
+}
+
+func (OrderReviewRequiredEvent) Name() string {
+	return EventOrderReviewRequired
+}
+
+// EmailVerificationRequestedEvent asks a notifier to send Token to Email. The token is a
+// credential, so publishers must not persist it anywhere but the outgoing message.
+type EmailVerificationRequestedEvent struct {
+	UserID    uuid.UUID
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (EmailVerificationRequestedEvent) Name() string {
+	return EventEmailVerification
+}
+
+// PasswordResetRequestedEvent asks a notifier to send a password reset Token to Email. Like the
+// verification token, it is a credential.
+type PasswordResetRequestedEvent struct {
+	UserID    uuid.UUID
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (PasswordResetRequestedEvent) Name() string {
+	return EventPasswordReset
+}
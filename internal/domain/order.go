@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,17 +15,70 @@ const (
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusCompleted OrderStatus = "completed"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusReviewRequired holds an order whose total exceeded order.max_total until an admin
+	// approves it (it becomes pending) or rejects it (it is cancelled and restocked). Its stock is
+	// reserved like a pending order's, but it is never expired automatically.
+	OrderStatusReviewRequired OrderStatus = "review_required"
+)
+
+// orderStatuses is every valid OrderStatus, in lifecycle order.
+var orderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusReviewRequired,
+	OrderStatusCompleted,
+	OrderStatusCancelled,
+}
+
+// OrderStatuses returns every valid order status.
+func OrderStatuses() []OrderStatus {
+	return append([]OrderStatus(nil), orderStatuses...)
+}
+
+// ParseOrderStatus maps s to an OrderStatus, rejecting unknown values with a validation error
+// that lists the valid ones.
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	names := make([]string, len(orderStatuses))
+	for i, status := range orderStatuses {
+		if string(status) == s {
+			return status, nil
+		}
+		names[i] = string(status)
+	}
+	return "", NewValidationError("unknown order status %q; valid values: %s", s, strings.Join(names, ", "))
+}
+
+// OrderItemStatus represents the fulfillment state of a single order item.
+type OrderItemStatus string
+
+const (
+	OrderItemStatusFulfilled   OrderItemStatus = "fulfilled"
+	OrderItemStatusBackordered OrderItemStatus = "backordered"
 )
 
 // OrderItem represents a single line item inside an order.
+// ProductName is a snapshot taken at order time so history survives product renames or deletion.
+// BackorderedQuantity is the part of Quantity that could not be fulfilled from stock.
 type OrderItem struct {
-	ID        uuid.UUID
-	ProductID uuid.UUID
-	OrderID   uuid.UUID
-	Quantity  int
-	UnitPrice float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                  uuid.UUID
+	ProductID           uuid.UUID
+	OrderID             uuid.UUID
+	ProductName         string
+	Quantity            int
+	BackorderedQuantity int
+	Status              OrderItemStatus
+	UnitPrice           float64
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	// Product is the live product, loaded only for order detail views; nil when not loaded or the
+	// product no longer exists.
+	Product *OrderItemProduct
+}
+
+// OrderItemProduct is what an order detail view shows of the product as it is now. The item's
+// ProductName snapshot stays the record of what was bought; Name may have changed since.
+type OrderItemProduct struct {
+	Name     string
+	ImageURL string
 }
 
 // Order represents an order entity.
@@ -36,4 +91,58 @@ type Order struct {
 	Items       []OrderItem
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// OrderNumber is the human-friendly id customers quote, e.g. ORD-2024-000123. It is empty for
+	// orders placed before order numbers were introduced.
+	OrderNumber string
+	// ArchivedAt is set once an admin archives the order, hiding it from listings by default.
+	ArchivedAt *time.Time
+}
+
+// Archivable reports whether the order is closed and so may be archived. Open orders still hold
+// stock and must stay visible to expiry and review.
+func (o *Order) Archivable() bool {
+	return o.Status == OrderStatusCompleted || o.Status == OrderStatusCancelled
+}
+
+// FormatOrderNumber builds an order number from a prefix, the order's year and a sequence value
+// that is unique across all orders, e.g. ORD-2024-000123.
+func FormatOrderNumber(prefix string, year int, seq int64) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, seq)
+}
+
+// NormalizeOrderNumber trims and upper-cases a number typed by a customer or support agent.
+func NormalizeOrderNumber(number string) string {
+	return strings.ToUpper(strings.TrimSpace(number))
+}
+
+// Total is the item's price at order time times its quantity. Backordered units are charged too.
+func (i OrderItem) Total() float64 {
+	return i.UnitPrice * float64(i.Quantity)
+}
+
+// ComputeTotal sums the totals of the order's items. Every order path goes through it (or AddItem)
+// so the way a total is made up cannot drift between them.
+func (o *Order) ComputeTotal() float64 {
+	var total float64
+	for _, item := range o.Items {
+		total += item.Total()
+	}
+	return total
+}
+
+// AddItem appends item and keeps TotalPrice equal to ComputeTotal.
+func (o *Order) AddItem(item OrderItem) {
+	o.Items = append(o.Items, item)
+	o.TotalPrice = o.ComputeTotal()
+}
+
+// OrderStatusChange is one entry in an order's status history. FromStatus is empty for the entry
+// written when the order is created; ActorID is nil when the system, not a user, made the change.
+type OrderStatusChange struct {
+	ID         uuid.UUID
+	OrderID    uuid.UUID
+	FromStatus OrderStatus
+	ToStatus   OrderStatus
+	ActorID    *uuid.UUID
+	CreatedAt  time.Time
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_ComputeTotal(t *testing.T) {
+	t.Run("empty order", func(t *testing.T) {
+		var order Order
+		assert.Zero(t, order.ComputeTotal())
+	})
+
+	t.Run("multiple items", func(t *testing.T) {
+		order := Order{Items: []OrderItem{
+			{UnitPrice: 19.99, Quantity: 3},
+			{UnitPrice: 5, Quantity: 1},
+			{UnitPrice: 0.1, Quantity: 2, BackorderedQuantity: 2},
+		}}
+		assert.InDelta(t, 65.17, order.ComputeTotal(), 1e-9)
+	})
+}
+
+func TestOrder_AddItem(t *testing.T) {
+	var order Order
+	order.AddItem(OrderItem{UnitPrice: 10, Quantity: 2})
+	assert.Equal(t, 20.0, order.TotalPrice)
+
+	order.AddItem(OrderItem{UnitPrice: 2.5, Quantity: 4})
+	assert.Len(t, order.Items, 2)
+	assert.Equal(t, 30.0, order.TotalPrice)
+	assert.Equal(t, order.ComputeTotal(), order.TotalPrice)
+}
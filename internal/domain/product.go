@@ -20,4 +20,12 @@ type Product struct {
 	UpdatedAt   time.Time
 	//
 	CategoryId uuid.UUID
+	// ExternalID is an optional client-supplied key, unique per owner, that makes creation idempotent.
+	ExternalID string `json:"externalId,omitempty"`
+}
+
+// ProductSuggestion is the minimal product shape returned for typeahead search.
+type ProductSuggestion struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
 }
@@ -6,9 +6,29 @@ import (
 	"github.com/google/uuid"
 )
 
-type ProductImage struct {
-	ID        uuid.UUID
-	ProductID uuid.UUID
-	URL       string `json:"url"`
-	CreatedAt time.Time
+// AssetType tells the kinds of files attached to a product apart.
+type AssetType string
+
+const (
+	AssetTypeImage    AssetType = "image"
+	AssetTypeDocument AssetType = "document"
+)
+
+// ProductAsset is a file attached to a product: a photo or a document such as a spec sheet or
+// manual. AltText describes an image for screen readers and search engines and Caption is optional
+// display text; both only apply to images. Filename is the uploaded name of a document.
+// ContentHash is the hex sha256 of the uploaded bytes, set only while upload dedup is enabled.
+type ProductAsset struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	Type        AssetType `json:"type"`
+	URL         string    `json:"url"`
+	AltText     string    `json:"altText"`
+	Caption     string    `json:"caption,omitempty"`
+	Filename    string    `json:"filename,omitempty"`
+	ContentHash string    `json:"-"`
+	CreatedAt   time.Time
 }
+
+// ProductImage is a ProductAsset of type AssetTypeImage, the only kind shown in product responses.
+type ProductImage = ProductAsset
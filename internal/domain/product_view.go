@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductView is a named, saved product listing filter. Views are private to their owner.
+type ProductView struct {
+	ID        uuid.UUID         `json:"id"`
+	OwnerID   uuid.UUID         `json:"ownerId"`
+	Name      string            `json:"name"`
+	Filter    ProductViewFilter `json:"filter"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// ProductViewFilter is the listing state a view restores. Zero fields leave the listing default.
+type ProductViewFilter struct {
+	Search     string    `json:"search,omitempty"`
+	CategoryID uuid.UUID `json:"categoryId,omitempty"`
+	Sort       string    `json:"sort,omitempty"`
+	PageSize   int       `json:"pageSize,omitempty"`
+}
@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/minilik/ecommerce/internal/domain"
 )
 
 type CategoryRepository interface {
 	Create(ctx context.Context, category *domain.Category) error
 	Update(ctx context.Context, category *domain.Category) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
 	List(ctx context.Context, filter ProductFilter) ([]domain.Category, int64, error)
 }
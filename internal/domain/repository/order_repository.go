@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,6 +11,37 @@ import (
 
 type OrderRepository interface {
 	Create(ctx context.Context, order *domain.Order) error
-	ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Order, error)
+	// ListByUser returns the user's orders, newest first; a non-empty status keeps only those orders.
+	// Archived orders are left out unless includeArchived is set.
+	ListByUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error)
+	// CountByUserAndStatus counts the user's orders in the given status.
+	CountByUserAndStatus(ctx context.Context, userID uuid.UUID, status domain.OrderStatus) (int64, error)
 	HasPendingOrdersByProductID(ctx context.Context, productID uuid.UUID) (bool, error)
+	// ListPendingBefore returns up to limit pending orders (with items) last updated before the given
+	// time, oldest first. An order approved after review counts from its approval, not its creation.
+	ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]domain.Order, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error
+	// GetByID loads an order with its items, archived or not; a missing order is domain.ErrOrderNotFound.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	// GetByIDWithProducts is GetByID with each item's live product (current name and first image)
+	// joined in, in a fixed number of queries however many items the order has.
+	GetByIDWithProducts(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	// GetByNumber loads an order with its items by its order number, archived or not; a missing
+	// order is domain.ErrOrderNotFound.
+	GetByNumber(ctx context.Context, number string) (*domain.Order, error)
+	// NextOrderNumber draws the next value of the order number sequence. Values are unique even
+	// across concurrent transactions; a rolled-back order leaves a gap.
+	NextOrderNumber(ctx context.Context) (int64, error)
+	// GetByIDForUpdate loads an order with its items, archived or not, and locks it for the rest of
+	// the transaction.
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	// AddStatusChange appends an entry to the order's status history.
+	AddStatusChange(ctx context.Context, change *domain.OrderStatusChange) error
+	// ListStatusChanges returns the order's status history, oldest first.
+	ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error)
+	// Archive hides an order from listings as of at without deleting it; its items and history are
+	// kept. An order that is already archived is domain.ErrOrderNotFound.
+	Archive(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Delete permanently removes an order, archived or not, together with its items and status history.
+	Delete(ctx context.Context, id uuid.UUID) error
 }
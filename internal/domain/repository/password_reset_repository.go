@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// PasswordResetRepository stores the state of issued password reset tokens.
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *domain.PasswordReset) error
+	// GetByID returns nil, nil when no reset has the id.
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PasswordReset, error)
+	// Consume marks the reset used at usedAt. It returns domain.ErrResetTokenUsed when the reset was
+	// already used, so two concurrent requests cannot both redeem it.
+	Consume(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	// InvalidateForUser marks every unused reset of the user as used.
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, usedAt time.Time) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// ProductAssetRepository stores the files attached to products. Images and documents share one
+// store and every lookup is scoped to one domain.AssetType, so they never mix.
+type ProductAssetRepository interface {
+	AddMany(ctx context.Context, assets []domain.ProductAsset) error
+	// ListByProduct returns the product's assets of the given type, oldest first.
+	ListByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) ([]domain.ProductAsset, error)
+	CountByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) (int64, error)
+	// GetImage returns domain.ErrImageNotFound unless the image belongs to productID.
+	GetImage(ctx context.Context, productID, imageID uuid.UUID) (*domain.ProductImage, error)
+	// UpdateMetadata stores the image's AltText and Caption.
+	UpdateMetadata(ctx context.Context, image domain.ProductImage) error
+	// DeleteByIDs deletes, in one transaction, those of ids that are assets of assetType belonging
+	// to productID and returns them; any other ids are left alone.
+	DeleteByIDs(ctx context.Context, productID uuid.UUID, assetType domain.AssetType, ids []uuid.UUID) ([]domain.ProductAsset, error)
+	// ExistingProducts reports which of ids name a product, so uploads can skip the others before
+	// anything reaches Cloudinary.
+	ExistingProducts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error)
+}
@@ -2,22 +2,67 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/minilik/ecommerce/internal/domain"
 )
 
+// Product list orderings accepted in ProductFilter.Sort; empty means SortNewest.
+const (
+	SortNewest    = "newest"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortName      = "name"
+)
+
+// ProductFilter narrows product listings; a zero CategoryID matches every category.
+// SkipCount leaves out the count query, and List then returns a total of 0.
 type ProductFilter struct {
-	Search string
-	Limit  int
-	Offset int
+	Search     string
+	CategoryID uuid.UUID
+	Sort       string
+	Limit      int
+	Offset     int
+	SkipCount  bool
+	// CreatedFrom and CreatedTo bound created_at, both inclusive; a zero time leaves that side open.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// ValidSort reports whether sort is one of the supported orderings (or empty).
+func ValidSort(sort string) bool {
+	switch sort {
+	case "", SortNewest, SortPriceAsc, SortPriceDesc, SortName:
+		return true
+	}
+	return false
 }
 
 type ProductRepository interface {
+	// Create inserts the product; a clash on the owner's external id is domain.ErrExternalIDExists.
 	Create(ctx context.Context, product *domain.Product) error
 	Update(ctx context.Context, product *domain.Product) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error)
+	// GetByExternalID finds an owner's product by its external id; none is domain.ErrProductNotFound.
+	GetByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*domain.Product, error)
 	List(ctx context.Context, filter ProductFilter) ([]domain.Product, int64, error)
+	// DecrementStockBatch subtracts each quantity from its product's stock in a single statement.
+	// Products without enough stock (or that do not exist) are left untouched and returned; callers
+	// running inside a UnitOfWork should roll back when any are returned.
+	DecrementStockBatch(ctx context.Context, quantities map[uuid.UUID]int) ([]uuid.UUID, error)
+	// IncrementStock adds qty to the product's stock in a single statement, so it never overwrites
+	// a concurrent change; a missing product is domain.ErrProductNotFound.
+	IncrementStock(ctx context.Context, id uuid.UUID, qty int) error
+	// SuggestByPrefix returns up to limit products whose name starts with prefix (case-insensitive),
+	// ordered by name. The prefix is matched literally; LIKE wildcards in it are escaped.
+	SuggestByPrefix(ctx context.Context, prefix string, limit int) ([]domain.ProductSuggestion, error)
+	// CountByCategory returns the number of products per category, largest first.
+	CountByCategory(ctx context.Context) ([]domain.CategoryCount, error)
+	// ListAfter returns up to limit products with an id greater than after, ordered by id. Passing the
+	// last id of one page as after fetches the next (keyset pagination); uuid.Nil starts from the beginning.
+	ListAfter(ctx context.Context, after uuid.UUID, limit int) ([]domain.Product, error)
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// ProductViewRepository stores saved product views. Lookups are scoped to the owner so one user
+// can never read or change another user's view.
+type ProductViewRepository interface {
+	Create(ctx context.Context, view *domain.ProductView) error
+	Update(ctx context.Context, view *domain.ProductView) error
+	Delete(ctx context.Context, ownerID, id uuid.UUID) error
+	GetByID(ctx context.Context, ownerID, id uuid.UUID) (*domain.ProductView, error)
+	FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*domain.ProductView, error)
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.ProductView, error)
+}
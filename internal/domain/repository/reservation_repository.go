@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// ReservationRepository stores stock reservations. Use it through a UnitOfWork: every status change
+// goes together with a stock change on the product.
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *domain.Reservation) error
+	// GetByIDForUpdate locks the reservation for the rest of the transaction.
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Reservation, error)
+	// ListExpired returns up to limit active reservations that expired before the given time,
+	// oldest first, skipping rows another transaction has locked.
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.Reservation, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ReservationStatus) error
+}
@@ -2,7 +2,8 @@ package repository
 
 import "context"
 
-// UnitOfWork defines a transaction boundary for repositories.
+// UnitOfWork defines a transaction boundary for repositories. Use it when several writes (or a read
+// that decides a later write) must commit together; plain reads should call a repository directly.
 type UnitOfWork interface {
 	Execute(ctx context.Context, fn func(tx RepositoryProvider) error) error
 }
@@ -12,4 +13,6 @@ type RepositoryProvider interface {
 	Users() UserRepository
 	Products() ProductRepository
 	Orders() OrderRepository
+	Reservations() ReservationRepository
+	PasswordResets() PasswordResetRepository
 }
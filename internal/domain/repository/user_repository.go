@@ -14,4 +14,14 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	UpdateRole(ctx context.Context, id uuid.UUID, role domain.Role) error
+	// SetRole is UpdateRole that refuses, with domain.ErrLastAdmin, to take admin away from the
+	// last admin.
+	SetRole(ctx context.Context, id uuid.UUID, role domain.Role) error
+	// UpdatePassword stores the new hash and stamps PasswordChangedAt, which revokes the user's
+	// earlier access tokens.
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashed string) error
+	// UpdateEmail changes the email and clears EmailVerified.
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	// MarkEmailVerified sets EmailVerified, but only while the stored email still equals email.
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error
 }
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReservationStatus is the lifecycle state of a stock reservation.
+type ReservationStatus string
+
+const (
+	ReservationStatusActive   ReservationStatus = "active"
+	ReservationStatusReleased ReservationStatus = "released"
+	ReservationStatusConsumed ReservationStatus = "consumed"
+)
+
+// Reservation holds Quantity units of a product for UserID until ExpiresAt, e.g. while the user is
+// at the payment step. The units are taken out of the product's stock when the reservation is made
+// and returned when it is released or expires; an order consumes it instead of taking stock again.
+type Reservation struct {
+	ID        uuid.UUID         `json:"id"`
+	ProductID uuid.UUID         `json:"productId"`
+	UserID    uuid.UUID         `json:"userId"`
+	Quantity  int               `json:"quantity"`
+	Status    ReservationStatus `json:"status"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,13 +14,32 @@ const (
 	RoleUser  Role = "user"
 )
 
+// Roles lists every supported role.
+var Roles = []Role{RoleAdmin, RoleUser}
+
+// ParseRole returns the supported role named s, ignoring case and surrounding space.
+func ParseRole(s string) (Role, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, r := range Roles {
+		if string(r) == s {
+			return r, true
+		}
+	}
+	return "", false
+}
+
 // User represents a user within the domain layer.
+// EmailVerified is set once the user follows a verification link sent to Email and is cleared
+// whenever Email changes. PasswordChangedAt is nil until the password is first changed; access
+// tokens issued before it are no longer accepted.
 type User struct {
-	ID        uuid.UUID
-	Username  string
-	Email     string
-	Password  string
-	Role      Role
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                uuid.UUID
+	Username          string
+	Email             string
+	Password          string
+	Role              Role
+	EmailVerified     bool
+	PasswordChangedAt *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
@@ -8,25 +8,26 @@ import (
 	"gorm.io/gorm"
 
 	"context"
-	"strings"
-	"time"
 
-	"github.com/google/uuid"
 	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/adapter/event"
 	"github.com/minilik/ecommerce/internal/adapter/handler"
 	mw "github.com/minilik/ecommerce/internal/adapter/middleware"
 	gormrepo "github.com/minilik/ecommerce/internal/adapter/repository/gorm"
 	"github.com/minilik/ecommerce/internal/adapter/router"
-	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
 	"github.com/minilik/ecommerce/internal/infrastructure/database"
 	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
 	orderusecase "github.com/minilik/ecommerce/internal/usecase/order"
 	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
 	"github.com/minilik/ecommerce/pkg/cache"
+	"github.com/minilik/ecommerce/pkg/clock"
 	"github.com/minilik/ecommerce/pkg/cloudinary"
 	hashpkg "github.com/minilik/ecommerce/pkg/hash"
 	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
 	"github.com/minilik/ecommerce/pkg/logger"
+	"github.com/minilik/ecommerce/pkg/metrics"
+	"github.com/minilik/ecommerce/pkg/response"
 )
 
 type DIContainer struct {
@@ -34,14 +35,25 @@ type DIContainer struct {
 	Logger *zap.Logger
 	DB     *gorm.DB
 	Router *gin.Engine
+
+	// stopSweeper ends the background reservation sweep.
+	stopSweeper context.CancelFunc
 }
 
 // Build initializes and wires all application dependencies... DI container pattern
 func Build(cfg *config.Config) (*DIContainer, error) {
-	log, err := logger.New(cfg.App.Environment)
+	log, err := logger.New(cfg.App.Environment, logger.Options{
+		Level:              cfg.Log.Level,
+		Format:             cfg.Log.Format,
+		SamplingInitial:    cfg.Log.Sampling.Initial,
+		SamplingThereafter: cfg.Log.Sampling.Thereafter,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("initialize logger: %w", err)
 	}
+	if err := response.UseTimestampFormat(cfg.Server.TimestampFormat, cfg.Server.TimeZone); err != nil {
+		return nil, err
+	}
 
 	db, err := database.NewPostgres(cfg.Database, log)
 	if err != nil {
@@ -53,75 +65,170 @@ func Build(cfg *config.Config) (*DIContainer, error) {
 	}
 
 	hasher := hashpkg.NewBcryptHasher(0)
-	jwtManager, err := jwtpkg.NewManager(cfg.JWT.Secret)
+	jwtManager, err := jwtpkg.NewManager(cfg.JWT.Secret, cfg.JWT.Audience)
 	if err != nil {
 		return nil, fmt.Errorf("create jwt manager: %w", err)
 	}
+	cdnBase, err := handler.ParseCDNBase(cfg.Cloud.CDNBase)
+	if err != nil {
+		return nil, err
+	}
 
 	userRepo := gormrepo.NewUserRepository(db)
 	productRepo := gormrepo.NewProductRepository(db)
 	orderRepo := gormrepo.NewOrderRepository(db)
+	categoryRepo := gormrepo.NewCategoryRepository(db)
 	uow := gormrepo.NewUnitOfWork(db)
 
-	authService := authusecase.NewService(userRepo, hasher, jwtManager, cfg, log)
+	clk := clock.Real()
+
+	if path := cfg.Auth.BlockedEmailDomainsFile; path != "" {
+		domains, err := authusecase.LoadEmailDomains(path)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Auth.BlockedEmailDomains = append(cfg.Auth.BlockedEmailDomains, domains...)
+	}
+	eventPublisher := event.NewLogPublisher(log)
+	authService := authusecase.NewService(userRepo, gormrepo.NewPasswordResetRepository(db), uow, hasher, jwtManager, eventPublisher, cfg, clk, log)
 	var prodCache *cache.MemoryCache
 	if cfg.Cache.Enabled {
-		prodCache = cache.NewMemoryCache(cfg.Cache.ProductListTTL, cfg.Cache.MaxProductEntries)
+		prodCache = cache.NewMemoryCache(cfg.Cache.ProductListTTL, cfg.Cache.MaxProductEntries).
+			WithSoftLimit(cfg.Cache.SoftLimitRatio, func(s cache.Stats) {
+				log.Warn("product cache near capacity",
+					zap.Int("size", s.Size),
+					zap.Int("max", s.Max),
+					zap.Uint64("rejected", s.Rejected))
+			})
+	}
+	productCache := cache.Memory(prodCache)
+	productService := productusecase.NewService(productRepo, orderRepo, categoryRepo, cfg.Product, clk, log, productCache)
+	viewService := productusecase.NewViewService(gormrepo.NewProductViewRepository(db), categoryRepo, clk, log)
+	// orders and reservations change stock inside their transactions, so cached product details
+	// are dropped once those commit
+	stockUow := productusecase.InvalidatingUnitOfWork(uow, productCache, log)
+	orderService := orderusecase.NewService(stockUow, orderRepo, eventPublisher, cfg.Order, clk, log)
+	reservationService := orderusecase.NewReservationService(stockUow, cfg.Order, clk, log)
+	stopSweeper := func() {}
+	if cfg.Order.ReservationSweepInterval > 0 {
+		var sweepCtx context.Context
+		sweepCtx, stopSweeper = context.WithCancel(context.Background())
+		go orderusecase.RunReservationSweeper(sweepCtx, reservationService, cfg.Order.ReservationSweepInterval, log)
 	}
-	productService := productusecase.NewService(productRepo, orderRepo, log, prodCache)
-	orderService := orderusecase.NewService(uow, log)
 
 	// Cloudinary uploader + image repo/service
 	var uploader *cloudinary.Client
 	if cfg.Cloud.CloudName != "" && (cfg.Cloud.UploadPreset != "" || cfg.Cloud.APIKey != "") {
 		uploader = cloudinary.NewClient(cfg.Cloud.CloudName, cfg.Cloud.APIKey, cfg.Cloud.APISecret, cfg.Cloud.UploadPreset, cfg.Cloud.Folder)
+		uploader.Breaker = cloudinary.NewBreaker(cfg.Cloud.BreakerThreshold, cfg.Cloud.BreakerCooldown, clk)
+	} else {
+		log.Warn("cloudinary is not configured; image uploads are disabled",
+			zap.Bool("cloud_name_set", cfg.Cloud.CloudName != ""),
+			zap.Bool("upload_preset_set", cfg.Cloud.UploadPreset != ""),
+			zap.Bool("api_key_set", cfg.Cloud.APIKey != ""))
 	}
-	imageRepo := gormrepo.NewProductImageRepository(db)
-	imageService := productusecase.NewImageService(imageRepo, uploader, log)
-
-	// Seed initial admin (idempotent)
-	if cfg.Admin.Enabled && cfg.Admin.Email != "" && cfg.Admin.Password != "" {
-		if existing, err := userRepo.FindByEmail(context.Background(), strings.ToLower(cfg.Admin.Email)); err == nil && existing == nil {
-			hashed, err := hasher.Hash(cfg.Admin.Password)
-			if err == nil {
-				admin := &domain.User{
-					ID:        uuid.New(),
-					Username:  cfg.Admin.Username,
-					Email:     strings.ToLower(cfg.Admin.Email),
-					Password:  hashed,
-					Role:      domain.RoleAdmin,
-					CreatedAt: time.Now(),
-					UpdatedAt: time.Now(),
-				}
-				if err := userRepo.Create(context.Background(), admin); err != nil {
-					log.Warn("admin seed failed", zap.Error(err))
-				} else {
-					log.Info("admin user seeded", zap.String("email", cfg.Admin.Email))
-				}
-			} else {
-				log.Warn("admin seed hash password failed", zap.Error(err))
-			}
+	assetRepo := productusecase.InvalidatingAssets(gormrepo.NewProductAssetRepository(db), productCache, log)
+	var folderEnv string
+	if cfg.Cloud.FolderPerEnvironment {
+		folderEnv = cfg.App.Environment
+	}
+	var folderProducts repository.ProductRepository
+	var folderCategories repository.CategoryRepository
+	if cfg.Cloud.FolderPerCategory {
+		folderProducts, folderCategories = productRepo, categoryRepo
+	}
+	uploadFolders := productusecase.NewUploadFolders(cfg.Cloud.Folder, folderEnv, folderProducts, folderCategories, log)
+	imageService := productusecase.NewImageService(assetRepo, uploader, cfg.Product.ImageExtensions, cfg.Product.DocumentExtensions, cfg.Cloud.UploadConcurrency, cfg.Product.MaxUploadBytes, cfg.Product.DedupUploads, uploadFolders, cfg.Cloud.SignedURLTTL, clk, log)
+
+	// Seed initial admins (idempotent)
+	seedAdmins(context.Background(), cfg.Admin, userRepo, hasher, clk, log)
+	if cfg.App.SeedSampleData {
+		if err := seedSampleData(context.Background(), cfg.App.Environment, authService, productService, userRepo, categoryRepo, clk, log); err != nil {
+			log.Warn("sample data not seeded", zap.Error(err))
 		}
 	}
 
-	authHandler := handler.NewAuthHandler(authService, log)
-	productHandler := handler.NewProductHandler(productService, log).WithImageService(imageService)
+	authHandler := handler.NewAuthHandler(authService, log).
+		WithTokenDelivery(handler.TokenDelivery(cfg.JWT.Delivery), cfg.JWT.CookieName, cfg.JWT.CookieSecure)
+	productHandler := handler.NewProductHandler(productService, log).
+		WithImageService(imageService).
+		WithViewService(viewService).
+		WithCDNBase(cdnBase).
+		WithImageCacheMaxAge(cfg.Product.ImageCacheMaxAge).
+		WithSuggestCacheMaxAge(cfg.Product.SuggestCacheMaxAge).
+		WithCategoryCacheMaxAge(cfg.Product.CategoryCacheMaxAge).
+		WithListTotals(cfg.Product.ListTotals)
+	productViewHandler := handler.NewProductViewHandler(viewService, log)
 	orderHandler := handler.NewOrderHandler(orderService, log)
-	adminHandler := handler.NewAdminHandler(authService, log)
+	reservationHandler := handler.NewReservationHandler(reservationService, log)
+	uploadMetrics := metrics.NewHistogram(metrics.UploadSizeBuckets)
+	adminHandler := handler.NewAdminHandler(authService, log).
+		WithUploadMetrics(uploadMetrics).
+		WithCache(productCache)
+	healthHandler := handler.NewHealthHandler(cfg.Server.HealthCritical, cfg.Server.HealthTimeout, log).
+		WithCheck("db", func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}).
+		WithCheck("cache", func(context.Context) error {
+			// caching switched off is a configuration choice, not a failure
+			if prodCache == nil {
+				return nil
+			}
+			if stats := prodCache.Stats(); stats.Max > 0 && stats.Size >= stats.Max {
+				return fmt.Errorf("cache full at %d entries: %w", stats.Size, handler.ErrDegraded)
+			}
+			return nil
+		}).
+		WithCheck("uploader", func(context.Context) error {
+			if !imageService.Enabled() {
+				return fmt.Errorf("cloudinary is not configured: %w", handler.ErrDegraded)
+			}
+			if uploader.Breaker.State() == cloudinary.BreakerOpen {
+				return fmt.Errorf("cloudinary circuit breaker is open: %w", handler.ErrDegraded)
+			}
+			return nil
+		})
 
-	authMiddleware := mw.NewAuthMiddleware(log, jwtManager)
+	authMiddleware := mw.NewAuthMiddleware(log, jwtManager).
+		WithTokenHeader(cfg.JWT.TokenHeader, cfg.JWT.TokenScheme).
+		WithRevocationCheck(authService.TokenRevoked)
+	if mode := handler.TokenDelivery(cfg.JWT.Delivery); mode == handler.TokenDeliveryCookie || mode == handler.TokenDeliveryBoth {
+		authMiddleware.WithTokenCookie(cfg.JWT.CookieName)
+	}
 	var rateLimiter *mw.RateLimitMiddleware
 	if cfg.Rate.Enabled && cfg.Rate.Limit > 0 && cfg.Rate.Window > 0 {
 		rateLimiter = mw.NewRateLimitMiddleware(cfg.Rate.Limit, cfg.Rate.Window)
 	}
+	var concurrencyLimiter *mw.ConcurrencyLimitMiddleware
+	if cfg.Rate.MaxConcurrent > 0 {
+		concurrencyLimiter = mw.NewConcurrencyLimitMiddleware(cfg.Rate.MaxConcurrent)
+	}
 
 	engine := router.Setup(router.Dependencies{
-		AuthHandler:    authHandler,
-		ProductHandler: productHandler,
-		OrderHandler:   orderHandler,
-		AdminHandler:   adminHandler,
-		AuthMiddleware: authMiddleware,
-		RateLimiter:    rateLimiter,
+		AuthHandler:          authHandler,
+		ProductHandler:       productHandler,
+		OrderHandler:         orderHandler,
+		AdminHandler:         adminHandler,
+		ProductViewHandler:   productViewHandler,
+		ReservationHandler:   reservationHandler,
+		AuthMiddleware:       authMiddleware,
+		RateLimiter:          rateLimiter,
+		ConcurrencyLimiter:   concurrencyLimiter,
+		CorsMaxAge:           cfg.Cors.MaxAge,
+		Logger:               log,
+		DebugRoutes:          cfg.Log.DebugRoutes,
+		SlowRequestThreshold: cfg.Log.SlowRequestThreshold,
+		UploadMetrics:        uploadMetrics,
+		Transaction:          mw.Transaction(uow),
+		ReadOnly:             cfg.App.ReadOnly,
+		TokenHeader:          cfg.JWT.TokenHeader,
+		RequestTimeout:       cfg.Server.RequestTimeout,
+		UploadTimeout:        cfg.Server.UploadTimeout,
+		HealthHandler:        healthHandler,
 	})
 
 	return &DIContainer{
@@ -129,11 +236,16 @@ func Build(cfg *config.Config) (*DIContainer, error) {
 		Logger: log,
 		DB:     db,
 		Router: engine,
+
+		stopSweeper: stopSweeper,
 	}, nil
 }
 
 // Close releases resources held by the container.
 func (c *DIContainer) Close() error {
+	if c.stopSweeper != nil {
+		c.stopSweeper()
+	}
 	logger.Sync(c.Logger)
 	if c.DB == nil {
 		return nil
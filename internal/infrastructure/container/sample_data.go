@@ -0,0 +1,106 @@
+package di_container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// Sample catalog seeded by app.seed_sample_data. The demo user owns every sample product.
+const (
+	sampleUserEmail    = "demo@example.com"
+	sampleUserName     = "demo"
+	sampleUserPassword = "Demo#12345"
+)
+
+// sampleNamespace derives the fixed category ids that make the seed idempotent.
+var sampleNamespace = uuid.MustParse("6f1c2b0e-5d2a-4c8e-9b7a-3e4f5a6b7c8d")
+
+var sampleCategories = []domain.Category{
+	{Name: "Electronics", Description: "Gadgets, accessories and devices"},
+	{Name: "Home", Description: "Furniture and things for around the house"},
+	{Name: "Books", Description: "Printed books and notebooks"},
+}
+
+// sampleProducts carry fixed external ids, so product creation returns the existing row on re-runs.
+var sampleProducts = []productusecase.CreateProductInput{
+	{ExternalID: "sample-headphones", Name: "Wireless Headphones", Description: "Over-ear headphones with 30 hours of battery", Price: 79.99, Stock: 25, Category: "Electronics"},
+	{ExternalID: "sample-keyboard", Name: "Mechanical Keyboard", Description: "Tenkeyless keyboard with brown switches", Price: 59.5, Stock: 40, Category: "Electronics"},
+	{ExternalID: "sample-desk-lamp", Name: "Desk Lamp", Description: "Adjustable LED desk lamp with warm light", Price: 24, Stock: 60, Category: "Home"},
+	{ExternalID: "sample-mug", Name: "Stoneware Mug", Description: "Hand-glazed 350 ml stoneware mug", Price: 12.75, Stock: 100, Category: "Home"},
+	{ExternalID: "sample-notebook", Name: "Dotted Notebook", Description: "A5 notebook with 160 dotted pages", Price: 9.9, Stock: 0, Category: "Books"},
+}
+
+// seedSampleData fills a development database with a demo user, a few categories and products.
+// The user and products go through the auth and product services, so they are validated like API
+// input. Running it again changes nothing. It refuses to run in production.
+func seedSampleData(ctx context.Context, environment string, auth authusecase.Service, products productusecase.Service, users repository.UserRepository, categories repository.CategoryRepository, clk clock.Clock, log *zap.Logger) error {
+	switch strings.ToLower(environment) {
+	case "production", "prod":
+		return fmt.Errorf("sample data is not seeded in %s", environment)
+	}
+
+	ownerID, err := seedSampleUser(ctx, auth, users)
+	if err != nil {
+		return fmt.Errorf("seed demo user: %w", err)
+	}
+
+	for _, category := range sampleCategories {
+		category.ID = uuid.NewSHA1(sampleNamespace, []byte(category.Name))
+		_, err := categories.GetByID(ctx, category.ID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrCategoryNotFound) {
+			return fmt.Errorf("seed category %s: %w", category.Name, err)
+		}
+		category.CreatedAt = clk.Now()
+		category.UpdatedAt = clk.Now()
+		if err := categories.Create(ctx, &category); err != nil {
+			return fmt.Errorf("seed category %s: %w", category.Name, err)
+		}
+	}
+
+	for _, input := range sampleProducts {
+		if _, err := products.Create(ctx, ownerID, input); err != nil {
+			return fmt.Errorf("seed product %s: %w", input.Name, err)
+		}
+	}
+
+	log.Info("sample data seeded",
+		zap.String("demo_user", sampleUserEmail),
+		zap.Int("categories", len(sampleCategories)),
+		zap.Int("products", len(sampleProducts)))
+	return nil
+}
+
+// seedSampleUser creates the demo user unless it exists and returns its id. It goes through
+// CreateUser so seeding also works with self-registration disabled.
+func seedSampleUser(ctx context.Context, auth authusecase.Service, users repository.UserRepository) (uuid.UUID, error) {
+	existing, err := users.FindByEmail(ctx, sampleUserEmail)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+	created, err := auth.CreateUser(ctx, authusecase.CreateUserInput{
+		Username: sampleUserName,
+		Email:    sampleUserEmail,
+		Password: sampleUserPassword,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return created.UserID, nil
+}
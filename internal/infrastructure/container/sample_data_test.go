@@ -0,0 +1,107 @@
+package di_container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	authusecase "github.com/minilik/ecommerce/internal/usecase/auth"
+	productusecase "github.com/minilik/ecommerce/internal/usecase/product"
+	"github.com/minilik/ecommerce/pkg/clock"
+	hashpkg "github.com/minilik/ecommerce/pkg/hash"
+)
+
+func (r *fakeUserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeCategoryRepo struct {
+	repository.CategoryRepository
+	categories map[uuid.UUID]domain.Category
+}
+
+func (r *fakeCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	c, ok := r.categories[id]
+	if !ok {
+		return nil, domain.ErrCategoryNotFound
+	}
+	return &c, nil
+}
+
+func (r *fakeCategoryRepo) Create(ctx context.Context, category *domain.Category) error {
+	r.categories[category.ID] = *category
+	return nil
+}
+
+func (r *fakeCategoryRepo) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Category, int64, error) {
+	out := make([]domain.Category, 0, len(r.categories))
+	for _, c := range r.categories {
+		out = append(out, c)
+	}
+	return out, int64(len(out)), nil
+}
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+	products []domain.Product
+}
+
+func (r *fakeProductRepo) Create(ctx context.Context, product *domain.Product) error {
+	r.products = append(r.products, *product)
+	return nil
+}
+
+func (r *fakeProductRepo) GetByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*domain.Product, error) {
+	for _, p := range r.products {
+		if p.UserID == ownerID && p.ExternalID == externalID {
+			return &p, nil
+		}
+	}
+	return nil, domain.ErrProductNotFound
+}
+
+func TestSeedSampleData_Idempotent(t *testing.T) {
+	users := &fakeUserRepo{users: map[string]*domain.User{}}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{}}
+	products := &fakeProductRepo{}
+	cfg := &config.Config{}
+	auth := authusecase.NewService(users, nil, nil, hashpkg.NewBcryptHasher(bcrypt.MinCost), nil, nil, cfg, clock.Real(), zap.NewNop())
+	catalog := productusecase.NewService(products, nil, categories, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+	for run := 0; run < 2; run++ {
+		require.NoError(t, seedSampleData(context.Background(), "development", auth, catalog, users, categories, clock.Real(), zap.NewNop()), "run %d", run+1)
+	}
+
+	require.Len(t, users.users, 1)
+	demo := users.users[sampleUserEmail]
+	require.NotNil(t, demo)
+	assert.Equal(t, domain.RoleUser, demo.Role)
+	assert.Len(t, categories.categories, len(sampleCategories))
+	require.Len(t, products.products, len(sampleProducts))
+	for _, p := range products.products {
+		assert.Equal(t, demo.ID, p.UserID)
+	}
+}
+
+func TestSeedSampleData_RefusesProduction(t *testing.T) {
+	users := &fakeUserRepo{users: map[string]*domain.User{}}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{}}
+
+	err := seedSampleData(context.Background(), "Production", nil, nil, users, categories, clock.Real(), zap.NewNop())
+	assert.Error(t, err)
+	assert.Empty(t, users.users)
+	assert.Empty(t, categories.categories)
+}
@@ -0,0 +1,78 @@
+package di_container
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+	hashpkg "github.com/minilik/ecommerce/pkg/hash"
+)
+
+// seedAdmins seeds the primary admin and every additional admin listed in the config.
+func seedAdmins(ctx context.Context, cfg config.AdminSeed, users repository.UserRepository, hasher hashpkg.Hasher, clk clock.Clock, log *zap.Logger) {
+	for _, account := range cfg.Accounts() {
+		seedAdmin(ctx, account, users, hasher, clk, log)
+	}
+}
+
+// seedAdmin creates the configured admin user when it does not exist yet (idempotent).
+// When SyncPassword is set and the admin already exists, its password is replaced with the configured one.
+func seedAdmin(ctx context.Context, cfg config.AdminSeed, users repository.UserRepository, hasher hashpkg.Hasher, clk clock.Clock, log *zap.Logger) {
+	if !cfg.Enabled || cfg.Email == "" || cfg.Password == "" {
+		return
+	}
+	email := strings.ToLower(cfg.Email)
+
+	existing, err := users.FindByEmail(ctx, email)
+	if err != nil {
+		log.Warn("admin seed lookup failed", zap.Error(err))
+		return
+	}
+
+	if existing != nil {
+		if !cfg.SyncPassword {
+			return
+		}
+		// skip the write when the stored hash already matches the configured password
+		if err := hasher.Compare(cfg.Password, existing.Password); err == nil {
+			return
+		}
+		hashed, err := hasher.Hash(cfg.Password)
+		if err != nil {
+			log.Warn("admin seed hash password failed", zap.Error(err))
+			return
+		}
+		if err := users.UpdatePassword(ctx, existing.ID, hashed); err != nil {
+			log.Warn("admin password sync failed", zap.Error(err))
+			return
+		}
+		log.Info("admin password synced from config", zap.String("email", email))
+		return
+	}
+
+	hashed, err := hasher.Hash(cfg.Password)
+	if err != nil {
+		log.Warn("admin seed hash password failed", zap.Error(err))
+		return
+	}
+	admin := &domain.User{
+		ID:        uuid.New(),
+		Username:  cfg.Username,
+		Email:     email,
+		Password:  hashed,
+		Role:      domain.RoleAdmin,
+		CreatedAt: clk.Now(),
+		UpdatedAt: clk.Now(),
+	}
+	if err := users.Create(ctx, admin); err != nil {
+		log.Warn("admin seed failed", zap.Error(err))
+		return
+	}
+	log.Info("admin user seeded", zap.String("email", cfg.Email))
+}
@@ -0,0 +1,96 @@
+package di_container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+	hashpkg "github.com/minilik/ecommerce/pkg/hash"
+)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.users[email], nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.users[user.Email] = user
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hashed string) error {
+	for _, u := range r.users {
+		if u.ID == id {
+			u.Password = hashed
+			return nil
+		}
+	}
+	return domain.ErrUserNotFound
+}
+
+func TestSeedAdmin_SyncPassword(t *testing.T) {
+	hasher := hashpkg.NewBcryptHasher(bcrypt.MinCost)
+	oldHash, err := hasher.Hash("Old#12345")
+	require.NoError(t, err)
+
+	newRepo := func() *fakeUserRepo {
+		return &fakeUserRepo{users: map[string]*domain.User{
+			"admin@example.com": {ID: uuid.New(), Email: "admin@example.com", Password: oldHash, Role: domain.RoleAdmin},
+		}}
+	}
+	cfg := config.AdminSeed{Enabled: true, Email: "admin@example.com", Username: "admin", Password: "New#12345"}
+
+	t.Run("default keeps existing password", func(t *testing.T) {
+		repo := newRepo()
+		seedAdmin(context.Background(), cfg, repo, hasher, clock.Real(), zap.NewNop())
+		assert.Equal(t, oldHash, repo.users["admin@example.com"].Password)
+	})
+
+	t.Run("sync updates existing hash", func(t *testing.T) {
+		repo := newRepo()
+		syncCfg := cfg
+		syncCfg.SyncPassword = true
+		seedAdmin(context.Background(), syncCfg, repo, hasher, clock.Real(), zap.NewNop())
+
+		stored := repo.users["admin@example.com"].Password
+		assert.NotEqual(t, oldHash, stored)
+		assert.NoError(t, hasher.Compare("New#12345", stored))
+	})
+}
+
+func TestSeedAdmins_MultipleUsers(t *testing.T) {
+	hasher := hashpkg.NewBcryptHasher(bcrypt.MinCost)
+	repo := &fakeUserRepo{users: map[string]*domain.User{}}
+	cfg := config.AdminSeed{
+		Enabled: true,
+		Users: []config.AdminUser{
+			{Email: "Ops@Example.com", Username: "ops", Password: "Ops#12345"},
+			{Email: "billing@example.com", Username: "billing", Password: "Billing#12345"},
+		},
+	}
+
+	seedAdmins(context.Background(), cfg, repo, hasher, clock.Real(), zap.NewNop())
+	// a second run must not create duplicates
+	seedAdmins(context.Background(), cfg, repo, hasher, clock.Real(), zap.NewNop())
+
+	require.Len(t, repo.users, 2)
+	for email, username := range map[string]string{"ops@example.com": "ops", "billing@example.com": "billing"} {
+		user := repo.users[email]
+		require.NotNil(t, user, email)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+		assert.Equal(t, username, user.Username)
+	}
+}
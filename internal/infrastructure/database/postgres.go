@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -17,6 +18,10 @@ import (
 func NewPostgres(cfg config.DatabaseConfig, log *zap.Logger) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+	if cfg.StatementTimeout > 0 {
+		// Sent as a startup parameter so the server cancels runaway statements on every connection.
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
 
 	gormLogger := logger.New(
 		zap.NewStdLog(log),
@@ -34,6 +39,12 @@ func NewPostgres(cfg config.DatabaseConfig, log *zap.Logger) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("connect to database: %w", err)
 	}
+	if err := RegisterQueryTimeout(db, cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+	if err := RegisterStaleConnRetry(db, log); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -43,18 +54,35 @@ func NewPostgres(cfg config.DatabaseConfig, log *zap.Logger) (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(25)
 	sqlDB.SetMaxIdleConns(25)
 	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	// close idle connections before a proxy or firewall silently drops them
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	return db, nil
 }
 
 // Migrate runs database migrations.
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		&models.User{},
 		&models.Product{},
 		&models.Order{},
 		&models.OrderItem{},
+		&models.OrderStatusChange{},
 		&models.ProductImage{},
 		&models.Category{},
+		&models.ProductView{},
+		&models.PasswordReset{},
+		&models.Reservation{},
 	)
+	if err != nil {
+		return err
+	}
+	// products created before category_id was written only carry the category name
+	if err := db.Exec(`UPDATE products SET category_id = category.id FROM category
+		WHERE (products.category_id IS NULL OR products.category_id = ?)
+		AND lower(category.name) = lower(products.category)`, uuid.Nil).Error; err != nil {
+		return fmt.Errorf("backfill product category ids: %w", err)
+	}
+	// order numbers come from a sequence so concurrent orders never draw the same value
+	return db.Exec("CREATE SEQUENCE IF NOT EXISTS order_number_seq").Error
 }
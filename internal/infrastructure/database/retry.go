@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// RegisterStaleConnRetry re-runs a read once when it failed on a pooled connection that had been
+// dropped underneath us, e.g. by a proxy closing idle connections. database/sql already retries
+// when the driver reports driver.ErrBadConn before sending anything; this covers a connection that
+// dies while the query is in flight. Only queries outside a transaction are retried: they are
+// idempotent, and the retry goes out on another connection from the pool.
+func RegisterStaleConnRetry(db *gorm.DB, log *zap.Logger) error {
+	retry := func(tx *gorm.DB) {
+		if tx.Error == nil || !isStaleConn(tx.Error) || inTransaction(tx) {
+			return
+		}
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("retrying query on stale database connection", zap.Error(tx.Error))
+		tx.Error = nil
+		tx.RowsAffected = 0
+		callbacks.Query(tx)
+	}
+	if err := db.Callback().Query().After("gorm:query").Before("gorm:preload").Register("stale:retry_query", retry); err != nil {
+		return fmt.Errorf("register stale connection retry: %w", err)
+	}
+	return nil
+}
+
+// isStaleConn reports whether err means the connection was gone rather than the query failing.
+func isStaleConn(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+func inTransaction(tx *gorm.DB) bool {
+	_, ok := tx.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newRetryDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterStaleConnRetry(db, zap.NewNop()))
+	return db, mock
+}
+
+func TestRegisterStaleConnRetry_RecoversBrokenConnection(t *testing.T) {
+	db, mock := newRetryDB(t)
+
+	// the pooled connection was dropped by a proxy: the first read hits EOF, the retry succeeds
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(io.ErrUnexpectedEOF)
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+	var out []map[string]interface{}
+	err := db.WithContext(context.Background()).Raw("SELECT 1").Find(&out).Error
+
+	require.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterStaleConnRetry_RetriesOnce(t *testing.T) {
+	db, mock := newRetryDB(t)
+
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(io.ErrUnexpectedEOF)
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(io.ErrUnexpectedEOF)
+
+	var out []map[string]interface{}
+	err := db.Raw("SELECT 1").Find(&out).Error
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterStaleConnRetry_LeavesQueryErrorsAlone(t *testing.T) {
+	db, mock := newRetryDB(t)
+
+	mock.ExpectQuery(`SELECT nope`).WillReturnError(assert.AnError)
+
+	var out []map[string]interface{}
+	err := db.Raw("SELECT nope").Find(&out).Error
+
+	require.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegisterStaleConnRetry_SkipsTransactions(t *testing.T) {
+	db, mock := newRetryDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(io.ErrUnexpectedEOF)
+	mock.ExpectRollback()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var out []map[string]interface{}
+		return tx.Raw("SELECT 1").Find(&out).Error
+	})
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
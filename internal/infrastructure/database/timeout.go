@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryCancelKey = "database:query_cancel"
+
+// RegisterQueryTimeout bounds every create, query, update, delete and raw statement whose context
+// has no deadline of its own. Callers that already set a deadline (e.g. a transaction with a larger
+// budget) keep it. Row/Rows are left alone because their results are read after the callback returns.
+func RegisterQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	before := func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if _, ok := ctx.Deadline(); ok {
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryCancelKey, cancel)
+	}
+	after := func(tx *gorm.DB) {
+		if v, ok := tx.InstanceGet(queryCancelKey); ok {
+			if cancel, ok := v.(context.CancelFunc); ok {
+				cancel()
+			}
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("*").Register("timeout:before_create", before),
+		cb.Create().After("*").Register("timeout:after_create", after),
+		cb.Query().Before("*").Register("timeout:before_query", before),
+		cb.Query().After("*").Register("timeout:after_query", after),
+		cb.Update().Before("*").Register("timeout:before_update", before),
+		cb.Update().After("*").Register("timeout:after_update", after),
+		cb.Delete().Before("*").Register("timeout:before_delete", before),
+		cb.Delete().After("*").Register("timeout:after_delete", after),
+		cb.Raw().Before("*").Register("timeout:before_raw", before),
+		cb.Raw().After("*").Register("timeout:after_raw", after),
+	} {
+		if err != nil {
+			return fmt.Errorf("register query timeout: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRegisterQueryTimeout_CancelsSlowQuery(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterQueryTimeout(db, 50*time.Millisecond))
+
+	mock.ExpectQuery(`SELECT pg_sleep`).
+		WillDelayFor(2 * time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_sleep"}).AddRow(""))
+
+	start := time.Now()
+	var out []map[string]interface{}
+	err = db.WithContext(context.Background()).Raw("SELECT pg_sleep(2)").Find(&out).Error
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRegisterQueryTimeout_KeepsCallerDeadline(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterQueryTimeout(db, 10*time.Millisecond))
+
+	mock.ExpectQuery(`SELECT 1`).
+		WillDelayFor(50 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var out []map[string]interface{}
+	err = db.WithContext(ctx).Raw("SELECT 1").Find(&out).Error
+
+	require.NoError(t, err)
+	assert.Len(t, out, 1)
+}
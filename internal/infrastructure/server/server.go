@@ -0,0 +1,90 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+)
+
+// Server runs the API over plain HTTP, or over HTTPS when a certificate and key are configured.
+// With TLS on and a redirect port set, a second plain listener sends every request to HTTPS.
+type Server struct {
+	http     *http.Server
+	redirect *http.Server
+	tls      config.TLSConfig
+	logger   *zap.Logger
+}
+
+// New builds the server for cfg without listening yet. Configuring only one of cert_file and
+// key_file is an error rather than a silent fallback to plain HTTP.
+func New(cfg config.ServerConfig, handler http.Handler, logger *zap.Logger) (*Server, error) {
+	if (cfg.TLS.CertFile == "") != (cfg.TLS.KeyFile == "") {
+		return nil, errors.New("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+	s := &Server{
+		http:   &http.Server{Addr: fmt.Sprintf(":%d", cfg.Port), Handler: handler},
+		tls:    cfg.TLS,
+		logger: logger,
+	}
+	if s.TLSEnabled() && cfg.TLS.RedirectHTTPPort > 0 {
+		s.redirect = &http.Server{
+			Addr:    fmt.Sprintf(":%d", cfg.TLS.RedirectHTTPPort),
+			Handler: RedirectToHTTPS(cfg.Port),
+		}
+	}
+	return s, nil
+}
+
+// TLSEnabled reports whether the API is served over HTTPS.
+func (s *Server) TLSEnabled() bool {
+	return s.tls.CertFile != ""
+}
+
+// ListenAndServe listens on the configured port and serves until a listener fails.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves the API on ln (and the redirect listener, if any) and returns the first error.
+func (s *Server) Serve(ln net.Listener) error {
+	errs := make(chan error, 2)
+	if s.redirect != nil {
+		s.logger.Info("starting HTTPS redirect listener", zap.String("address", s.redirect.Addr))
+		go func() { errs <- s.redirect.ListenAndServe() }()
+	}
+	go func() {
+		if s.TLSEnabled() {
+			s.logger.Info("starting HTTPS server", zap.String("address", ln.Addr().String()))
+			errs <- s.http.ServeTLS(ln, s.tls.CertFile, s.tls.KeyFile)
+			return
+		}
+		s.logger.Info("starting HTTP server", zap.String("address", ln.Addr().String()))
+		errs <- s.http.Serve(ln)
+	}()
+	return <-errs
+}
+
+// RedirectToHTTPS permanently redirects every request to the same host and path on httpsPort.
+// 308 keeps the method and body, so a POST is not turned into a GET.
+func RedirectToHTTPS(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
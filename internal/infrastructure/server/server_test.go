@@ -0,0 +1,124 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+)
+
+// writeSelfSignedCert writes a throwaway certificate and key for 127.0.0.1 into a temp dir.
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// serve starts srv on a free local port and returns its address.
+func serve(t *testing.T, srv *Server) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.http.Close() })
+	return ln.Addr().String()
+}
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+func TestServer_ChoosesTLSWhenCertsConfigured(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	srv, err := New(config.ServerConfig{TLS: config.TLSConfig{CertFile: certFile, KeyFile: keyFile}}, ok, zap.NewNop())
+	require.NoError(t, err)
+	require.True(t, srv.TLSEnabled())
+	addr := serve(t, srv)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	resp, err := client.Get("https://" + addr + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, resp.TLS)
+
+	resp, err = http.Get("http://" + addr + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "plain HTTP is refused on the TLS port")
+}
+
+func TestServer_PlainHTTPWithoutCerts(t *testing.T) {
+	srv, err := New(config.ServerConfig{TLS: config.TLSConfig{RedirectHTTPPort: 8081}}, ok, zap.NewNop())
+	require.NoError(t, err)
+	assert.False(t, srv.TLSEnabled())
+	assert.Nil(t, srv.redirect, "no redirect without TLS")
+	addr := serve(t, srv)
+
+	resp, err := http.Get("http://" + addr + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, resp.TLS)
+}
+
+func TestNew_RequiresCertAndKeyTogether(t *testing.T) {
+	_, err := New(config.ServerConfig{TLS: config.TLSConfig{CertFile: "cert.pem"}}, ok, zap.NewNop())
+	assert.Error(t, err)
+	_, err = New(config.ServerConfig{TLS: config.TLSConfig{KeyFile: "key.pem"}}, ok, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		httpsPort int
+		host      string
+		want      string
+	}{
+		{name: "default port omitted", httpsPort: 443, host: "shop.example.com", want: "https://shop.example.com/api/v1/products?page=2"},
+		{name: "custom port", httpsPort: 8443, host: "shop.example.com:8080", want: "https://shop.example.com:8443/api/v1/products?page=2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products?page=2", nil)
+			req.Host = tc.host
+			w := httptest.NewRecorder()
+			RedirectToHTTPS(tc.httpsPort).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tc.want, w.Header().Get("Location"))
+		})
+	}
+}
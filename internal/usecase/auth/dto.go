@@ -12,13 +12,21 @@ type RegisterInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// CreateUserInput is an account created by an admin. Role defaults to "user".
+type CreateUserInput struct {
+	Username string `json:"username" binding:"required"`
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
 type AuthResponse struct {
-	Token     string    `json:"token"`
+	Token     string    `json:"token,omitempty"`
 	ExpiresAt time.Time `json:"expiresAt"`
 	UserID    uuid.UUID `json:"userId"`
 	Username  string    `json:"username"`
@@ -32,3 +40,53 @@ type RegisterResponse struct {
 	Email    string    `json:"email"`
 	Role     string    `json:"role"`
 }
+
+type UpdateEmailInput struct {
+	Email           string `json:"email" binding:"required"`
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+}
+
+type UpdateEmailResponse struct {
+	UserID uuid.UUID `json:"userId"`
+	Email  string    `json:"email"`
+}
+
+// UserSummary is the admin-facing view of a user; it never carries the password hash.
+type UserSummary struct {
+	UserID        uuid.UUID `json:"userId"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type EmailVerificationResponse struct {
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type VerifyEmailResponse struct {
+	UserID        uuid.UUID `json:"userId"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+}
+
+type PromoteByEmailInput struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// SetRoleInput names the role to give a user, e.g. "admin" or "user".
+type SetRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordInput struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// emailValidator checks address syntax and, when configured, rejects disposable email domains.
+type emailValidator struct {
+	blocked map[string]struct{}
+}
+
+// newEmailValidator builds a validator blocking the given domains (case-insensitive, subdomains included).
+func newEmailValidator(blockedDomains []string) emailValidator {
+	blocked := make(map[string]struct{}, len(blockedDomains))
+	for _, d := range blockedDomains {
+		if d = normalizeDomain(d); d != "" {
+			blocked[d] = struct{}{}
+		}
+	}
+	return emailValidator{blocked: blocked}
+}
+
+// validate runs the syntax check first, so a malformed address never reaches the blocklist.
+func (v emailValidator) validate(email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return domain.ErrEmailCannotEmpty
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return domain.ErrInvalidEmailFormat
+	}
+	if v.isBlocked(addr.Address) {
+		return domain.ErrDisposableEmail
+	}
+	return nil
+}
+
+func (v emailValidator) isBlocked(address string) bool {
+	if len(v.blocked) == 0 {
+		return false
+	}
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return false
+	}
+	// walk up the labels so "x.mailinator.com" matches a "mailinator.com" entry
+	for host := normalizeDomain(address[at+1:]); host != ""; {
+		if _, ok := v.blocked[host]; ok {
+			return true
+		}
+		dot := strings.Index(host, ".")
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return false
+}
+
+func normalizeDomain(d string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), ".")
+}
+
+// LoadEmailDomains reads one domain per line from path, skipping blank lines and # comments.
+func LoadEmailDomains(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open email domain list: %w", err)
+	}
+	defer f.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read email domain list: %w", err)
+	}
+	return domains, nil
+}
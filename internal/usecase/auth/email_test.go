@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+func TestEmailValidator(t *testing.T) {
+	v := newEmailValidator([]string{"Mailinator.com", " tempmail.dev "})
+
+	cases := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "allowed domain", email: "jane@example.com"},
+		{name: "blocked domain", email: "jane@mailinator.com", wantErr: domain.ErrDisposableEmail},
+		{name: "blocked domain any case", email: "Jane@MAILINATOR.COM", wantErr: domain.ErrDisposableEmail},
+		{name: "blocked subdomain", email: "jane@inbox.tempmail.dev", wantErr: domain.ErrDisposableEmail},
+		{name: "lookalike is allowed", email: "jane@notmailinator.com"},
+		{name: "malformed fails syntax first", email: "jane@@mailinator.com", wantErr: domain.ErrInvalidEmailFormat},
+		{name: "empty", email: "  ", wantErr: domain.ErrEmailCannotEmpty},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.validate(tc.email)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+
+	t.Run("disabled without a blocklist", func(t *testing.T) {
+		assert.NoError(t, newEmailValidator(nil).validate("jane@mailinator.com"))
+	})
+}
+
+func TestLoadEmailDomains(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# disposable providers\nmailinator.com\n\n  yopmail.com  \n"), 0o600))
+
+	domains, err := LoadEmailDomains(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mailinator.com", "yopmail.com"}, domains)
+
+	_, err = LoadEmailDomains(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
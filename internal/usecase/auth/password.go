@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// validatePassword checks password against the configured policy and reports every rule it breaks,
+// so the client can show them all at once. Lengths are counted in characters; the character classes
+// are ASCII, and anything that is not an ASCII letter or digit counts as special.
+func validatePassword(password string, policy config.PasswordPolicy) error {
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf("%w: "+format, append([]interface{}{domain.ErrInvalidPasswordFormat}, args...)...))
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		fail("must be at least %d characters", policy.MinLength)
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		fail("must be at most %d characters", policy.MaxLength)
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			hasLower = true
+		case r >= 'A' && r <= 'Z':
+			hasUpper = true
+		case r >= '0' && r <= '9':
+			hasDigit = true
+		default:
+			hasSpecial = true
+		}
+	}
+	if policy.RequireLower && !hasLower {
+		fail("must contain a lowercase letter")
+	}
+	if policy.RequireUpper && !hasUpper {
+		fail("must contain an uppercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		fail("must contain a digit")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		fail("must contain a special character")
+	}
+
+	return errors.Join(problems...)
+}
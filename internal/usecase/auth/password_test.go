@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+func TestValidatePassword(t *testing.T) {
+	strict := config.PasswordPolicy{MinLength: 8, RequireLower: true, RequireUpper: true, RequireDigit: true, RequireSpecial: true}
+	lengthOnly := config.PasswordPolicy{MinLength: 12}
+
+	cases := []struct {
+		name     string
+		password string
+		policy   config.PasswordPolicy
+		wantMsgs []string
+	}{
+		{name: "default policy accepts strong password", password: "Strong#Pass123", policy: strict},
+		{name: "too short", password: "Sh#1a", policy: strict, wantMsgs: []string{"at least 8 characters"}},
+		{name: "lowercase required", password: "STRONG#PASS123", policy: strict, wantMsgs: []string{"lowercase letter"}},
+		{name: "uppercase required", password: "strong#pass123", policy: strict, wantMsgs: []string{"uppercase letter"}},
+		{name: "digit required", password: "Strong#Password", policy: strict, wantMsgs: []string{"a digit"}},
+		{name: "special required", password: "StrongPass123", policy: strict, wantMsgs: []string{"special character"}},
+		{name: "every broken rule reported", password: "abc", policy: strict, wantMsgs: []string{"at least 8", "uppercase", "digit", "special"}},
+		{name: "classes off", password: "alllowercaseletters", policy: lengthOnly},
+		{name: "min length raised", password: "alllower", policy: lengthOnly, wantMsgs: []string{"at least 12 characters"}},
+		{name: "max length", password: strings.Repeat("a", 17), policy: config.PasswordPolicy{MinLength: 1, MaxLength: 16}, wantMsgs: []string{"at most 16 characters"}},
+		{name: "length counts characters", password: strings.Repeat("é", 8), policy: config.PasswordPolicy{MinLength: 8, MaxLength: 8}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePassword(tc.password, tc.policy)
+			if len(tc.wantMsgs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, domain.ErrInvalidPasswordFormat)
+			var appErr *domain.AppError
+			require.True(t, errors.As(err, &appErr), "status and code come from the wrapped AppError")
+
+			joined, ok := err.(interface{ Unwrap() []error })
+			require.True(t, ok)
+			require.Len(t, joined.Unwrap(), len(tc.wantMsgs))
+			for i, msg := range tc.wantMsgs {
+				assert.Contains(t, joined.Unwrap()[i].Error(), msg)
+			}
+		})
+	}
+}
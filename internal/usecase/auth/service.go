@@ -2,8 +2,8 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/mail"
 	"regexp"
 	"strings"
 	"time"
@@ -15,48 +15,117 @@ import (
 	"github.com/minilik/ecommerce/config"
 	"github.com/minilik/ecommerce/internal/domain"
 	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
 	hashpkg "github.com/minilik/ecommerce/pkg/hash"
 	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
 )
 
 var (
 	usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
-	// passwordRegex = regexp.MustCompile(`^(?=.*[a-z])(?=.*[A-Z])(?=.*\d)(?=.*[^a-zA-Z0-9]).{8,}$`)
 )
 
 type Service interface {
+	// Register creates a user account for anyone; it fails with domain.ErrRegistrationDisabled
+	// unless auth.registration_enabled is set.
 	Register(ctx context.Context, input RegisterInput) (*RegisterResponse, error)
+	// CreateUser creates an account on an admin's behalf, whether or not registration is open.
+	CreateUser(ctx context.Context, input CreateUserInput) (*UserSummary, error)
 	Login(ctx context.Context, input LoginInput) (*AuthResponse, error)
 	PromoteToAdmin(ctx context.Context, userID uuid.UUID) error
+	PromoteToAdminByEmail(ctx context.Context, email string) error
+	// SetRole gives a user any role of domain.Roles. Demoting the last admin fails with
+	// domain.ErrLastAdmin.
+	SetRole(ctx context.Context, userID uuid.UUID, role string) (*UserSummary, error)
+	UpdateEmail(ctx context.Context, userID uuid.UUID, input UpdateEmailInput) (*UpdateEmailResponse, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*UserSummary, error)
+	SendEmailVerification(ctx context.Context, userID uuid.UUID) (*EmailVerificationResponse, error)
+	VerifyEmail(ctx context.Context, token string) (*VerifyEmailResponse, error)
+	ForgotPassword(ctx context.Context, input ForgotPasswordInput) error
+	ResetPassword(ctx context.Context, input ResetPasswordInput) error
+	// TokenRevoked reports whether an access token issued at issuedAt for the user predates the
+	// user's last password change, or the user no longer exists.
+	TokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error)
 }
 
+const (
+	defaultEmailVerificationTTL = 24 * time.Hour
+	defaultPasswordResetTTL     = time.Hour
+)
+
 type service struct {
 	users   repository.UserRepository
+	resets  repository.PasswordResetRepository
+	uow     repository.UnitOfWork
 	hasher  hashpkg.Hasher
 	tokens  jwtpkg.Manager
+	events  domain.EventPublisher
 	cfg     *config.Config
+	emails  emailValidator
 	logger  *zap.Logger
 	nowFunc func() time.Time
 }
 
 func NewService(
 	users repository.UserRepository,
+	resets repository.PasswordResetRepository,
+	uow repository.UnitOfWork,
 	hasher hashpkg.Hasher,
 	tokens jwtpkg.Manager,
+	events domain.EventPublisher,
 	cfg *config.Config,
+	clk clock.Clock,
 	logger *zap.Logger,
 ) Service {
 	return &service{
 		users:   users,
+		resets:  resets,
+		uow:     uow,
 		hasher:  hasher,
 		tokens:  tokens,
+		events:  events,
 		cfg:     cfg,
+		emails:  newEmailValidator(cfg.Auth.BlockedEmailDomains),
 		logger:  logger,
-		nowFunc: time.Now,
+		nowFunc: clk.Now,
 	}
 }
 
 func (s *service) Register(ctx context.Context, input RegisterInput) (*RegisterResponse, error) {
+	if !s.cfg.Auth.RegistrationEnabled {
+		return nil, domain.ErrRegistrationDisabled
+	}
+	user, err := s.createUser(ctx, input, domain.RoleUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegisterResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     string(user.Role),
+	}, nil
+}
+
+func (s *service) CreateUser(ctx context.Context, input CreateUserInput) (*UserSummary, error) {
+	role := domain.RoleUser
+	if strings.TrimSpace(input.Role) != "" {
+		parsed, ok := domain.ParseRole(input.Role)
+		if !ok {
+			return nil, domain.ErrInvalidRole
+		}
+		role = parsed
+	}
+	user, err := s.createUser(ctx, RegisterInput{Username: input.Username, Email: input.Email, Password: input.Password}, role)
+	if err != nil {
+		return nil, err
+	}
+	return userSummary(user), nil
+}
+
+// createUser validates input as a registration and stores the account with role.
+func (s *service) createUser(ctx context.Context, input RegisterInput, role domain.Role) (*domain.User, error) {
+	input.Username = normalizeUsername(input.Username)
 	if err := s.validateRegisterInput(ctx, input); err != nil {
 		return nil, err
 	}
@@ -71,7 +140,7 @@ func (s *service) Register(ctx context.Context, input RegisterInput) (*RegisterR
 		Username:  strings.TrimSpace(input.Username),
 		Email:     strings.ToLower(strings.TrimSpace(input.Email)),
 		Password:  hashed,
-		Role:      domain.RoleUser,
+		Role:      role,
 		CreatedAt: s.nowFunc(),
 		UpdatedAt: s.nowFunc(),
 	}
@@ -79,13 +148,7 @@ func (s *service) Register(ctx context.Context, input RegisterInput) (*RegisterR
 	if err := s.users.Create(ctx, user); err != nil {
 		return nil, err
 	}
-
-	return &RegisterResponse{
-		UserID:   user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Role:     string(user.Role),
-	}, nil
+	return user, nil
 }
 
 func (s *service) Login(ctx context.Context, input LoginInput) (*AuthResponse, error) {
@@ -122,6 +185,280 @@ func (s *service) PromoteToAdmin(ctx context.Context, userID uuid.UUID) error {
 	return s.users.UpdateRole(ctx, userID, domain.RoleAdmin)
 }
 
+func (s *service) PromoteToAdminByEmail(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return domain.ErrEmailCannotEmpty
+	}
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil
+	}
+	return s.users.UpdateRole(ctx, user.ID, domain.RoleAdmin)
+}
+
+func (s *service) SetRole(ctx context.Context, userID uuid.UUID, role string) (*UserSummary, error) {
+	newRole, ok := domain.ParseRole(role)
+	if !ok {
+		return nil, domain.ErrInvalidRole
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.Role != newRole {
+		if err := s.users.SetRole(ctx, userID, newRole); err != nil {
+			return nil, err
+		}
+		user.Role = newRole
+		user.UpdatedAt = s.nowFunc()
+	}
+	return userSummary(user), nil
+}
+
+func (s *service) GetUser(ctx context.Context, userID uuid.UUID) (*UserSummary, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return userSummary(user), nil
+}
+
+func userSummary(user *domain.User) *UserSummary {
+	return &UserSummary{
+		UserID:        user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		Role:          string(user.Role),
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// SendEmailVerification signs a verification token for the user's current email and publishes it
+// for the notifier to deliver. Unlike order events, a failed publish fails the call, because
+// delivering the token is the whole point.
+func (s *service) SendEmailVerification(ctx context.Context, userID uuid.UUID) (*EmailVerificationResponse, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if user.EmailVerified {
+		return nil, domain.ErrEmailAlreadyVerified
+	}
+
+	ttl := s.cfg.Auth.EmailVerificationTTL
+	if ttl <= 0 {
+		ttl = defaultEmailVerificationTTL
+	}
+	token, err := s.tokens.GenerateEmailVerificationToken(user.ID, user.Email, ttl, s.cfg.JWT.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("generate verification token: %w", err)
+	}
+	expiresAt := s.nowFunc().Add(ttl)
+
+	if s.events == nil {
+		return nil, errors.New("no notifier configured for verification emails")
+	}
+	if err := s.events.Publish(ctx, domain.EmailVerificationRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, fmt.Errorf("send verification email: %w", err)
+	}
+
+	return &EmailVerificationResponse{Email: user.Email, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyEmail marks the token's email as verified. A token stops working once the user changes
+// their email, because it names the address it was sent to.
+func (s *service) VerifyEmail(ctx context.Context, token string) (*VerifyEmailResponse, error) {
+	claims, err := s.tokens.ParseEmailVerificationToken(token)
+	if err != nil {
+		s.logger.Debug("rejected email verification token", zap.Error(err))
+		return nil, domain.ErrInvalidVerification
+	}
+
+	user, err := s.users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Email != claims.Email {
+		return nil, domain.ErrInvalidVerification
+	}
+
+	if !user.EmailVerified {
+		if err := s.users.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			return nil, err
+		}
+	}
+	return &VerifyEmailResponse{UserID: user.ID, Email: user.Email, EmailVerified: true}, nil
+}
+
+// UpdateEmail changes the user's email after confirming their current password.
+func (s *service) UpdateEmail(ctx context.Context, userID uuid.UUID, input UpdateEmailInput) (*UpdateEmailResponse, error) {
+	if err := s.emails.validate(input.Email); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if err := s.hasher.Compare(input.CurrentPassword, user.Password); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if email == user.Email {
+		return &UpdateEmailResponse{UserID: user.ID, Email: user.Email}, nil
+	}
+	if existing, err := s.users.FindByEmail(ctx, email); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, domain.ErrEmailAlreadyExists
+	}
+
+	if err := s.users.UpdateEmail(ctx, user.ID, email); err != nil {
+		return nil, err
+	}
+	return &UpdateEmailResponse{UserID: user.ID, Email: email}, nil
+}
+
+// ForgotPassword sends a reset link when email belongs to a user. It succeeds whether or not the
+// account exists, and failures after the lookup are only logged, so callers cannot probe which
+// emails are registered.
+func (s *service) ForgotPassword(ctx context.Context, input ForgotPasswordInput) error {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if email == "" {
+		return domain.ErrEmailCannotEmpty
+	}
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		s.logger.Debug("password reset requested for unknown email")
+		return nil
+	}
+
+	if err := s.sendPasswordReset(ctx, user); err != nil {
+		s.logger.Error("send password reset failed", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+	return nil
+}
+
+func (s *service) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	ttl := s.cfg.Auth.PasswordResetTTL
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
+	reset := &domain.PasswordReset{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		ExpiresAt: s.nowFunc().Add(ttl),
+		CreatedAt: s.nowFunc(),
+	}
+	if err := s.resets.Create(ctx, reset); err != nil {
+		return err
+	}
+	token, err := s.tokens.GeneratePasswordResetToken(user.ID, reset.ID, ttl, s.cfg.JWT.Issuer)
+	if err != nil {
+		return fmt.Errorf("generate reset token: %w", err)
+	}
+	if s.events == nil {
+		return errors.New("no notifier configured for password reset emails")
+	}
+	return s.events.Publish(ctx, domain.PasswordResetRequestedEvent{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: reset.ExpiresAt,
+	})
+}
+
+// ResetPassword redeems a reset token. Consuming it, storing the new password and revoking the
+// user's other outstanding resets commit together, so a failure leaves the token usable; the
+// password change also revokes every access token issued before it.
+func (s *service) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
+	claims, err := s.tokens.ParsePasswordResetToken(input.Token)
+	if err != nil {
+		s.logger.Debug("rejected password reset token", zap.Error(err))
+		return domain.ErrInvalidResetToken
+	}
+	if err := validatePassword(input.NewPassword, s.cfg.Auth.PasswordPolicy); err != nil {
+		return err
+	}
+
+	reset, err := s.resets.GetByID(ctx, claims.ResetID)
+	if err != nil {
+		return err
+	}
+	if reset == nil || reset.UserID != claims.UserID || !s.nowFunc().Before(reset.ExpiresAt) {
+		return domain.ErrInvalidResetToken
+	}
+	if reset.UsedAt != nil {
+		return domain.ErrResetTokenUsed
+	}
+
+	hashed, err := s.hasher.Hash(input.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	now := s.nowFunc()
+	err = s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		if err := repos.PasswordResets().Consume(ctx, reset.ID, now); err != nil {
+			return err
+		}
+		if err := repos.Users().UpdatePassword(ctx, reset.UserID, hashed); err != nil {
+			return err
+		}
+		return repos.PasswordResets().InvalidateForUser(ctx, reset.UserID, now)
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.Info("password reset", zap.String("user_id", reset.UserID.String()))
+	return nil
+}
+
+// TokenRevoked compares at whole seconds because the iat claim has no finer precision, so a token
+// issued in the same second as the change is still accepted.
+func (s *service) TokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return true, nil
+	}
+	if user.PasswordChangedAt == nil {
+		return false, nil
+	}
+	return issuedAt.Before(user.PasswordChangedAt.Truncate(time.Second)), nil
+}
+
 func (s *service) issueToken(user *domain.User) (*AuthResponse, error) {
 	ttl := s.cfg.JWT.AccessTokenTTL
 	token, err := s.tokens.GenerateAccessToken(user.ID, user.Username, string(user.Role), ttl, s.cfg.JWT.Issuer)
@@ -142,16 +479,16 @@ func (s *service) issueToken(user *domain.User) (*AuthResponse, error) {
 }
 
 func (s *service) validateRegisterInput(ctx context.Context, input RegisterInput) error {
-	if strings.TrimSpace(input.Username) == "" || !usernameRegex.MatchString(input.Username) {
-		return domain.ErrInvalidUsernameFormat
+	if err := validateUsername(input.Username, s.cfg.Auth); err != nil {
+		return err
 	}
 
-	if err := validateEmail(input.Email); err != nil {
+	if err := s.emails.validate(input.Email); err != nil {
 		return err
 	}
 
-	if !isValidPassword(input.Password) {
-		return domain.ErrInvalidPasswordFormat
+	if err := validatePassword(input.Password, s.cfg.Auth.PasswordPolicy); err != nil {
+		return err
 	}
 
 	if existing, err := s.users.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email))); err == nil && existing != nil {
@@ -168,29 +505,3 @@ func (s *service) validateRegisterInput(ctx context.Context, input RegisterInput
 
 	return nil
 }
-
-func validateEmail(email string) error {
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return domain.ErrEmailCannotEmpty
-	}
-	if _, err := mail.ParseAddress(email); err != nil {
-		return domain.ErrInvalidEmailFormat
-	}
-	return nil
-}
-
-// all registrations become regular users; admin seeding controls admin creation.
-
-func isValidPassword(password string) bool {
-	if len(password) < 8 {
-		return false
-	}
-
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(password)
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	hasDigit := regexp.MustCompile(`[0-9]`).MatchString(password)
-	hasSpecial := regexp.MustCompile(`[^a-zA-Z0-9]`).MatchString(password)
-
-	return hasLower && hasUpper && hasDigit && hasSpecial
-}
@@ -0,0 +1,516 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+	hashpkg "github.com/minilik/ecommerce/pkg/hash"
+	jwtpkg "github.com/minilik/ecommerce/pkg/jwt"
+)
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users             map[uuid.UUID]*domain.User
+	updatePasswordErr error
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	r.users[id].Email = email
+	r.users[id].EmailVerified = false
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	if u := r.users[id]; u != nil && u.Email == email {
+		u.EmailVerified = true
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hashed string) error {
+	if r.updatePasswordErr != nil {
+		return r.updatePasswordErr
+	}
+	now := time.Now()
+	r.users[id].Password = hashed
+	r.users[id].PasswordChangedAt = &now
+	return nil
+}
+
+func (r *fakeUserRepo) SetRole(ctx context.Context, id uuid.UUID, role domain.Role) error {
+	if r.users[id].Role == domain.RoleAdmin && role != domain.RoleAdmin {
+		admins := 0
+		for _, u := range r.users {
+			if u.Role == domain.RoleAdmin {
+				admins++
+			}
+		}
+		if admins <= 1 {
+			return domain.ErrLastAdmin
+		}
+	}
+	r.users[id].Role = role
+	return nil
+}
+
+type fakeResetRepo struct {
+	resets map[uuid.UUID]domain.PasswordReset
+}
+
+func (r *fakeResetRepo) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	r.resets[reset.ID] = *reset
+	return nil
+}
+
+func (r *fakeResetRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.PasswordReset, error) {
+	reset, ok := r.resets[id]
+	if !ok {
+		return nil, nil
+	}
+	return &reset, nil
+}
+
+func (r *fakeResetRepo) Consume(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	reset := r.resets[id]
+	if reset.UsedAt != nil {
+		return domain.ErrResetTokenUsed
+	}
+	reset.UsedAt = &usedAt
+	r.resets[id] = reset
+	return nil
+}
+
+func (r *fakeResetRepo) InvalidateForUser(ctx context.Context, userID uuid.UUID, usedAt time.Time) error {
+	for id, reset := range r.resets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &usedAt
+			r.resets[id] = reset
+		}
+	}
+	return nil
+}
+
+// fakeUnitOfWork runs the callback against the fake repositories and, like a rolled back
+// transaction, restores the resets when it fails.
+type fakeUnitOfWork struct {
+	repository.RepositoryProvider
+	users  *fakeUserRepo
+	resets *fakeResetRepo
+}
+
+func (u *fakeUnitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
+	saved := make(map[uuid.UUID]domain.PasswordReset, len(u.resets.resets))
+	for id, reset := range u.resets.resets {
+		saved[id] = reset
+	}
+	if err := fn(u); err != nil {
+		u.resets.resets = saved
+		return err
+	}
+	return nil
+}
+
+func (u *fakeUnitOfWork) Users() repository.UserRepository { return u.users }
+func (u *fakeUnitOfWork) PasswordResets() repository.PasswordResetRepository {
+	return u.resets
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestService_UpdateEmail(t *testing.T) {
+	hasher := hashpkg.NewBcryptHasher(bcrypt.MinCost)
+	hashed, err := hasher.Hash("Strong#Pass123")
+	require.NoError(t, err)
+
+	alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+	bob := &domain.User{ID: uuid.New(), Email: "bob@example.com", Password: hashed}
+	repo := &fakeUserRepo{users: map[uuid.UUID]*domain.User{alice.ID: alice, bob.ID: bob}}
+	svc := NewService(repo, nil, nil, hasher, nil, nil, &config.Config{}, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := svc.UpdateEmail(ctx, alice.ID, UpdateEmailInput{Email: "new@example.com", CurrentPassword: "Wrong#Pass123"})
+		require.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		assert.Equal(t, "alice@example.com", alice.Email)
+	})
+
+	t.Run("email taken by another user", func(t *testing.T) {
+		_, err := svc.UpdateEmail(ctx, alice.ID, UpdateEmailInput{Email: "Bob@Example.com", CurrentPassword: "Strong#Pass123"})
+		require.ErrorIs(t, err, domain.ErrEmailAlreadyExists)
+		assert.Equal(t, "alice@example.com", alice.Email)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		_, err := svc.UpdateEmail(ctx, alice.ID, UpdateEmailInput{Email: "not-an-email", CurrentPassword: "Strong#Pass123"})
+		require.ErrorIs(t, err, domain.ErrInvalidEmailFormat)
+	})
+
+	t.Run("success lowercases the new email", func(t *testing.T) {
+		res, err := svc.UpdateEmail(ctx, alice.ID, UpdateEmailInput{Email: "  Alice.New@Example.com ", CurrentPassword: "Strong#Pass123"})
+		require.NoError(t, err)
+		assert.Equal(t, "alice.new@example.com", res.Email)
+		assert.Equal(t, alice.ID, res.UserID)
+		assert.Equal(t, "alice.new@example.com", alice.Email)
+	})
+}
+
+func TestService_EmailVerification(t *testing.T) {
+	hasher := hashpkg.NewBcryptHasher(bcrypt.MinCost)
+	hashed, err := hasher.Hash("Strong#Pass123")
+	require.NoError(t, err)
+	tokens, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+
+	newService := func(users ...*domain.User) (Service, *fakeUserRepo, *recordingPublisher) {
+		repo := &fakeUserRepo{users: map[uuid.UUID]*domain.User{}}
+		for _, u := range users {
+			repo.users[u.ID] = u
+		}
+		publisher := &recordingPublisher{}
+		cfg := &config.Config{JWT: config.JWTConfig{Issuer: "ecommerce-api"}, Auth: config.AuthConfig{EmailVerificationTTL: time.Hour}}
+		frozen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		return NewService(repo, nil, nil, hasher, tokens, publisher, cfg, clock.Fixed(frozen), zap.NewNop()), repo, publisher
+	}
+	sentToken := func(t *testing.T, publisher *recordingPublisher) string {
+		require.Len(t, publisher.events, 1)
+		event, ok := publisher.events[0].(domain.EmailVerificationRequestedEvent)
+		require.True(t, ok)
+		return event.Token
+	}
+	ctx := context.Background()
+
+	t.Run("send issues a token for the current email", func(t *testing.T) {
+		alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+		svc, _, publisher := newService(alice)
+
+		res, err := svc.SendEmailVerification(ctx, alice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", res.Email)
+		assert.Equal(t, time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC), res.ExpiresAt)
+
+		event := publisher.events[0].(domain.EmailVerificationRequestedEvent)
+		assert.Equal(t, alice.ID, event.UserID)
+		assert.Equal(t, "alice@example.com", event.Email)
+		claims, err := tokens.ParseEmailVerificationToken(event.Token)
+		require.NoError(t, err)
+		assert.Equal(t, alice.ID, claims.UserID)
+		_, err = tokens.ParseToken(event.Token)
+		assert.Error(t, err, "a verification token is not an access token")
+	})
+
+	t.Run("verify marks the email verified", func(t *testing.T) {
+		alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+		svc, _, publisher := newService(alice)
+		_, err := svc.SendEmailVerification(ctx, alice.ID)
+		require.NoError(t, err)
+
+		res, err := svc.VerifyEmail(ctx, sentToken(t, publisher))
+		require.NoError(t, err)
+		assert.True(t, res.EmailVerified)
+		assert.True(t, alice.EmailVerified)
+
+		_, err = svc.SendEmailVerification(ctx, alice.ID)
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyVerified)
+	})
+
+	t.Run("expired, tampered and misused tokens are rejected", func(t *testing.T) {
+		alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+		svc, _, publisher := newService(alice)
+
+		expired, err := tokens.GenerateEmailVerificationToken(alice.ID, alice.Email, -time.Minute, "ecommerce-api")
+		require.NoError(t, err)
+		_, err = svc.VerifyEmail(ctx, expired)
+		assert.ErrorIs(t, err, domain.ErrInvalidVerification)
+
+		_, err = svc.SendEmailVerification(ctx, alice.ID)
+		require.NoError(t, err)
+		token := sentToken(t, publisher)
+		tampered := token[:len(token)-2] + "xx"
+		if tampered == token {
+			tampered = token[:len(token)-2] + "yy"
+		}
+		_, err = svc.VerifyEmail(ctx, tampered)
+		assert.ErrorIs(t, err, domain.ErrInvalidVerification)
+
+		access, err := tokens.GenerateAccessToken(alice.ID, "alice", "user", time.Hour, "ecommerce-api")
+		require.NoError(t, err)
+		_, err = svc.VerifyEmail(ctx, access)
+		assert.ErrorIs(t, err, domain.ErrInvalidVerification)
+
+		assert.False(t, alice.EmailVerified)
+	})
+
+	t.Run("changing the email invalidates earlier links", func(t *testing.T) {
+		alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+		svc, _, publisher := newService(alice)
+		_, err := svc.SendEmailVerification(ctx, alice.ID)
+		require.NoError(t, err)
+
+		_, err = svc.UpdateEmail(ctx, alice.ID, UpdateEmailInput{Email: "alice.new@example.com", CurrentPassword: "Strong#Pass123"})
+		require.NoError(t, err)
+
+		_, err = svc.VerifyEmail(ctx, sentToken(t, publisher))
+		assert.ErrorIs(t, err, domain.ErrInvalidVerification)
+		assert.False(t, alice.EmailVerified)
+	})
+}
+
+func TestService_PasswordReset(t *testing.T) {
+	hasher := hashpkg.NewBcryptHasher(bcrypt.MinCost)
+	hashed, err := hasher.Hash("Strong#Pass123")
+	require.NoError(t, err)
+	tokens, err := jwtpkg.NewManager("test-secret", "")
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Issuer: "ecommerce-api"},
+		Auth: config.AuthConfig{
+			PasswordResetTTL: time.Hour,
+			PasswordPolicy:   config.PasswordPolicy{MinLength: 8, RequireDigit: true},
+		},
+	}
+	ctx := context.Background()
+
+	type fixture struct {
+		svc       Service
+		alice     *domain.User
+		users     *fakeUserRepo
+		resets    *fakeResetRepo
+		publisher *recordingPublisher
+	}
+	setup := func(clk clock.Clock) fixture {
+		alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Password: hashed}
+		users := &fakeUserRepo{users: map[uuid.UUID]*domain.User{alice.ID: alice}}
+		resets := &fakeResetRepo{resets: map[uuid.UUID]domain.PasswordReset{}}
+		publisher := &recordingPublisher{}
+		uow := &fakeUnitOfWork{users: users, resets: resets}
+		svc := NewService(users, resets, uow, hasher, tokens, publisher, cfg, clk, zap.NewNop())
+		return fixture{svc: svc, alice: alice, users: users, resets: resets, publisher: publisher}
+	}
+	requestReset := func(t *testing.T, f fixture) string {
+		require.NoError(t, f.svc.ForgotPassword(ctx, ForgotPasswordInput{Email: " Alice@Example.com "}))
+		require.NotEmpty(t, f.publisher.events)
+		event, ok := f.publisher.events[len(f.publisher.events)-1].(domain.PasswordResetRequestedEvent)
+		require.True(t, ok)
+		assert.Equal(t, f.alice.ID, event.UserID)
+		return event.Token
+	}
+
+	t.Run("unknown email gets the same answer and no email", func(t *testing.T) {
+		f := setup(clock.Real())
+		assert.NoError(t, f.svc.ForgotPassword(ctx, ForgotPasswordInput{Email: "nobody@example.com"}))
+		assert.Empty(t, f.publisher.events)
+		assert.Empty(t, f.resets.resets)
+	})
+
+	t.Run("valid reset changes the password and revokes other links", func(t *testing.T) {
+		f := setup(clock.Real())
+		first := requestReset(t, f)
+		second := requestReset(t, f)
+
+		require.NoError(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: second, NewPassword: "brand-new-1"}))
+		assert.NoError(t, hasher.Compare("brand-new-1", f.alice.Password))
+
+		err := f.svc.ResetPassword(ctx, ResetPasswordInput{Token: first, NewPassword: "another-one-2"})
+		assert.ErrorIs(t, err, domain.ErrResetTokenUsed)
+		assert.NoError(t, hasher.Compare("brand-new-1", f.alice.Password))
+	})
+
+	t.Run("reused token is rejected", func(t *testing.T) {
+		f := setup(clock.Real())
+		token := requestReset(t, f)
+		require.NoError(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-1"}))
+
+		err := f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-2"})
+		assert.ErrorIs(t, err, domain.ErrResetTokenUsed)
+		assert.NoError(t, hasher.Compare("brand-new-1", f.alice.Password))
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		// the stored record expires by the service clock even while the JWT is still valid
+		past := setup(clock.Fixed(time.Now().Add(-2 * time.Hour)))
+		token := requestReset(t, past)
+		laterUsers := &fakeUserRepo{users: map[uuid.UUID]*domain.User{past.alice.ID: past.alice}}
+		later := NewService(laterUsers, past.resets, &fakeUnitOfWork{users: laterUsers, resets: past.resets}, hasher, tokens, nil, cfg, clock.Real(), zap.NewNop())
+		err := later.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-1"})
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+
+		// and a token whose own expiry has passed never reaches the store
+		f := setup(clock.Real())
+		expired, err := tokens.GeneratePasswordResetToken(f.alice.ID, uuid.New(), -time.Minute, "ecommerce-api")
+		require.NoError(t, err)
+		err = f.svc.ResetPassword(ctx, ResetPasswordInput{Token: expired, NewPassword: "brand-new-1"})
+		assert.ErrorIs(t, err, domain.ErrInvalidResetToken)
+		assert.NoError(t, hasher.Compare("Strong#Pass123", f.alice.Password))
+	})
+
+	t.Run("new password must satisfy the policy", func(t *testing.T) {
+		f := setup(clock.Real())
+		token := requestReset(t, f)
+		err := f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "short"})
+		assert.ErrorIs(t, err, domain.ErrInvalidPasswordFormat)
+
+		require.NoError(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "long-enough-1"}), "a rejected password does not burn the token")
+	})
+
+	t.Run("failed password update leaves the token usable", func(t *testing.T) {
+		f := setup(clock.Real())
+		token := requestReset(t, f)
+		f.users.updatePasswordErr = errors.New("connection reset")
+		assert.Error(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-1"}))
+
+		f.users.updatePasswordErr = nil
+		require.NoError(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-1"}))
+		assert.NoError(t, hasher.Compare("brand-new-1", f.alice.Password))
+	})
+
+	t.Run("reset revokes earlier access tokens", func(t *testing.T) {
+		f := setup(clock.Real())
+		before := time.Now().Add(-time.Minute)
+		revoked, err := f.svc.TokenRevoked(ctx, f.alice.ID, before)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		token := requestReset(t, f)
+		require.NoError(t, f.svc.ResetPassword(ctx, ResetPasswordInput{Token: token, NewPassword: "brand-new-1"}))
+
+		revoked, err = f.svc.TokenRevoked(ctx, f.alice.ID, before)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		revoked, err = f.svc.TokenRevoked(ctx, f.alice.ID, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.False(t, revoked, "tokens issued after the reset stay valid")
+		revoked, err = f.svc.TokenRevoked(ctx, uuid.New(), time.Now())
+		require.NoError(t, err)
+		assert.True(t, revoked, "tokens of unknown users are revoked")
+	})
+}
+
+func TestService_SetRole(t *testing.T) {
+	alice := &domain.User{ID: uuid.New(), Email: "alice@example.com", Role: domain.RoleAdmin}
+	bob := &domain.User{ID: uuid.New(), Email: "bob@example.com", Role: domain.RoleUser}
+	repo := &fakeUserRepo{users: map[uuid.UUID]*domain.User{alice.ID: alice, bob.ID: bob}}
+	svc := NewService(repo, nil, nil, nil, nil, nil, &config.Config{}, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("valid role", func(t *testing.T) {
+		summary, err := svc.SetRole(ctx, bob.ID, " Admin ")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", summary.Role)
+		assert.Equal(t, domain.RoleAdmin, bob.Role)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		_, err := svc.SetRole(ctx, bob.ID, "superuser")
+		require.ErrorIs(t, err, domain.ErrInvalidRole)
+		assert.Equal(t, domain.RoleAdmin, bob.Role)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := svc.SetRole(ctx, uuid.New(), "user")
+		require.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("last admin guard", func(t *testing.T) {
+		_, err := svc.SetRole(ctx, alice.ID, "user")
+		require.NoError(t, err, "bob is still an admin")
+
+		_, err = svc.SetRole(ctx, bob.ID, "user")
+		require.ErrorIs(t, err, domain.ErrLastAdmin)
+		assert.Equal(t, domain.RoleAdmin, bob.Role)
+	})
+}
+
+func TestService_RegistrationToggle(t *testing.T) {
+	newService := func(enabled bool) (Service, *fakeUserRepo) {
+		repo := &fakeUserRepo{users: map[uuid.UUID]*domain.User{}}
+		cfg := &config.Config{Auth: config.AuthConfig{
+			UsernameMinLength:   3,
+			UsernameMaxLength:   32,
+			PasswordPolicy:      config.PasswordPolicy{MinLength: 8},
+			RegistrationEnabled: enabled,
+		}}
+		return NewService(repo, nil, nil, hashpkg.NewBcryptHasher(bcrypt.MinCost), nil, nil, cfg, clock.Real(), zap.NewNop()), repo
+	}
+	ctx := context.Background()
+	register := RegisterInput{Username: "jane", Email: "jane@example.com", Password: "secret-pass"}
+
+	t.Run("open", func(t *testing.T) {
+		svc, repo := newService(true)
+		res, err := svc.Register(ctx, register)
+		require.NoError(t, err)
+		assert.Equal(t, "user", res.Role)
+		assert.Len(t, repo.users, 1)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		svc, repo := newService(false)
+		_, err := svc.Register(ctx, register)
+		require.ErrorIs(t, err, domain.ErrRegistrationDisabled)
+		assert.Empty(t, repo.users)
+	})
+
+	t.Run("admin creates the account while closed", func(t *testing.T) {
+		svc, repo := newService(false)
+		summary, err := svc.CreateUser(ctx, CreateUserInput{Username: "jane", Email: " Jane@Example.com ", Password: "secret-pass"})
+		require.NoError(t, err)
+		assert.Equal(t, "user", summary.Role)
+		assert.Equal(t, "jane@example.com", summary.Email)
+		require.Contains(t, repo.users, summary.UserID)
+		assert.NotEqual(t, "secret-pass", repo.users[summary.UserID].Password, "the password is stored hashed")
+
+		staff, err := svc.CreateUser(ctx, CreateUserInput{Username: "sam", Email: "sam@example.com", Password: "secret-pass", Role: "Admin"})
+		require.NoError(t, err)
+		assert.Equal(t, "admin", staff.Role)
+
+		_, err = svc.CreateUser(ctx, CreateUserInput{Username: "max", Email: "max@example.com", Password: "secret-pass", Role: "owner"})
+		require.ErrorIs(t, err, domain.ErrInvalidRole)
+		_, err = svc.CreateUser(ctx, CreateUserInput{Username: "jane2", Email: "jane@example.com", Password: "secret-pass"})
+		require.ErrorIs(t, err, domain.ErrEmailAlreadyExists)
+	})
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// normalizeUsername trims and NFC-normalizes a username so composed and decomposed
+// spellings of the same name ("é" vs "é") are stored and looked up identically.
+func normalizeUsername(username string) string {
+	return norm.NFC.String(strings.TrimSpace(username))
+}
+
+// validateUsername applies the configured length bounds (in characters) and character set.
+// Spaces, punctuation and control characters are always rejected.
+func validateUsername(username string, cfg config.AuthConfig) error {
+	if username == "" {
+		return domain.ErrInvalidUsernameFormat
+	}
+	length := utf8.RuneCountInString(username)
+	if cfg.UsernameMinLength > 0 && length < cfg.UsernameMinLength {
+		return fmt.Errorf("%w: must be at least %d characters", domain.ErrInvalidUsernameFormat, cfg.UsernameMinLength)
+	}
+	if cfg.UsernameMaxLength > 0 && length > cfg.UsernameMaxLength {
+		return fmt.Errorf("%w: must be at most %d characters", domain.ErrInvalidUsernameFormat, cfg.UsernameMaxLength)
+	}
+
+	if !cfg.UsernameUnicode {
+		if !usernameRegex.MatchString(username) {
+			return domain.ErrInvalidUsernameFormat
+		}
+		return nil
+	}
+	for _, r := range username {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return domain.ErrInvalidUsernameFormat
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+func TestValidateUsername(t *testing.T) {
+	unicodeCfg := config.AuthConfig{UsernameMinLength: 3, UsernameMaxLength: 12, UsernameUnicode: true}
+	asciiCfg := config.AuthConfig{UsernameMinLength: 3, UsernameMaxLength: 12}
+
+	cases := []struct {
+		name     string
+		username string
+		cfg      config.AuthConfig
+		wantErr  bool
+	}{
+		{name: "accented unicode", username: "JoséMüller", cfg: unicodeCfg},
+		{name: "non-latin unicode", username: "Алексей2", cfg: unicodeCfg},
+		{name: "accented rejected in ascii mode", username: "JoséMüller", cfg: asciiCfg, wantErr: true},
+		{name: "at max length counts runes", username: strings.Repeat("é", 12), cfg: unicodeCfg},
+		{name: "over max length", username: strings.Repeat("a", 13), cfg: unicodeCfg, wantErr: true},
+		{name: "under min length", username: "ab", cfg: unicodeCfg, wantErr: true},
+		{name: "contains space", username: "José Müller", cfg: unicodeCfg, wantErr: true},
+		{name: "contains control character", username: "jose\u0007x", cfg: unicodeCfg, wantErr: true},
+		{name: "contains symbol", username: "jose!", cfg: unicodeCfg, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateUsername(normalizeUsername(tc.username), tc.cfg)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidUsernameFormat)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizeUsername_NFC(t *testing.T) {
+	composed := "Jos\u00e9"
+	decomposed := "Jose\u0301"
+
+	assert.NotEqual(t, composed, decomposed)
+	assert.Equal(t, normalizeUsername(composed), normalizeUsername(decomposed))
+	// the decomposed form carries a combining mark, which only passes once composed
+	assert.NoError(t, validateUsername(normalizeUsername(decomposed), config.AuthConfig{UsernameUnicode: true}))
+}
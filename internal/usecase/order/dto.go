@@ -1,6 +1,10 @@
 package order
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
 
 type OrderItemInput struct {
 	ProductID uuid.UUID `json:"productId"`
@@ -10,4 +14,23 @@ type OrderItemInput struct {
 type CreateOrderInput struct {
 	Description string           `json:"description"`
 	Items       []OrderItemInput `json:"items"`
+	// ExpectedTotal is the total the client displayed; when set, the order is rejected if prices changed since.
+	ExpectedTotal *float64 `json:"expectedTotal,omitempty"`
+	// ReservationIDs are the caller's active reservations to spend on this order; reserved units
+	// are counted towards the matching items instead of being taken from stock again.
+	ReservationIDs []uuid.UUID `json:"reservationIds,omitempty"`
+}
+
+// ReorderResult is the order created by Reorder and the items whose unit price differs from the
+// original order.
+type ReorderResult struct {
+	Order        *domain.Order `json:"order"`
+	PriceChanges []PriceChange `json:"priceChanges"`
+}
+
+type PriceChange struct {
+	ProductID     uuid.UUID `json:"productId"`
+	ProductName   string    `json:"productName"`
+	PreviousPrice float64   `json:"previousPrice"`
+	CurrentPrice  float64   `json:"currentPrice"`
 }
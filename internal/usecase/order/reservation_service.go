@@ -0,0 +1,176 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// ReservationService holds stock for a shopper while they check out. Reserved units come out of
+// the product's stock straight away, so every other stock check (orders, other reservations) sees
+// only what is still available; Release and expiry put them back, and an order consumes them.
+type ReservationService interface {
+	Reserve(ctx context.Context, userID, productID uuid.UUID, qty int, ttl time.Duration) (*domain.Reservation, error)
+	Release(ctx context.Context, userID, id uuid.UUID) error
+	ReleaseExpired(ctx context.Context) (int, error)
+}
+
+type reservationService struct {
+	uow    repository.UnitOfWork
+	cfg    config.OrderConfig
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+func NewReservationService(uow repository.UnitOfWork, cfg config.OrderConfig, clk clock.Clock, logger *zap.Logger) ReservationService {
+	return &reservationService{
+		uow:    uow,
+		cfg:    cfg,
+		logger: logger,
+		now:    clk.Now,
+	}
+}
+
+// Reserve holds qty units of the product for ttl (the configured ReservationTTL when zero).
+func (s *reservationService) Reserve(ctx context.Context, userID, productID uuid.UUID, qty int, ttl time.Duration) (*domain.Reservation, error) {
+	if productID == uuid.Nil {
+		return nil, domain.NewValidationError("product id is required")
+	}
+	if qty <= 0 {
+		return nil, domain.NewValidationError("quantity must be greater than zero")
+	}
+	if ttl <= 0 {
+		ttl = s.cfg.ReservationTTL
+	}
+	if ttl <= 0 {
+		return nil, domain.NewValidationError("reservation ttl must be greater than zero")
+	}
+	if max := s.cfg.ReservationMaxTTL; max > 0 && ttl > max {
+		return nil, domain.NewValidationError("reservation ttl must be at most %s", max)
+	}
+
+	reservation := &domain.Reservation{
+		ID:        uuid.New(),
+		ProductID: productID,
+		UserID:    userID,
+		Quantity:  qty,
+		Status:    domain.ReservationStatusActive,
+		ExpiresAt: s.now().Add(ttl),
+		CreatedAt: s.now(),
+		UpdatedAt: s.now(),
+	}
+	err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		// the decrement checks and takes the stock in one statement, so two shoppers cannot both
+		// reserve the last units
+		short, err := repos.Products().DecrementStockBatch(ctx, map[uuid.UUID]int{productID: qty})
+		if err != nil {
+			return err
+		}
+		if len(short) > 0 {
+			product, err := repos.Products().GetByID(ctx, productID)
+			if err != nil {
+				// the repository reports a missing product as ErrProductNotFound; other failures pass through
+				return err
+			}
+			return &domain.InsufficientStockError{Shortages: []domain.StockShortage{{
+				ProductID:   product.ID,
+				ProductName: product.Name,
+				Requested:   qty,
+				Available:   product.Stock,
+			}}}
+		}
+		return repos.Reservations().Create(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Release gives an active reservation's units back to stock. Only the user who made it may release it.
+func (s *reservationService) Release(ctx context.Context, userID, id uuid.UUID) error {
+	return s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		reservation, err := repos.Reservations().GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if reservation.UserID != userID {
+			return domain.ErrReservationNotFound
+		}
+		if reservation.Status != domain.ReservationStatusActive {
+			return domain.ErrReservationInactive
+		}
+		return s.release(ctx, repos, *reservation)
+	})
+}
+
+// ReleaseExpired returns the stock of every active reservation past its expiry. Reservations are
+// processed in batches, each in its own transaction, like order expiry.
+func (s *reservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	batchSize := s.cfg.ExpireBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpireBatchSize
+	}
+	cutoff := s.now()
+
+	released := 0
+	for {
+		var processed int
+		err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+			reservations, err := repos.Reservations().ListExpired(ctx, cutoff, batchSize)
+			if err != nil {
+				return err
+			}
+			processed = len(reservations)
+			for _, reservation := range reservations {
+				if err := s.release(ctx, repos, reservation); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return released, err
+		}
+		released += processed
+		if processed < batchSize {
+			break
+		}
+	}
+
+	if released > 0 {
+		s.logger.Info("released expired reservations", zap.Int("count", released))
+	}
+	return released, nil
+}
+
+func (s *reservationService) release(ctx context.Context, repos repository.RepositoryProvider, reservation domain.Reservation) error {
+	if err := repos.Products().IncrementStock(ctx, reservation.ProductID, reservation.Quantity); err != nil {
+		return err
+	}
+	return repos.Reservations().UpdateStatus(ctx, reservation.ID, domain.ReservationStatusReleased)
+}
+
+// RunReservationSweeper calls ReleaseExpired every interval until ctx is cancelled. Failures are
+// logged and retried on the next tick.
+func RunReservationSweeper(ctx context.Context, svc ReservationService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := svc.ReleaseExpired(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn("failed to release expired reservations", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+type fakeReservationRepo struct {
+	repository.ReservationRepository
+	reservations map[uuid.UUID]domain.Reservation
+}
+
+func (r *fakeReservationRepo) Create(ctx context.Context, reservation *domain.Reservation) error {
+	r.reservations[reservation.ID] = *reservation
+	return nil
+}
+
+func (r *fakeReservationRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Reservation, error) {
+	res, ok := r.reservations[id]
+	if !ok {
+		return nil, domain.ErrReservationNotFound
+	}
+	return &res, nil
+}
+
+func (r *fakeReservationRepo) ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.Reservation, error) {
+	var out []domain.Reservation
+	for _, res := range r.reservations {
+		if res.Status == domain.ReservationStatusActive && res.ExpiresAt.Before(before) {
+			out = append(out, res)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(out[j].ExpiresAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *fakeReservationRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ReservationStatus) error {
+	res, ok := r.reservations[id]
+	if !ok {
+		return domain.ErrReservationNotFound
+	}
+	res.Status = status
+	r.reservations[id] = res
+	return nil
+}
+
+var reservationConfig = config.OrderConfig{ReservationTTL: 15 * time.Minute, ReservationMaxTTL: time.Hour}
+
+func TestReservationService_ReserveAndRelease(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 3}
+	uow := newFakeUnitOfWork(product)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewReservationService(uow, reservationConfig, clock.Fixed(now), zap.NewNop())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	res, err := svc.Reserve(ctx, userID, product.ID, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReservationStatusActive, res.Status)
+	assert.Equal(t, now.Add(15*time.Minute), res.ExpiresAt, "defaults to the configured ttl")
+	assert.Equal(t, 1, uow.products.products[product.ID].Stock, "reserved units leave available stock")
+
+	_, err = svc.Reserve(ctx, uuid.New(), product.ID, 2, 0)
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock, "a second shopper cannot take held units")
+
+	_, err = svc.Reserve(ctx, userID, product.ID, 1, 2*time.Hour)
+	assert.ErrorContains(t, err, "ttl must be at most")
+
+	assert.ErrorIs(t, svc.Release(ctx, uuid.New(), res.ID), domain.ErrReservationNotFound, "only the owner may release")
+	require.NoError(t, svc.Release(ctx, userID, res.ID))
+	assert.Equal(t, 3, uow.products.products[product.ID].Stock)
+	assert.Equal(t, domain.ReservationStatusReleased, uow.reservations.reservations[res.ID].Status)
+	assert.ErrorIs(t, svc.Release(ctx, userID, res.ID), domain.ErrReservationInactive)
+}
+
+func TestReservationService_Reserve_ShortageLookupErrors(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 1}
+	uow := newFakeUnitOfWork(product)
+	svc := NewReservationService(uow, reservationConfig, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.Reserve(ctx, uuid.New(), uuid.New(), 1, 0)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	lost := errors.New("connection reset by peer")
+	uow.products.getErr = lost
+	_, err = svc.Reserve(ctx, uuid.New(), product.ID, 2, 0)
+	assert.ErrorIs(t, err, lost, "a failed read is not reported as a missing product")
+	assert.NotErrorIs(t, err, domain.ErrProductNotFound)
+}
+
+func TestReservationService_ConsumedByOrder(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 5}
+	other := domain.Product{ID: uuid.New(), Name: "Desk", Price: 50, Stock: 5}
+	uow := newFakeUnitOfWork(product, other)
+	clk := clock.Fixed(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	reservations := NewReservationService(uow, reservationConfig, clk, zap.NewNop())
+	orders := NewService(uow, uow.orders, nil, config.OrderConfig{}, clk, zap.NewNop())
+	ctx := context.Background()
+	userID := uuid.New()
+	stock := func() int { return uow.products.products[product.ID].Stock }
+
+	res, err := reservations.Reserve(ctx, userID, product.ID, 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, stock())
+
+	input := CreateOrderInput{
+		Items:          []OrderItemInput{{ProductID: product.ID, Quantity: 4}},
+		ReservationIDs: []uuid.UUID{res.ID},
+	}
+	_, err = orders.Create(ctx, uuid.New(), input)
+	assert.ErrorIs(t, err, domain.ErrReservationNotFound, "another user's reservation")
+
+	order, err := orders.Create(ctx, userID, input)
+	require.NoError(t, err)
+	assert.Equal(t, 0, order.Items[0].BackorderedQuantity)
+	assert.Equal(t, 1, stock(), "only the unreserved unit comes from stock")
+	assert.Equal(t, domain.ReservationStatusConsumed, uow.reservations.reservations[res.ID].Status)
+
+	_, err = orders.Create(ctx, userID, input)
+	assert.ErrorIs(t, err, domain.ErrReservationInactive, "a reservation is consumed once")
+
+	t.Run("surplus goes back to stock", func(t *testing.T) {
+		res, err := reservations.Reserve(ctx, userID, product.ID, 1, 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, stock())
+		_, err = orders.Create(ctx, userID, CreateOrderInput{
+			Items:          []OrderItemInput{{ProductID: product.ID, Quantity: 1}},
+			ReservationIDs: []uuid.UUID{res.ID},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, stock())
+	})
+
+	t.Run("reservation for a product not ordered", func(t *testing.T) {
+		res, err := reservations.Reserve(ctx, userID, other.ID, 1, 0)
+		require.NoError(t, err)
+		_, err = orders.Create(ctx, userID, CreateOrderInput{
+			Items:          []OrderItemInput{{ProductID: product.ID, Quantity: 1}},
+			ReservationIDs: []uuid.UUID{res.ID},
+		})
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, "validation_failed", appErr.Code)
+	})
+}
+
+func TestReservationService_ExpiryRestoresAvailability(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 2}
+	uow := newFakeUnitOfWork(product)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	res, err := NewReservationService(uow, reservationConfig, clock.Fixed(start), zap.NewNop()).
+		Reserve(ctx, userID, product.ID, 2, 10*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 0, uow.products.products[product.ID].Stock)
+
+	notYet := NewReservationService(uow, reservationConfig, clock.Fixed(start.Add(5*time.Minute)), zap.NewNop())
+	released, err := notYet.ReleaseExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, released)
+
+	later := clock.Fixed(start.Add(11 * time.Minute))
+	orders := NewService(uow, uow.orders, nil, config.OrderConfig{}, later, zap.NewNop())
+	_, err = orders.Create(ctx, userID, CreateOrderInput{
+		Items:          []OrderItemInput{{ProductID: product.ID, Quantity: 2}},
+		ReservationIDs: []uuid.UUID{res.ID},
+	})
+	assert.ErrorIs(t, err, domain.ErrReservationInactive, "an expired reservation cannot be spent even before the sweep")
+
+	released, err = NewReservationService(uow, reservationConfig, later, zap.NewNop()).ReleaseExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, 2, uow.products.products[product.ID].Stock)
+	assert.Equal(t, domain.ReservationStatusReleased, uow.reservations.reservations[res.ID].Status)
+
+	_, err = orders.Create(ctx, uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 2}}})
+	assert.NoError(t, err, "released units can be bought by anyone")
+}
@@ -3,45 +3,89 @@ package order
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 
 	"github.com/google/uuid"
 
+	"github.com/minilik/ecommerce/config"
 	"github.com/minilik/ecommerce/internal/domain"
 	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
 )
 
 type Service interface {
 	Create(ctx context.Context, userID uuid.UUID, input CreateOrderInput) (*domain.Order, error)
-	ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Order, error)
+	Reorder(ctx context.Context, userID, orderID uuid.UUID) (*ReorderResult, error)
+	ListForUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error)
+	ExpireStale(ctx context.Context, actorID uuid.UUID, olderThan time.Duration) (int, error)
+	History(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) ([]domain.OrderStatusChange, error)
+	GetByNumber(ctx context.Context, requesterID uuid.UUID, isAdmin bool, number string) (*domain.Order, error)
+	// Get returns an order with each item's current product details.
+	Get(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) (*domain.Order, error)
+	// Archive hides a completed or cancelled order from listings; archiving it again is a no-op.
+	Archive(ctx context.Context, id uuid.UUID) (*domain.Order, error)
+	// ApproveReview releases an order held for review as pending; RejectReview cancels it and
+	// restocks its items. Any other status fails with domain.ErrOrderNotUnderReview.
+	ApproveReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error)
+	RejectReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error)
+	Delete(ctx context.Context, id uuid.UUID, force bool) error
 }
 
+const defaultExpireBatchSize = 100
+
+// defaultOrderNumberPrefix is used when order.number_prefix is empty.
+const defaultOrderNumberPrefix = "ORD"
+
+// MaxTotalAction values for config.OrderConfig.MaxTotalAction.
+const (
+	MaxTotalActionReview = "review"
+	MaxTotalActionReject = "reject"
+)
+
+// service runs every multi-step write (stock changes plus order rows) through the UnitOfWork so it
+// commits or rolls back as one. Single-statement reads use orders directly and never open a transaction.
 type service struct {
 	uow    repository.UnitOfWork
+	orders repository.OrderRepository
+	events domain.EventPublisher
+	cfg    config.OrderConfig
 	logger *zap.Logger
 	now    func() time.Time
 }
 
-func NewService(uow repository.UnitOfWork, logger *zap.Logger) Service {
+func NewService(uow repository.UnitOfWork, orders repository.OrderRepository, events domain.EventPublisher, cfg config.OrderConfig, clk clock.Clock, logger *zap.Logger) Service {
 	return &service{
 		uow:    uow,
+		orders: orders,
+		events: events,
+		cfg:    cfg,
 		logger: logger,
-		now:    time.Now,
+		now:    clk.Now,
 	}
 }
 
 func (s *service) Create(ctx context.Context, userID uuid.UUID, input CreateOrderInput) (*domain.Order, error) {
-	if len(input.Items) == 0 {
-		return nil, fmt.Errorf("order must contain at least one item")
+	if err := validateItems(input.Items, s.cfg.MaxItems); err != nil {
+		return nil, err
+	}
+	if max := s.cfg.MaxItems; max > 0 && len(input.ReservationIDs) > max {
+		return nil, domain.NewValidationError("order may reference at most %d reservations", max)
+	}
+	description, err := s.normalizeDescription(input.Description)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &domain.Order{
 		ID:          uuid.New(),
 		UserID:      userID,
-		Description: strings.TrimSpace(input.Description),
+		Description: description,
 		Status:      domain.OrderStatusPending,
 		CreatedAt:   s.now(),
 		UpdatedAt:   s.now(),
@@ -50,71 +94,582 @@ func (s *service) Create(ctx context.Context, userID uuid.UUID, input CreateOrde
 	// This is more efficient than using a single transaction for the entire order creation
 	// because it allows for more granular control over the transaction boundaries
 
-	err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
-		var total float64
-		items := make([]domain.OrderItem, 0, len(input.Items))
+	if s.cfg.CreateTimeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.cfg.CreateTimeout)
+			defer cancel()
+		}
+	}
 
-		for _, item := range input.Items {
-			if item.Quantity <= 0 {
-				return fmt.Errorf("quantity for product %s must be greater than zero", item.ProductID)
+	var events []domain.Event
+	err = s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		events = events[:0]
+		if s.cfg.RequireVerifiedEmail {
+			if err := requireVerifiedEmail(ctx, repos, userID); err != nil {
+				return err
 			}
+		}
+		if err := s.checkOpenOrders(ctx, repos, userID); err != nil {
+			return err
+		}
+		held, err := s.consumeReservations(ctx, repos, userID, input)
+		if err != nil {
+			return err
+		}
+		// start from an empty order in case the transaction is retried
+		order.Items = make([]domain.OrderItem, 0, len(input.Items))
+		order.TotalPrice = 0
+		// every short line is collected so the client can fix them all at once
+		var shortages []domain.StockShortage
+		taken := make(map[uuid.UUID]int, len(input.Items))
 
+		for _, item := range input.Items {
 			product, err := repos.Products().GetByID(ctx, item.ProductID)
 			if err != nil {
 				return domain.ErrProductNotFound
 			}
+			if s.cfg.ForbidSelfPurchase && product.UserID == userID {
+				return fmt.Errorf("%w: %s", domain.ErrSelfPurchase, product.Name)
+			}
 
-			if product.Stock < item.Quantity {
-				return fmt.Errorf("%w: %s", domain.ErrInsufficientStock, product.Name)
+			// reserved units already left stock; reserving more than ordered gives the surplus back
+			reserved := held[item.ProductID]
+			needed := max(item.Quantity-reserved, 0)
+			surplus := max(reserved-item.Quantity, 0)
+			fromStock := needed
+			if product.Stock < needed {
+				if !s.cfg.AllowBackorder {
+					shortages = append(shortages, domain.StockShortage{
+						ProductID:   product.ID,
+						ProductName: product.Name,
+						Requested:   item.Quantity,
+						Available:   product.Stock + reserved,
+					})
+					continue
+				}
+				// backorder mode: take what is on hand and record the shortfall on the item
+				fromStock = product.Stock
 			}
+			fulfilled := item.Quantity - needed + fromStock
 
-			product.Stock -= item.Quantity
-			product.UpdatedAt = s.now()
+			if fromStock > 0 {
+				taken[product.ID] = fromStock
+			}
+			if surplus > 0 {
+				if err := repos.Products().IncrementStock(ctx, product.ID, surplus); err != nil {
+					return err
+				}
+			}
 
-			if err := repos.Products().Update(ctx, product); err != nil {
-				return err
+			// only the order that crosses from positive to zero reports it
+			if fromStock > 0 && product.Stock == fromStock {
+				events = append(events, domain.OutOfStockEvent{
+					ProductID:   product.ID,
+					ProductName: product.Name,
+					OrderID:     order.ID,
+					OccurredAt:  s.now(),
+				})
+			}
+
+			itemStatus := domain.OrderItemStatusFulfilled
+			if fulfilled < item.Quantity {
+				itemStatus = domain.OrderItemStatusBackordered
 			}
 
-			itemTotal := product.Price * float64(item.Quantity)
-			total += itemTotal
+			order.AddItem(domain.OrderItem{
+				ID:                  uuid.New(),
+				ProductID:           product.ID,
+				OrderID:             order.ID,
+				ProductName:         product.Name,
+				Quantity:            item.Quantity,
+				BackorderedQuantity: item.Quantity - fulfilled,
+				Status:              itemStatus,
+				UnitPrice:           product.Price,
+				CreatedAt:           s.now(),
+				UpdatedAt:           s.now(),
+			})
+		}
+		if len(shortages) > 0 {
+			return &domain.InsufficientStockError{Shortages: shortages}
+		}
+		// one conditional statement takes the stock of every line, so two orders can never both
+		// take the last units; a line only comes up short here when a concurrent order won the race
+		// since the read above, and the transaction rolls back the lines already taken
+		short, err := repos.Products().DecrementStockBatch(ctx, taken)
+		if err != nil {
+			return err
+		}
+		if len(short) > 0 {
+			return lostStockRace(ctx, repos, short, input.Items, held)
+		}
+		total := order.TotalPrice
 
-			items = append(items, domain.OrderItem{
-				ID:        uuid.New(),
-				ProductID: product.ID,
-				OrderID:   order.ID,
-				Quantity:  item.Quantity,
-				UnitPrice: product.Price,
-				CreatedAt: s.now(),
-				UpdatedAt: s.now(),
+		if input.ExpectedTotal != nil && toCents(*input.ExpectedTotal) != toCents(total) {
+			return fmt.Errorf("%w: expected %.2f, current total %.2f", domain.ErrPriceChanged, *input.ExpectedTotal, total)
+		}
+
+		if s.cfg.MaxTotal > 0 && toCents(total) > toCents(s.cfg.MaxTotal) {
+			if s.cfg.MaxTotalAction == MaxTotalActionReject {
+				return fmt.Errorf("%w: %.2f is over %.2f", domain.ErrOrderTotalExceeded, total, s.cfg.MaxTotal)
+			}
+			order.Status = domain.OrderStatusReviewRequired
+			events = append(events, domain.OrderReviewRequiredEvent{
+				OrderID:    order.ID,
+				UserID:     userID,
+				Total:      total,
+				MaxTotal:   s.cfg.MaxTotal,
+				OccurredAt: s.now(),
 			})
 		}
 
-		order.TotalPrice = total
-		order.Items = items
+		seq, err := repos.Orders().NextOrderNumber(ctx)
+		if err != nil {
+			return err
+		}
+		order.OrderNumber = domain.FormatOrderNumber(s.orderNumberPrefix(), order.CreatedAt.Year(), seq)
 
 		if err := repos.Orders().Create(ctx, order); err != nil {
 			return err
 		}
 
-		return nil
+		return s.recordStatusChange(ctx, repos, order.ID, "", order.Status, userID)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	if order.Status == domain.OrderStatusReviewRequired {
+		s.logger.Info("order held for review", zap.String("order_id", order.ID.String()), zap.Float64("total", order.TotalPrice))
+	}
+	s.publish(ctx, events)
+
 	return order, nil
 }
 
-func (s *service) ListForUser(ctx context.Context, userID uuid.UUID) ([]domain.Order, error) {
-	var orders []domain.Order
-	err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
-		var err error
-		orders, err = repos.Orders().ListByUser(ctx, userID)
+// lostStockRace reports the lines DecrementStockBatch could not take with the stock that is left
+// now, in the same shape as the shortages found before the write.
+func lostStockRace(ctx context.Context, repos repository.RepositoryProvider, short []uuid.UUID, items []OrderItemInput, held map[uuid.UUID]int) error {
+	requested := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		requested[item.ProductID] = item.Quantity
+	}
+	shortages := make([]domain.StockShortage, 0, len(short))
+	for _, id := range short {
+		product, err := repos.Products().GetByID(ctx, id)
+		if err != nil {
+			return domain.ErrProductNotFound
+		}
+		shortages = append(shortages, domain.StockShortage{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Requested:   requested[id],
+			Available:   product.Stock + held[id],
+		})
+	}
+	return &domain.InsufficientStockError{Shortages: shortages}
+}
+
+// consumeReservations locks the reservations named in input, marks them consumed and returns the
+// units they hold per product. Each must belong to the user, still be active and unexpired, and
+// be for a product on the order.
+func (s *service) consumeReservations(ctx context.Context, repos repository.RepositoryProvider, userID uuid.UUID, input CreateOrderInput) (map[uuid.UUID]int, error) {
+	if len(input.ReservationIDs) == 0 {
+		return nil, nil
+	}
+	ordered := make(map[uuid.UUID]struct{}, len(input.Items))
+	for _, item := range input.Items {
+		ordered[item.ProductID] = struct{}{}
+	}
+	held := make(map[uuid.UUID]int, len(input.ReservationIDs))
+	for _, id := range input.ReservationIDs {
+		reservation, err := repos.Reservations().GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if reservation.UserID != userID {
+			return nil, domain.ErrReservationNotFound
+		}
+		if reservation.Status != domain.ReservationStatusActive || !s.now().Before(reservation.ExpiresAt) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrReservationInactive, id)
+		}
+		if _, ok := ordered[reservation.ProductID]; !ok {
+			return nil, domain.NewValidationError("reservation %s is for a product not on the order", id)
+		}
+		if err := repos.Reservations().UpdateStatus(ctx, id, domain.ReservationStatusConsumed); err != nil {
+			return nil, err
+		}
+		held[reservation.ProductID] += reservation.Quantity
+	}
+	return held, nil
+}
+
+// Reorder places a new order for the items of one of the user's previous orders. It goes through
+// Create, so stock is checked and current prices are charged; items whose price moved since the
+// original order are reported. Another user's order is reported as not found.
+func (s *service) Reorder(ctx context.Context, userID, orderID uuid.UUID) (*ReorderResult, error) {
+	previous, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.UserID != userID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	input := CreateOrderInput{
+		Description: previous.Description,
+		Items:       make([]OrderItemInput, 0, len(previous.Items)),
+	}
+	previousPrices := make(map[uuid.UUID]float64, len(previous.Items))
+	for _, item := range previous.Items {
+		input.Items = append(input.Items, OrderItemInput{ProductID: item.ProductID, Quantity: item.Quantity})
+		previousPrices[item.ProductID] = item.UnitPrice
+	}
+
+	order, err := s.Create(ctx, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReorderResult{Order: order, PriceChanges: []PriceChange{}}
+	for _, item := range order.Items {
+		if before := previousPrices[item.ProductID]; toCents(before) != toCents(item.UnitPrice) {
+			result.PriceChanges = append(result.PriceChanges, PriceChange{
+				ProductID:     item.ProductID,
+				ProductName:   item.ProductName,
+				PreviousPrice: before,
+				CurrentPrice:  item.UnitPrice,
+			})
+		}
+	}
+	return result, nil
+}
+
+// checkOpenOrders refuses a new order once the user holds cfg.MaxOpenOrders pending orders. The
+// count is read inside the order transaction, so concurrent requests from one user can still
+// overshoot the limit by the number in flight.
+func (s *service) checkOpenOrders(ctx context.Context, repos repository.RepositoryProvider, userID uuid.UUID) error {
+	if s.cfg.MaxOpenOrders <= 0 {
+		return nil
+	}
+	open, err := repos.Orders().CountByUserAndStatus(ctx, userID, domain.OrderStatusPending)
+	if err != nil {
+		return err
+	}
+	if open >= int64(s.cfg.MaxOpenOrders) {
+		return fmt.Errorf("%w: limit is %d", domain.ErrTooManyOpenOrders, s.cfg.MaxOpenOrders)
+	}
+	return nil
+}
+
+// requireVerifiedEmail refuses users who have not verified their email address.
+func requireVerifiedEmail(ctx context.Context, repos repository.RepositoryProvider, userID uuid.UUID) error {
+	user, err := repos.Users().FindByID(ctx, userID)
+	if err != nil {
 		return err
+	}
+	if user == nil {
+		return domain.ErrUserNotFound
+	}
+	if !user.EmailVerified {
+		return domain.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// validateItems rejects malformed input before a transaction is opened. Whether the products exist
+// and have stock is only known inside the transaction, so that check stays there. The item count is
+// checked first so an oversized payload is turned away before any per-item work; max <= 0 disables it.
+func validateItems(items []OrderItemInput, max int) error {
+	if len(items) == 0 {
+		return domain.NewValidationError("order must contain at least one item")
+	}
+	if max > 0 && len(items) > max {
+		return domain.NewValidationError("order must contain at most %d items, got %d", max, len(items))
+	}
+	seen := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		if item.ProductID == uuid.Nil {
+			return domain.NewValidationError("product id is required")
+		}
+		if item.Quantity <= 0 {
+			return domain.NewValidationError("quantity for product %s must be greater than zero", item.ProductID)
+		}
+		if _, dup := seen[item.ProductID]; dup {
+			return domain.NewValidationError("product %s is listed more than once", item.ProductID)
+		}
+		seen[item.ProductID] = struct{}{}
+	}
+	return nil
+}
+
+// normalizeDescription trims the description and normalizes line endings. Newlines and tabs are
+// kept so customers can leave multi-line notes; any other control character is rejected.
+func (s *service) normalizeDescription(raw string) (string, error) {
+	description := strings.TrimSpace(strings.ReplaceAll(raw, "\r\n", "\n"))
+	for _, r := range description {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return "", domain.NewValidationError("description must not contain control characters")
+		}
+	}
+	if max := s.cfg.MaxDescriptionLength; max > 0 && utf8.RuneCountInString(description) > max {
+		return "", domain.NewValidationError("description must be at most %d characters", max)
+	}
+	return description, nil
+}
+
+// toCents rounds an amount to whole cents so totals are compared to the cent, not bit-for-bit.
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// publish emits events after the transaction committed; failures are logged and never fail the order.
+func (s *service) publish(ctx context.Context, events []domain.Event) {
+	if s.events == nil {
+		return
+	}
+	for _, event := range events {
+		if err := s.events.Publish(ctx, event); err != nil {
+			s.logger.Warn("failed to publish event", zap.String("event", event.Name()), zap.Error(err))
+		}
+	}
+}
+
+// ListForUser is a plain read, so it goes straight to the repository without a transaction. An
+// empty status lists orders in every status; archived orders are only listed with includeArchived.
+func (s *service) ListForUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error) {
+	return s.orders.ListByUser(ctx, userID, status, includeArchived)
+}
+
+// ExpireStale cancels pending orders older than olderThan (the configured PendingTTL when zero) and
+// returns their fulfilled stock. Orders are processed in batches, each in its own transaction, to keep locks short.
+// actorID is recorded in each order's status history; uuid.Nil records the change as the system's.
+func (s *service) ExpireStale(ctx context.Context, actorID uuid.UUID, olderThan time.Duration) (int, error) {
+	if olderThan <= 0 {
+		olderThan = s.cfg.PendingTTL
+	}
+	if olderThan <= 0 {
+		return 0, domain.NewValidationError("expiry age must be greater than zero")
+	}
+	batchSize := s.cfg.ExpireBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExpireBatchSize
+	}
+	cutoff := s.now().Add(-olderThan)
+
+	cancelled := 0
+	for {
+		var processed int
+		err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+			orders, err := repos.Orders().ListPendingBefore(ctx, cutoff, batchSize)
+			if err != nil {
+				return err
+			}
+			processed = len(orders)
+			for _, order := range orders {
+				if err := s.restock(ctx, repos, order); err != nil {
+					return err
+				}
+				if err := repos.Orders().UpdateStatus(ctx, order.ID, domain.OrderStatusCancelled); err != nil {
+					return err
+				}
+				if err := s.recordStatusChange(ctx, repos, order.ID, order.Status, domain.OrderStatusCancelled, actorID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled += processed
+		if processed < batchSize {
+			break
+		}
+	}
+
+	if cancelled > 0 {
+		s.logger.Info("expired stale pending orders", zap.Int("count", cancelled), zap.Time("cutoff", cutoff))
+	}
+	return cancelled, nil
+}
+
+// History returns an order's status transitions, oldest first. Only the order's owner or an admin
+// may read it; anyone else gets domain.ErrOrderNotFound so the order's existence is not revealed.
+func (s *service) History(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && order.UserID != requesterID {
+		return nil, domain.ErrOrderNotFound
+	}
+	return s.orders.ListStatusChanges(ctx, orderID)
+}
+
+// GetByNumber looks an order up by its order number. Orders of other users are reported as not
+// found unless the requester is an admin.
+func (s *service) GetByNumber(ctx context.Context, requesterID uuid.UUID, isAdmin bool, number string) (*domain.Order, error) {
+	number = domain.NormalizeOrderNumber(number)
+	if number == "" {
+		return nil, domain.NewValidationError("order number is required")
+	}
+	order, err := s.orders.GetByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && order.UserID != requesterID {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// Get loads an order for its detail view. The items' ProductName and UnitPrice are what was bought
+// and stay as recorded; Product carries the live product for display. Orders of other users are
+// reported as not found unless the requester is an admin.
+func (s *service) Get(ctx context.Context, requesterID uuid.UUID, isAdmin bool, orderID uuid.UUID) (*domain.Order, error) {
+	order, err := s.orders.GetByIDWithProducts(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && order.UserID != requesterID {
+		return nil, domain.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// orderNumberPrefix is upper-cased like the numbers GetByNumber looks up, so a lowercase
+// order.number_prefix still yields numbers customers can find.
+func (s *service) orderNumberPrefix() string {
+	prefix := strings.ToUpper(strings.TrimSpace(s.cfg.NumberPrefix))
+	if prefix == "" {
+		return defaultOrderNumberPrefix
+	}
+	return prefix
+}
+
+// recordStatusChange writes a status history entry through repos, so it commits or rolls back with
+// the change it describes. A nil actorID is stored as a system change.
+func (s *service) recordStatusChange(ctx context.Context, repos repository.RepositoryProvider, orderID uuid.UUID, from, to domain.OrderStatus, actorID uuid.UUID) error {
+	change := &domain.OrderStatusChange{
+		ID:         uuid.New(),
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		CreatedAt:  s.now(),
+	}
+	if actorID != uuid.Nil {
+		change.ActorID = &actorID
+	}
+	return repos.Orders().AddStatusChange(ctx, change)
+}
+
+func (s *service) Archive(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	var archived *domain.Order
+	err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		order, err := repos.Orders().GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order.ArchivedAt == nil {
+			if !order.Archivable() {
+				return domain.ErrOrderNotArchivable
+			}
+			now := s.now()
+			if err := repos.Orders().Archive(ctx, id, now); err != nil {
+				return err
+			}
+			order.ArchivedAt = &now
+			s.logger.Info("order archived", zap.String("order_id", id.String()), zap.String("status", string(order.Status)))
+		}
+		archived = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+func (s *service) ApproveReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error) {
+	return s.review(ctx, actorID, id, domain.OrderStatusPending)
+}
+
+func (s *service) RejectReview(ctx context.Context, actorID, id uuid.UUID) (*domain.Order, error) {
+	return s.review(ctx, actorID, id, domain.OrderStatusCancelled)
+}
+
+// review moves an order out of review_required. Approved orders become pending and so fall under
+// pending expiry from now on; rejected ones give their stock back like any cancelled order.
+func (s *service) review(ctx context.Context, actorID, id uuid.UUID, to domain.OrderStatus) (*domain.Order, error) {
+	var reviewed *domain.Order
+	err := s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		order, err := repos.Orders().GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.OrderStatusReviewRequired {
+			return domain.ErrOrderNotUnderReview
+		}
+		if to == domain.OrderStatusCancelled {
+			if err := s.restock(ctx, repos, *order); err != nil {
+				return err
+			}
+		}
+		if err := repos.Orders().UpdateStatus(ctx, id, to); err != nil {
+			return err
+		}
+		if err := s.recordStatusChange(ctx, repos, id, order.Status, to, actorID); err != nil {
+			return err
+		}
+		order.Status = to
+		order.UpdatedAt = s.now()
+		reviewed = order
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	return orders, nil
+	s.logger.Info("order reviewed", zap.String("order_id", id.String()), zap.String("status", string(to)))
+	return reviewed, nil
+}
+
+// Delete removes an order and its items. Cancelled orders were already restocked and are deleted
+// as-is; any other order is rejected unless force is set, in which case it is restocked first.
+func (s *service) Delete(ctx context.Context, id uuid.UUID, force bool) error {
+	return s.uow.Execute(ctx, func(repos repository.RepositoryProvider) error {
+		order, err := repos.Orders().GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.OrderStatusCancelled {
+			if !force {
+				return domain.ErrOrderNotCancelled
+			}
+			if err := s.restock(ctx, repos, *order); err != nil {
+				return err
+			}
+		}
+		if err := repos.Orders().Delete(ctx, id); err != nil {
+			return err
+		}
+		s.logger.Info("order deleted", zap.String("order_id", id.String()), zap.String("status", string(order.Status)), zap.Bool("force", force))
+		return nil
+	})
+}
+
+// restock returns the fulfilled (non-backordered) quantity of each item to its product, adding in
+// place so a concurrent order's decrement is never overwritten.
+func (s *service) restock(ctx context.Context, repos repository.RepositoryProvider, order domain.Order) error {
+	for _, item := range order.Items {
+		fulfilled := item.Quantity - item.BackorderedQuantity
+		if fulfilled <= 0 {
+			continue
+		}
+		if err := repos.Products().IncrementStock(ctx, item.ProductID, fulfilled); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,1043 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// fakeUnitOfWork runs the callback directly against in-memory repositories, one at a time like
+// transactions that touch the same rows.
+type fakeUnitOfWork struct {
+	mu           sync.Mutex
+	products     *fakeProductRepo
+	orders       *fakeOrderRepo
+	users        *fakeUserRepo
+	reservations *fakeReservationRepo
+	executions   int
+}
+
+func newFakeUnitOfWork(products ...domain.Product) *fakeUnitOfWork {
+	repo := &fakeProductRepo{products: make(map[uuid.UUID]domain.Product, len(products))}
+	for _, p := range products {
+		repo.products[p.ID] = p
+	}
+	return &fakeUnitOfWork{
+		products:     repo,
+		orders:       &fakeOrderRepo{},
+		users:        &fakeUserRepo{users: map[uuid.UUID]domain.User{}},
+		reservations: &fakeReservationRepo{reservations: map[uuid.UUID]domain.Reservation{}},
+	}
+}
+
+func (u *fakeUnitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.executions++
+	return fn(u)
+}
+
+func (u *fakeUnitOfWork) Users() repository.UserRepository       { return u.users }
+func (u *fakeUnitOfWork) Products() repository.ProductRepository { return u.products }
+func (u *fakeUnitOfWork) Orders() repository.OrderRepository     { return u.orders }
+func (u *fakeUnitOfWork) Reservations() repository.ReservationRepository {
+	return u.reservations
+}
+func (u *fakeUnitOfWork) PasswordResets() repository.PasswordResetRepository { return nil }
+
+type fakeProductRepo struct {
+	repository.ProductRepository
+	products map[uuid.UUID]domain.Product
+	// stale makes the next GetByID of a product report that much more stock than is left, like a
+	// read a concurrent order overtook before the write.
+	stale map[uuid.UUID]int
+	// getErr, when set, fails every GetByID like a lost database connection.
+	getErr error
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	p, ok := r.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	p.Stock += r.stale[id]
+	delete(r.stale, id)
+	return &p, nil
+}
+
+func (r *fakeProductRepo) Update(ctx context.Context, product *domain.Product) error {
+	r.products[product.ID] = *product
+	return nil
+}
+
+// DecrementStockBatch applies all quantities or, when any product is missing or short, none: the
+// fake unit of work does not roll back, so this leaves the state a rolled back transaction would.
+func (r *fakeProductRepo) DecrementStockBatch(ctx context.Context, quantities map[uuid.UUID]int) ([]uuid.UUID, error) {
+	var short []uuid.UUID
+	for id, qty := range quantities {
+		if p, ok := r.products[id]; !ok || p.Stock < qty {
+			short = append(short, id)
+		}
+	}
+	if len(short) > 0 {
+		return short, nil
+	}
+	for id, qty := range quantities {
+		p := r.products[id]
+		p.Stock -= qty
+		r.products[id] = p
+	}
+	return nil, nil
+}
+
+func (r *fakeProductRepo) IncrementStock(ctx context.Context, id uuid.UUID, qty int) error {
+	p, ok := r.products[id]
+	if !ok {
+		return domain.ErrProductNotFound
+	}
+	p.Stock += qty
+	r.products[id] = p
+	return nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uuid.UUID]domain.User
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+type fakeOrderRepo struct {
+	repository.OrderRepository
+	created []domain.Order
+	history []domain.OrderStatusChange
+	seq     atomic.Int64
+}
+
+func (r *fakeOrderRepo) NextOrderNumber(ctx context.Context) (int64, error) {
+	return r.seq.Add(1), nil
+}
+
+func (r *fakeOrderRepo) GetByNumber(ctx context.Context, number string) (*domain.Order, error) {
+	for _, o := range r.created {
+		if o.OrderNumber == number {
+			return &o, nil
+		}
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+func (r *fakeOrderRepo) GetByIDWithProducts(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	for _, o := range r.created {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+func (r *fakeOrderRepo) AddStatusChange(ctx context.Context, change *domain.OrderStatusChange) error {
+	r.history = append(r.history, *change)
+	return nil
+}
+
+func (r *fakeOrderRepo) ListStatusChanges(ctx context.Context, orderID uuid.UUID) ([]domain.OrderStatusChange, error) {
+	var out []domain.OrderStatusChange
+	for _, c := range r.history {
+		if c.OrderID == orderID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeOrderRepo) Create(ctx context.Context, order *domain.Order) error {
+	r.created = append(r.created, *order)
+	return nil
+}
+
+func (r *fakeOrderRepo) CountByUserAndStatus(ctx context.Context, userID uuid.UUID, status domain.OrderStatus) (int64, error) {
+	var n int64
+	for _, o := range r.created {
+		if o.UserID == userID && o.Status == status {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeOrderRepo) ListByUser(ctx context.Context, userID uuid.UUID, status domain.OrderStatus, includeArchived bool) ([]domain.Order, error) {
+	var out []domain.Order
+	for _, o := range r.created {
+		if o.ArchivedAt != nil && !includeArchived {
+			continue
+		}
+		if o.UserID == userID && (status == "" || o.Status == status) {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeOrderRepo) ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]domain.Order, error) {
+	var out []domain.Order
+	for _, o := range r.created {
+		if o.Status == domain.OrderStatusPending && o.UpdatedAt.Before(before) {
+			out = append(out, o)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.Before(out[j].UpdatedAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *fakeOrderRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error {
+	for i := range r.created {
+		if r.created[i].ID == id {
+			r.created[i].Status = status
+			r.created[i].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("order %s not found", id)
+}
+
+func (r *fakeOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	return r.GetByIDForUpdate(ctx, id)
+}
+
+func (r *fakeOrderRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Order, error) {
+	for _, o := range r.created {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+	return nil, domain.ErrOrderNotFound
+}
+
+func (r *fakeOrderRepo) Archive(ctx context.Context, id uuid.UUID, at time.Time) error {
+	for i := range r.created {
+		if r.created[i].ID == id && r.created[i].ArchivedAt == nil {
+			r.created[i].ArchivedAt = &at
+			return nil
+		}
+	}
+	return domain.ErrOrderNotFound
+}
+
+func (r *fakeOrderRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	for i, o := range r.created {
+		if o.ID == id {
+			r.created = append(r.created[:i], r.created[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrOrderNotFound
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestService_Create_OutOfStockEvent(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 3}
+	uow := newFakeUnitOfWork(product)
+	publisher := &recordingPublisher{}
+	svc := NewService(uow, uow.orders, publisher, config.OrderConfig{}, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+	order := func(qty int) error {
+		_, err := svc.Create(ctx, uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: qty}}})
+		return err
+	}
+
+	require.NoError(t, order(2))
+	assert.Empty(t, publisher.events, "stock still positive")
+
+	require.NoError(t, order(1))
+	require.Len(t, publisher.events, 1, "fires on the transition to zero")
+	event, ok := publisher.events[0].(domain.OutOfStockEvent)
+	require.True(t, ok)
+	assert.Equal(t, product.ID, event.ProductID)
+
+	assert.ErrorIs(t, order(1), domain.ErrInsufficientStock)
+	assert.Len(t, publisher.events, 1, "does not fire again once already at zero")
+}
+
+func TestService_Create_SnapshotsProductName(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Price: 25, Stock: 5}
+	uow := newFakeUnitOfWork(product)
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+	_, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+	require.NoError(t, err)
+
+	renamed := uow.products.products[product.ID]
+	renamed.Name = "Floor Lamp"
+	require.NoError(t, uow.products.Update(context.Background(), &renamed))
+
+	require.Len(t, uow.orders.created, 1)
+	assert.Equal(t, "Desk Lamp", uow.orders.created[0].Items[0].ProductName)
+}
+
+func TestService_Create_Backorder(t *testing.T) {
+	cases := []struct {
+		name            string
+		stock           int
+		quantity        int
+		wantStatus      domain.OrderItemStatus
+		wantBackordered int
+		wantStock       int
+	}{
+		{name: "full", stock: 5, quantity: 3, wantStatus: domain.OrderItemStatusFulfilled, wantBackordered: 0, wantStock: 2},
+		{name: "partial", stock: 2, quantity: 5, wantStatus: domain.OrderItemStatusBackordered, wantBackordered: 3, wantStock: 0},
+		{name: "fully backordered", stock: 0, quantity: 4, wantStatus: domain.OrderItemStatusBackordered, wantBackordered: 4, wantStock: 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Name: "Chair", Price: 40, Stock: tc.stock}
+			uow := newFakeUnitOfWork(product)
+			svc := NewService(uow, uow.orders, nil, config.OrderConfig{AllowBackorder: true}, clock.Real(), zap.NewNop())
+
+			order, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: tc.quantity}}})
+			require.NoError(t, err)
+
+			require.Len(t, order.Items, 1)
+			assert.Equal(t, tc.wantStatus, order.Items[0].Status)
+			assert.Equal(t, tc.wantBackordered, order.Items[0].BackorderedQuantity)
+			assert.Equal(t, tc.wantStock, uow.products.products[product.ID].Stock)
+		})
+	}
+
+	t.Run("strict mode rejects shortfall", func(t *testing.T) {
+		product := domain.Product{ID: uuid.New(), Name: "Chair", Price: 40, Stock: 1}
+		uow := newFakeUnitOfWork(product)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 2}}})
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	})
+
+	t.Run("strict mode reports every short line", func(t *testing.T) {
+		chair := domain.Product{ID: uuid.New(), Name: "Chair", Price: 40, Stock: 1}
+		lamp := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Price: 20, Stock: 10}
+		mug := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 0}
+		uow := newFakeUnitOfWork(chair, lamp, mug)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{
+			{ProductID: chair.ID, Quantity: 3},
+			{ProductID: lamp.ID, Quantity: 2},
+			{ProductID: mug.ID, Quantity: 1},
+		}})
+
+		var stockErr *domain.InsufficientStockError
+		require.ErrorAs(t, err, &stockErr)
+		assert.Equal(t, []domain.StockShortage{
+			{ProductID: chair.ID, ProductName: "Chair", Requested: 3, Available: 1},
+			{ProductID: mug.ID, ProductName: "Mug", Requested: 1, Available: 0},
+		}, stockErr.Shortages)
+		assert.EqualError(t, err, "insufficient stock: Chair, Mug")
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, "insufficient_stock", appErr.Code)
+		assert.Empty(t, uow.orders.created)
+	})
+
+	t.Run("stock taken by a concurrent order", func(t *testing.T) {
+		chair := domain.Product{ID: uuid.New(), Name: "Chair", Price: 40, Stock: 1}
+		lamp := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Price: 20, Stock: 10}
+		uow := newFakeUnitOfWork(chair, lamp)
+		// the order reads 3 chairs, but another order took 2 of them before the decrement
+		uow.products.stale = map[uuid.UUID]int{chair.ID: 2}
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{
+			{ProductID: chair.ID, Quantity: 3},
+			{ProductID: lamp.ID, Quantity: 2},
+		}})
+
+		var stockErr *domain.InsufficientStockError
+		require.ErrorAs(t, err, &stockErr)
+		assert.Equal(t, []domain.StockShortage{
+			{ProductID: chair.ID, ProductName: "Chair", Requested: 3, Available: 1},
+		}, stockErr.Shortages)
+		assert.Equal(t, 1, uow.products.products[chair.ID].Stock)
+		assert.Equal(t, 10, uow.products.products[lamp.ID].Stock)
+		assert.Empty(t, uow.orders.created)
+	})
+}
+
+func TestService_Create_ExpectedTotal(t *testing.T) {
+	total := func(v float64) *float64 { return &v }
+	cases := []struct {
+		name     string
+		expected *float64
+		wantErr  error
+	}{
+		{name: "not provided", expected: nil},
+		{name: "matches", expected: total(59.97)},
+		{name: "within rounding", expected: total(59.9700001)},
+		{name: "price went up", expected: total(49.97), wantErr: domain.ErrPriceChanged},
+		{name: "off by a cent", expected: total(59.96), wantErr: domain.ErrPriceChanged},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 19.99, Stock: 10}
+			uow := newFakeUnitOfWork(product)
+			svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+			order, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{
+				Items:         []OrderItemInput{{ProductID: product.ID, Quantity: 3}},
+				ExpectedTotal: tc.expected,
+			})
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				assert.Contains(t, err.Error(), "current total 59.97")
+				assert.Empty(t, uow.orders.created, "no order is stored on mismatch")
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, 59.97, order.TotalPrice, 0.001)
+		})
+	}
+}
+
+func TestService_Create_Description(t *testing.T) {
+	cases := []struct {
+		name        string
+		description string
+		want        string
+		wantErr     string
+	}{
+		{name: "trimmed", description: "  leave at the door  ", want: "leave at the door"},
+		{name: "at the limit", description: strings.Repeat("é", 20), want: strings.Repeat("é", 20)},
+		{name: "over the limit", description: strings.Repeat("a", 21), wantErr: "at most 20 characters"},
+		{name: "newlines kept and normalized", description: "ring\r\nback\tdoor", want: "ring\nback\tdoor"},
+		{name: "other control characters rejected", description: "gift\x00wrap", wantErr: "control characters"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Name: "Vase", Price: 12, Stock: 5}
+			uow := newFakeUnitOfWork(product)
+			svc := NewService(uow, uow.orders, nil, config.OrderConfig{MaxDescriptionLength: 20}, clock.Real(), zap.NewNop())
+
+			order, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{
+				Description: tc.description,
+				Items:       []OrderItemInput{{ProductID: product.ID, Quantity: 1}},
+			})
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				assert.Empty(t, uow.orders.created)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, order.Description)
+		})
+	}
+}
+
+func TestService_ListForUser_NoTransaction(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Teapot", Price: 18, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+	buyer, other := uuid.New(), uuid.New()
+
+	for _, userID := range []uuid.UUID{buyer, buyer, other} {
+		_, err := svc.Create(ctx, userID, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, uow.executions, "each create runs in its own transaction")
+
+	orders, err := svc.ListForUser(ctx, buyer, "", false)
+	require.NoError(t, err)
+	assert.Len(t, orders, 2)
+	assert.Equal(t, 3, uow.executions, "listing does not open a transaction")
+}
+
+func TestService_ExpireStale(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+
+	pendingOrder := func(age time.Duration, qty, backordered int) domain.Order {
+		return domain.Order{
+			ID:        uuid.New(),
+			Status:    domain.OrderStatusPending,
+			CreatedAt: now.Add(-age),
+			UpdatedAt: now.Add(-age),
+			Items: []domain.OrderItem{{
+				ProductID:           product.ID,
+				Quantity:            qty,
+				BackorderedQuantity: backordered,
+			}},
+		}
+	}
+	old1 := pendingOrder(72*time.Hour, 2, 0)
+	old2 := pendingOrder(48*time.Hour, 3, 1)
+	recent := pendingOrder(time.Hour, 4, 0)
+	uow.orders.created = []domain.Order{old1, recent, old2}
+
+	// batch size 1 forces several transactions
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{PendingTTL: 24 * time.Hour, ExpireBatchSize: 1}, clock.Fixed(now), zap.NewNop())
+
+	cancelled, err := svc.ExpireStale(context.Background(), uuid.Nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cancelled)
+
+	statuses := make(map[uuid.UUID]domain.OrderStatus)
+	for _, o := range uow.orders.created {
+		statuses[o.ID] = o.Status
+	}
+	assert.Equal(t, domain.OrderStatusCancelled, statuses[old1.ID])
+	assert.Equal(t, domain.OrderStatusCancelled, statuses[old2.ID])
+	assert.Equal(t, domain.OrderStatusPending, statuses[recent.ID])
+
+	// 2 from old1 plus the 2 fulfilled units of old2; backordered units were never taken from stock
+	assert.Equal(t, 14, uow.products.products[product.ID].Stock)
+}
+
+func TestService_Delete(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (Service, *fakeUnitOfWork, domain.Product, *domain.Order) {
+		product := domain.Product{ID: uuid.New(), Name: "Kettle", Price: 30, Stock: 5}
+		uow := newFakeUnitOfWork(product)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+		order, err := svc.Create(ctx, uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 2}}})
+		require.NoError(t, err)
+		require.Equal(t, 3, uow.products.products[product.ID].Stock)
+		return svc, uow, product, order
+	}
+
+	t.Run("pending order requires force", func(t *testing.T) {
+		svc, uow, product, order := setup(t)
+		require.ErrorIs(t, svc.Delete(ctx, order.ID, false), domain.ErrOrderNotCancelled)
+		assert.Len(t, uow.orders.created, 1)
+		assert.Equal(t, 3, uow.products.products[product.ID].Stock)
+	})
+
+	t.Run("force restocks and deletes order with items", func(t *testing.T) {
+		svc, uow, product, order := setup(t)
+		require.NoError(t, svc.Delete(ctx, order.ID, true))
+		assert.Equal(t, 5, uow.products.products[product.ID].Stock)
+		assert.Empty(t, uow.orders.created)
+		_, err := uow.orders.GetByIDForUpdate(ctx, order.ID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	t.Run("cancelled order is not restocked twice", func(t *testing.T) {
+		svc, uow, product, order := setup(t)
+		// cancellation already returned the stock
+		require.NoError(t, uow.orders.UpdateStatus(ctx, order.ID, domain.OrderStatusCancelled))
+		restored := uow.products.products[product.ID]
+		restored.Stock = 5
+		uow.products.products[product.ID] = restored
+
+		require.NoError(t, svc.Delete(ctx, order.ID, false))
+		assert.Equal(t, 5, uow.products.products[product.ID].Stock)
+		assert.Empty(t, uow.orders.created)
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		assert.ErrorIs(t, svc.Delete(ctx, uuid.New(), true), domain.ErrOrderNotFound)
+	})
+}
+
+func TestService_Create_RejectsInvalidItemsBeforeTransaction(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 30, Stock: 5}
+	tooMany := make([]OrderItemInput, 4)
+	for i := range tooMany {
+		tooMany[i] = OrderItemInput{ProductID: uuid.New(), Quantity: 1}
+	}
+	cases := []struct {
+		name    string
+		items   []OrderItemInput
+		wantErr string
+	}{
+		{name: "no items", items: nil, wantErr: "at least one item"},
+		{name: "zero quantity", items: []OrderItemInput{{ProductID: product.ID, Quantity: 0}}, wantErr: "greater than zero"},
+		{name: "nil product id", items: []OrderItemInput{{ProductID: uuid.Nil, Quantity: 1}}, wantErr: "product id is required"},
+		{name: "duplicate product", items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}, {ProductID: product.ID, Quantity: 2}}, wantErr: "more than once"},
+		{name: "more items than order.max_items", items: tooMany, wantErr: "at most 3 items, got 4"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uow := newFakeUnitOfWork(product)
+			svc := NewService(uow, uow.orders, nil, config.OrderConfig{MaxItems: 3}, clock.Real(), zap.NewNop())
+
+			_, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: tc.items})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+			assert.Equal(t, 0, uow.executions, "no transaction for malformed input")
+			assert.Equal(t, 5, uow.products.products[product.ID].Stock)
+		})
+	}
+}
+
+func TestService_Create_MaxTotal(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Camera", Price: 400, Stock: 10}
+	cfg := config.OrderConfig{MaxTotal: 1000, MaxTotalAction: MaxTotalActionReview}
+	ctx := context.Background()
+	buy := func(qty int) CreateOrderInput {
+		return CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: qty}}}
+	}
+
+	t.Run("under the threshold proceeds as pending", func(t *testing.T) {
+		uow := newFakeUnitOfWork(product)
+		publisher := &recordingPublisher{}
+		svc := NewService(uow, uow.orders, publisher, cfg, clock.Real(), zap.NewNop())
+
+		order, err := svc.Create(ctx, uuid.New(), buy(2))
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusPending, order.Status)
+		assert.Empty(t, publisher.events)
+	})
+
+	t.Run("over the threshold is held for review", func(t *testing.T) {
+		uow := newFakeUnitOfWork(product)
+		publisher := &recordingPublisher{}
+		svc := NewService(uow, uow.orders, publisher, cfg, clock.Real(), zap.NewNop())
+		userID := uuid.New()
+
+		order, err := svc.Create(ctx, userID, buy(3))
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusReviewRequired, order.Status)
+		require.Len(t, uow.orders.created, 1)
+		assert.Equal(t, domain.OrderStatusReviewRequired, uow.orders.created[0].Status)
+		assert.Equal(t, 7, uow.products.products[product.ID].Stock, "stock is reserved while under review")
+
+		require.Len(t, publisher.events, 1)
+		event, ok := publisher.events[0].(domain.OrderReviewRequiredEvent)
+		require.True(t, ok)
+		assert.Equal(t, order.ID, event.OrderID)
+		assert.Equal(t, userID, event.UserID)
+		assert.Equal(t, 1200.0, event.Total)
+		assert.Equal(t, 1000.0, event.MaxTotal)
+
+		expired, err := svc.ExpireStale(ctx, uuid.Nil, time.Nanosecond)
+		require.NoError(t, err)
+		assert.Zero(t, expired, "orders under review are not expired")
+	})
+
+	t.Run("reject action refuses the order", func(t *testing.T) {
+		uow := newFakeUnitOfWork(product)
+		reject := cfg
+		reject.MaxTotalAction = MaxTotalActionReject
+		svc := NewService(uow, uow.orders, nil, reject, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(ctx, uuid.New(), buy(3))
+		assert.ErrorIs(t, err, domain.ErrOrderTotalExceeded)
+		assert.Empty(t, uow.orders.created)
+	})
+}
+
+func TestService_Review(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Camera", Price: 400, Stock: 10}
+	cfg := config.OrderConfig{MaxTotal: 1000, MaxTotalAction: MaxTotalActionReview}
+	ctx := context.Background()
+	admin := uuid.New()
+	held := func(t *testing.T) (Service, *fakeUnitOfWork, *domain.Order) {
+		uow := newFakeUnitOfWork(product)
+		svc := NewService(uow, uow.orders, nil, cfg, clock.Real(), zap.NewNop())
+		order, err := svc.Create(ctx, uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 3}}})
+		require.NoError(t, err)
+		require.Equal(t, domain.OrderStatusReviewRequired, order.Status)
+		return svc, uow, order
+	}
+
+	t.Run("approve releases the order as pending", func(t *testing.T) {
+		svc, uow, order := held(t)
+
+		approved, err := svc.ApproveReview(ctx, admin, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusPending, approved.Status)
+		assert.Equal(t, domain.OrderStatusPending, uow.orders.created[0].Status)
+		assert.Equal(t, 7, uow.products.products[product.ID].Stock, "approved orders keep their stock")
+
+		last := uow.orders.history[len(uow.orders.history)-1]
+		assert.Equal(t, domain.OrderStatusReviewRequired, last.FromStatus)
+		assert.Equal(t, domain.OrderStatusPending, last.ToStatus)
+		require.NotNil(t, last.ActorID)
+		assert.Equal(t, admin, *last.ActorID)
+
+		expired, err := svc.ExpireStale(ctx, uuid.Nil, time.Minute)
+		require.NoError(t, err)
+		assert.Zero(t, expired, "expiry counts from the approval")
+
+		_, err = svc.ApproveReview(ctx, admin, order.ID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotUnderReview)
+	})
+
+	t.Run("reject cancels and restocks", func(t *testing.T) {
+		svc, uow, order := held(t)
+
+		rejected, err := svc.RejectReview(ctx, admin, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusCancelled, rejected.Status)
+		assert.Equal(t, domain.OrderStatusCancelled, uow.orders.created[0].Status)
+		assert.Equal(t, 10, uow.products.products[product.ID].Stock)
+
+		_, err = svc.RejectReview(ctx, admin, order.ID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotUnderReview, "stock is not returned twice")
+		assert.Equal(t, 10, uow.products.products[product.ID].Stock)
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		svc, _, _ := held(t)
+		_, err := svc.RejectReview(ctx, admin, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+}
+
+func TestService_Create_RequireVerifiedEmail(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 5}
+	uow := newFakeUnitOfWork(product)
+	unverified := domain.User{ID: uuid.New(), Email: "new@example.com"}
+	verified := domain.User{ID: uuid.New(), Email: "known@example.com", EmailVerified: true}
+	uow.users.users[unverified.ID] = unverified
+	uow.users.users[verified.ID] = verified
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{RequireVerifiedEmail: true}, clock.Real(), zap.NewNop())
+	input := CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}}
+
+	_, err := svc.Create(context.Background(), unverified.ID, input)
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+	assert.Equal(t, 5, uow.products.products[product.ID].Stock)
+
+	_, err = svc.Create(context.Background(), verified.ID, input)
+	require.NoError(t, err)
+	assert.Len(t, uow.orders.created, 1)
+}
+
+func TestService_Create_ForbidSelfPurchase(t *testing.T) {
+	seller := uuid.New()
+	own := domain.Product{ID: uuid.New(), Name: "Handmade Mug", Price: 12, Stock: 5, UserID: seller}
+	other := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 5, UserID: uuid.New()}
+	input := CreateOrderInput{Items: []OrderItemInput{{ProductID: other.ID, Quantity: 1}, {ProductID: own.ID, Quantity: 1}}}
+
+	t.Run("on blocks ordering your own product", func(t *testing.T) {
+		uow := newFakeUnitOfWork(own, other)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{ForbidSelfPurchase: true}, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(context.Background(), seller, input)
+		require.ErrorIs(t, err, domain.ErrSelfPurchase)
+		assert.Contains(t, err.Error(), "Handmade Mug")
+		assert.Empty(t, uow.orders.created)
+
+		_, err = svc.Create(context.Background(), uuid.New(), input)
+		assert.NoError(t, err, "other buyers are unaffected")
+	})
+
+	t.Run("off allows it", func(t *testing.T) {
+		uow := newFakeUnitOfWork(own, other)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+		_, err := svc.Create(context.Background(), seller, input)
+		require.NoError(t, err)
+		assert.Len(t, uow.orders.created, 1)
+	})
+}
+
+func TestService_Create_MaxOpenOrders(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 50}
+	uow := newFakeUnitOfWork(product)
+	userID := uuid.New()
+	// seed pending orders up to the limit, plus orders that do not count towards it
+	for i := 0; i < 2; i++ {
+		uow.orders.created = append(uow.orders.created, domain.Order{ID: uuid.New(), UserID: userID, Status: domain.OrderStatusPending})
+	}
+	uow.orders.created = append(uow.orders.created,
+		domain.Order{ID: uuid.New(), UserID: userID, Status: domain.OrderStatusCancelled},
+		domain.Order{ID: uuid.New(), UserID: uuid.New(), Status: domain.OrderStatusPending},
+	)
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{MaxOpenOrders: 3}, clock.Real(), zap.NewNop())
+	input := CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}}
+
+	_, err := svc.Create(context.Background(), userID, input)
+	require.NoError(t, err, "third pending order is within the limit")
+
+	_, err = svc.Create(context.Background(), userID, input)
+	require.ErrorIs(t, err, domain.ErrTooManyOpenOrders)
+	assert.Contains(t, err.Error(), "limit is 3")
+	assert.Equal(t, 49, uow.products.products[product.ID].Stock, "rejected order takes no stock")
+
+	_, err = svc.Create(context.Background(), uuid.New(), input)
+	assert.NoError(t, err, "other users are unaffected")
+}
+
+func TestService_Reorder(t *testing.T) {
+	lamp := domain.Product{ID: uuid.New(), Name: "Lamp", Price: 10, Stock: 10}
+	desk := domain.Product{ID: uuid.New(), Name: "Desk", Price: 100, Stock: 10}
+	ctx := context.Background()
+	userID := uuid.New()
+	setup := func(t *testing.T) (Service, *fakeUnitOfWork, *domain.Order) {
+		uow := newFakeUnitOfWork(lamp, desk)
+		svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+		original, err := svc.Create(ctx, userID, CreateOrderInput{
+			Description: "office",
+			Items: []OrderItemInput{
+				{ProductID: lamp.ID, Quantity: 2},
+				{ProductID: desk.ID, Quantity: 1},
+			},
+		})
+		require.NoError(t, err)
+		return svc, uow, original
+	}
+
+	t.Run("repeats the items at current prices", func(t *testing.T) {
+		svc, uow, original := setup(t)
+		changed := uow.products.products[desk.ID]
+		changed.Price = 120
+		uow.products.products[desk.ID] = changed
+
+		result, err := svc.Reorder(ctx, userID, original.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, original.ID, result.Order.ID)
+		assert.Equal(t, "office", result.Order.Description)
+		require.Len(t, result.Order.Items, 2)
+		assert.Equal(t, 140.0, result.Order.TotalPrice)
+		assert.Equal(t, 6, uow.products.products[lamp.ID].Stock)
+		assert.Equal(t, []PriceChange{{ProductID: desk.ID, ProductName: "Desk", PreviousPrice: 100, CurrentPrice: 120}}, result.PriceChanges)
+	})
+
+	t.Run("blocked by current stock", func(t *testing.T) {
+		svc, uow, original := setup(t)
+		low := uow.products.products[lamp.ID]
+		low.Stock = 1
+		uow.products.products[lamp.ID] = low
+
+		_, err := svc.Reorder(ctx, userID, original.ID)
+		assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+		assert.Len(t, uow.orders.created, 1, "no new order")
+	})
+
+	t.Run("another user's or a missing order", func(t *testing.T) {
+		svc, _, original := setup(t)
+		_, err := svc.Reorder(ctx, uuid.New(), original.ID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+		_, err = svc.Reorder(ctx, userID, uuid.New())
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+}
+
+func TestService_History_CreateThenCancel(t *testing.T) {
+	created := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+	buyer, admin := uuid.New(), uuid.New()
+	clk := clock.Fixed(created)
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{PendingTTL: 24 * time.Hour}, clk, zap.NewNop())
+
+	order, err := svc.Create(context.Background(), buyer, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 2}}})
+	require.NoError(t, err)
+
+	svc = NewService(uow, uow.orders, nil, config.OrderConfig{PendingTTL: 24 * time.Hour}, clock.Fixed(created.Add(48*time.Hour)), zap.NewNop())
+	cancelled, err := svc.ExpireStale(context.Background(), admin, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, cancelled)
+
+	history, err := svc.History(context.Background(), buyer, false, order.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, domain.OrderStatus(""), history[0].FromStatus)
+	assert.Equal(t, domain.OrderStatusPending, history[0].ToStatus)
+	require.NotNil(t, history[0].ActorID)
+	assert.Equal(t, buyer, *history[0].ActorID)
+	assert.Equal(t, created, history[0].CreatedAt)
+
+	assert.Equal(t, domain.OrderStatusPending, history[1].FromStatus)
+	assert.Equal(t, domain.OrderStatusCancelled, history[1].ToStatus)
+	require.NotNil(t, history[1].ActorID)
+	assert.Equal(t, admin, *history[1].ActorID)
+
+	t.Run("admin can read any order", func(t *testing.T) {
+		history, err := svc.History(context.Background(), admin, true, order.ID)
+		require.NoError(t, err)
+		assert.Len(t, history, 2)
+	})
+
+	t.Run("other users cannot", func(t *testing.T) {
+		_, err := svc.History(context.Background(), uuid.New(), false, order.ID)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+}
+
+func TestService_Create_OrderNumbersUniqueUnderConcurrency(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 1000}
+	uow := newFakeUnitOfWork(product)
+	clk := clock.Fixed(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clk, zap.NewNop())
+
+	const buyers = 50
+	numbers := make([]string, buyers)
+	var wg sync.WaitGroup
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			order, err := svc.Create(context.Background(), uuid.New(), CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+			if assert.NoError(t, err) {
+				numbers[i] = order.OrderNumber
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, buyers)
+	for _, number := range numbers {
+		assert.Regexp(t, `^ORD-2024-\d{6}$`, number)
+		assert.False(t, seen[number], "duplicate order number %s", number)
+		seen[number] = true
+	}
+	assert.Len(t, seen, buyers)
+}
+
+func TestService_GetByNumber(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+	buyer := uuid.New()
+	clk := clock.Fixed(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{NumberPrefix: "SHOP"}, clk, zap.NewNop())
+
+	order, err := svc.Create(context.Background(), buyer, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 2}}})
+	require.NoError(t, err)
+	require.Equal(t, "SHOP-2024-000001", order.OrderNumber)
+
+	t.Run("owner, ignoring case and spaces", func(t *testing.T) {
+		found, err := svc.GetByNumber(context.Background(), buyer, false, " shop-2024-000001 ")
+		require.NoError(t, err)
+		assert.Equal(t, order.ID, found.ID)
+		assert.Len(t, found.Items, 1)
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		found, err := svc.GetByNumber(context.Background(), uuid.New(), true, order.OrderNumber)
+		require.NoError(t, err)
+		assert.Equal(t, order.ID, found.ID)
+	})
+
+	t.Run("other users get not found", func(t *testing.T) {
+		_, err := svc.GetByNumber(context.Background(), uuid.New(), false, order.OrderNumber)
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	t.Run("unknown number", func(t *testing.T) {
+		_, err := svc.GetByNumber(context.Background(), buyer, false, "SHOP-2024-999999")
+		assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+	})
+
+	t.Run("lowercase prefix", func(t *testing.T) {
+		lower := NewService(uow, uow.orders, nil, config.OrderConfig{NumberPrefix: "shop"}, clk, zap.NewNop())
+		placed, err := lower.Create(context.Background(), buyer, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+		require.NoError(t, err)
+		assert.Equal(t, "SHOP-2024-000002", placed.OrderNumber)
+
+		found, err := lower.GetByNumber(context.Background(), buyer, false, placed.OrderNumber)
+		require.NoError(t, err)
+		assert.Equal(t, placed.ID, found.ID)
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Mug", Price: 8, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+	buyer := uuid.New()
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+
+	order, err := svc.Create(context.Background(), buyer, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+	require.NoError(t, err)
+
+	found, err := svc.Get(context.Background(), buyer, false, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.ID, found.ID)
+
+	found, err = svc.Get(context.Background(), uuid.New(), true, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.ID, found.ID)
+
+	_, err = svc.Get(context.Background(), uuid.New(), false, order.ID)
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestService_Archive(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Name: "Teapot", Price: 18, Stock: 10}
+	uow := newFakeUnitOfWork(product)
+	svc := NewService(uow, uow.orders, nil, config.OrderConfig{}, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+	buyer := uuid.New()
+
+	place := func() *domain.Order {
+		order, err := svc.Create(ctx, buyer, CreateOrderInput{Items: []OrderItemInput{{ProductID: product.ID, Quantity: 1}}})
+		require.NoError(t, err)
+		return order
+	}
+	open, done := place(), place()
+	require.NoError(t, uow.orders.UpdateStatus(ctx, done.ID, domain.OrderStatusCompleted))
+
+	_, err := svc.Archive(ctx, open.ID)
+	require.ErrorIs(t, err, domain.ErrOrderNotArchivable, "pending orders still hold stock")
+
+	archived, err := svc.Archive(ctx, done.ID)
+	require.NoError(t, err)
+	require.NotNil(t, archived.ArchivedAt)
+
+	again, err := svc.Archive(ctx, done.ID)
+	require.NoError(t, err, "archiving twice is a no-op")
+	assert.Equal(t, archived.ArchivedAt, again.ArchivedAt)
+
+	t.Run("hidden by default", func(t *testing.T) {
+		orders, err := svc.ListForUser(ctx, buyer, "", false)
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		assert.Equal(t, open.ID, orders[0].ID)
+	})
+
+	t.Run("visible with include archived", func(t *testing.T) {
+		orders, err := svc.ListForUser(ctx, buyer, "", true)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		for _, o := range orders {
+			if o.ID == done.ID {
+				assert.NotNil(t, o.ArchivedAt)
+				assert.Len(t, o.Items, 1, "items stay with the archived order")
+			}
+		}
+	})
+
+	_, err = svc.Archive(ctx, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
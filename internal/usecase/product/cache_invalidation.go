@@ -0,0 +1,161 @@
+package product
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	memcache "github.com/minilik/ecommerce/pkg/cache"
+)
+
+// Cached product details include stock and images, which other services change without going
+// through this package. The wrappers below drop the cached details of every product such a write
+// touched; a nil cache returns the wrapped value unchanged.
+
+// InvalidatingUnitOfWork evicts the products a transaction changed through Products() once it
+// commits. Evicting earlier would let a concurrent read cache the old row again.
+func InvalidatingUnitOfWork(uow repository.UnitOfWork, cache memcache.Cache, logger *zap.Logger) repository.UnitOfWork {
+	if cache == nil {
+		return uow
+	}
+	return &invalidatingUnitOfWork{uow: uow, evict: evictor(cache, logger)}
+}
+
+// InvalidatingAssets evicts a product whenever its images are added, edited or deleted.
+func InvalidatingAssets(repo repository.ProductAssetRepository, cache memcache.Cache, logger *zap.Logger) repository.ProductAssetRepository {
+	if cache == nil {
+		return repo
+	}
+	return &invalidatingAssets{ProductAssetRepository: repo, evict: evictor(cache, logger)}
+}
+
+func evictor(cache memcache.Cache, logger *zap.Logger) func(ctx context.Context, ids []uuid.UUID) {
+	s := &service{cache: cache, logger: logger}
+	return func(ctx context.Context, ids []uuid.UUID) {
+		for _, id := range ids {
+			s.invalidateProduct(ctx, id)
+		}
+	}
+}
+
+type invalidatingUnitOfWork struct {
+	uow   repository.UnitOfWork
+	evict func(ctx context.Context, ids []uuid.UUID)
+}
+
+func (u *invalidatingUnitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
+	products := &recordingProducts{}
+	err := u.uow.Execute(ctx, func(tx repository.RepositoryProvider) error {
+		products.ProductRepository = tx.Products()
+		return fn(&invalidatingProvider{RepositoryProvider: tx, products: products})
+	})
+	if err == nil {
+		u.evict(ctx, products.changed())
+	}
+	return err
+}
+
+type invalidatingProvider struct {
+	repository.RepositoryProvider
+	products *recordingProducts
+}
+
+func (p *invalidatingProvider) Products() repository.ProductRepository {
+	return p.products
+}
+
+// recordingProducts notes the products written through it. Retried transactions record into the
+// same set, which at worst evicts a product that did not change.
+type recordingProducts struct {
+	repository.ProductRepository
+	mu  sync.Mutex
+	ids map[uuid.UUID]struct{}
+}
+
+func (r *recordingProducts) record(ids ...uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids == nil {
+		r.ids = make(map[uuid.UUID]struct{}, len(ids))
+	}
+	for _, id := range ids {
+		r.ids[id] = struct{}{}
+	}
+}
+
+func (r *recordingProducts) changed() []uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]uuid.UUID, 0, len(r.ids))
+	for id := range r.ids {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (r *recordingProducts) Update(ctx context.Context, product *domain.Product) error {
+	r.record(product.ID)
+	return r.ProductRepository.Update(ctx, product)
+}
+
+func (r *recordingProducts) Delete(ctx context.Context, id uuid.UUID) error {
+	r.record(id)
+	return r.ProductRepository.Delete(ctx, id)
+}
+
+func (r *recordingProducts) DecrementStockBatch(ctx context.Context, quantities map[uuid.UUID]int) ([]uuid.UUID, error) {
+	for id := range quantities {
+		r.record(id)
+	}
+	return r.ProductRepository.DecrementStockBatch(ctx, quantities)
+}
+
+func (r *recordingProducts) IncrementStock(ctx context.Context, id uuid.UUID, qty int) error {
+	r.record(id)
+	return r.ProductRepository.IncrementStock(ctx, id, qty)
+}
+
+type invalidatingAssets struct {
+	repository.ProductAssetRepository
+	evict func(ctx context.Context, ids []uuid.UUID)
+}
+
+func (r *invalidatingAssets) AddMany(ctx context.Context, assets []domain.ProductAsset) error {
+	if err := r.ProductAssetRepository.AddMany(ctx, assets); err != nil {
+		return err
+	}
+	seen := make(map[uuid.UUID]struct{}, 1)
+	ids := make([]uuid.UUID, 0, 1)
+	for _, asset := range assets {
+		if _, ok := seen[asset.ProductID]; !ok {
+			seen[asset.ProductID] = struct{}{}
+			ids = append(ids, asset.ProductID)
+		}
+	}
+	r.evict(ctx, ids)
+	return nil
+}
+
+func (r *invalidatingAssets) UpdateMetadata(ctx context.Context, image domain.ProductImage) error {
+	if err := r.ProductAssetRepository.UpdateMetadata(ctx, image); err != nil {
+		return err
+	}
+	r.evict(ctx, []uuid.UUID{image.ProductID})
+	return nil
+}
+
+func (r *invalidatingAssets) DeleteByIDs(ctx context.Context, productID uuid.UUID, assetType domain.AssetType, ids []uuid.UUID) ([]domain.ProductAsset, error) {
+	deleted, err := r.ProductAssetRepository.DeleteByIDs(ctx, productID, assetType, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) > 0 {
+		r.evict(ctx, []uuid.UUID{productID})
+	}
+	return deleted, nil
+}
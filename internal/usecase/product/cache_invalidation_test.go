@@ -0,0 +1,133 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	memcache "github.com/minilik/ecommerce/pkg/cache"
+)
+
+// stockRepo accepts every stock write; it stands in for the transaction's product repository.
+type stockRepo struct {
+	repository.ProductRepository
+}
+
+func (stockRepo) DecrementStockBatch(ctx context.Context, quantities map[uuid.UUID]int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (stockRepo) IncrementStock(ctx context.Context, id uuid.UUID, qty int) error { return nil }
+
+type stockUnitOfWork struct {
+	repository.RepositoryProvider
+}
+
+func (u stockUnitOfWork) Execute(ctx context.Context, fn func(tx repository.RepositoryProvider) error) error {
+	return fn(u)
+}
+
+func (stockUnitOfWork) Products() repository.ProductRepository { return stockRepo{} }
+
+func TestInvalidatingUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	changed, untouched := uuid.New(), uuid.New()
+	setup := func() (*memcache.MemoryCache, repository.UnitOfWork) {
+		c := memcache.NewMemoryCache(time.Minute, 10)
+		c.Set(productCacheKey(changed), domain.Product{ID: changed, Stock: 5})
+		c.Set(productCacheKey(untouched), domain.Product{ID: untouched, Stock: 5})
+		return c, InvalidatingUnitOfWork(stockUnitOfWork{}, memcache.Memory(c), zap.NewNop())
+	}
+
+	t.Run("evicts changed products after commit", func(t *testing.T) {
+		c, uow := setup()
+		err := uow.Execute(ctx, func(tx repository.RepositoryProvider) error {
+			_, err := tx.Products().DecrementStockBatch(ctx, map[uuid.UUID]int{changed: 1})
+			require.NoError(t, err)
+			_, cached := c.Get(productCacheKey(changed))
+			assert.True(t, cached, "nothing is evicted before the commit")
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, cached := c.Get(productCacheKey(changed))
+		assert.False(t, cached)
+		_, cached = c.Get(productCacheKey(untouched))
+		assert.True(t, cached)
+	})
+
+	t.Run("keeps entries when the transaction fails", func(t *testing.T) {
+		c, uow := setup()
+		err := uow.Execute(ctx, func(tx repository.RepositoryProvider) error {
+			require.NoError(t, tx.Products().IncrementStock(ctx, changed, 1))
+			return errors.New("rolled back")
+		})
+		require.Error(t, err)
+
+		_, cached := c.Get(productCacheKey(changed))
+		assert.True(t, cached)
+	})
+
+	t.Run("no cache leaves the unit of work alone", func(t *testing.T) {
+		uow := stockUnitOfWork{}
+		assert.Equal(t, repository.UnitOfWork(uow), InvalidatingUnitOfWork(uow, nil, zap.NewNop()))
+	})
+}
+
+func TestInvalidatingAssets(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	image := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://example.com/a.jpg"}
+	setup := func() (*memcache.MemoryCache, *fakeImageRepo, repository.ProductAssetRepository) {
+		c := memcache.NewMemoryCache(time.Minute, 10)
+		c.Set(productCacheKey(productID), domain.Product{ID: productID})
+		repo := &fakeImageRepo{byProduct: map[uuid.UUID][]domain.ProductAsset{productID: {image}}}
+		return c, repo, InvalidatingAssets(repo, memcache.Memory(c), zap.NewNop())
+	}
+	cached := func(c *memcache.MemoryCache) bool {
+		_, ok := c.Get(productCacheKey(productID))
+		return ok
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		c, repo, assets := setup()
+		require.NoError(t, assets.AddMany(ctx, []domain.ProductAsset{{ID: uuid.New(), ProductID: productID}}))
+		assert.Equal(t, 1, repo.addCalls)
+		assert.False(t, cached(c))
+	})
+
+	t.Run("metadata", func(t *testing.T) {
+		c, _, assets := setup()
+		edited := image
+		edited.AltText = "front"
+		require.NoError(t, assets.UpdateMetadata(ctx, edited))
+		assert.False(t, cached(c))
+
+		c, _, assets = setup()
+		missing := edited
+		missing.ID = uuid.New()
+		assert.ErrorIs(t, assets.UpdateMetadata(ctx, missing), domain.ErrImageNotFound)
+		assert.True(t, cached(c), "a failed edit keeps the entry")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, _, assets := setup()
+		deleted, err := assets.DeleteByIDs(ctx, productID, domain.AssetTypeImage, []uuid.UUID{uuid.New()})
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+		assert.True(t, cached(c), "nothing deleted, nothing evicted")
+
+		deleted, err = assets.DeleteByIDs(ctx, productID, domain.AssetTypeImage, []uuid.UUID{image.ID})
+		require.NoError(t, err)
+		assert.Len(t, deleted, 1)
+		assert.False(t, cached(c))
+	})
+}
@@ -1,11 +1,21 @@
 package product
 
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
 type CreateProductInput struct {
 	Name        string  `json:"name" binding:"required"`
 	Description string  `json:"description" binding:"required"`
 	Price       float64 `json:"price" binding:"required"`
 	Stock       int     `json:"stock" binding:"required"`
 	Category    string  `json:"category" binding:"required"`
+	ExternalID  string  `json:"externalId"`
 }
 
 type UpdateProductInput struct {
@@ -16,8 +26,113 @@ type UpdateProductInput struct {
 	Category    *string  `json:"category"`
 }
 
+// ListProductsInput pages through products. With SkipCount the matching products are not counted:
+// the returned total is instead the number of products up to the end of this page, plus one when
+// another page follows, so ListPage.HasNext still tells whether there is a next page.
 type ListProductsInput struct {
-	Search   string
+	Search    string
+	Sort      string
+	Page      int
+	PageSize  int
+	SkipCount bool
+	// CreatedFrom and CreatedTo keep products created in that range, both inclusive; a zero time
+	// leaves that side open.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// ListPage is the page a product list returned. Page and PageSize are the values actually used,
+// after the defaults and the 100-product cap, and Total counts the matching products (see
+// ListProductsInput.SkipCount).
+type ListPage struct {
 	Page     int
 	PageSize int
+	Total    int64
+}
+
+// HasNext reports whether another page follows this one.
+func (p ListPage) HasNext() bool {
+	return p.Total > int64(p.Page)*int64(p.PageSize)
+}
+
+// SaveViewInput creates or replaces a saved product view.
+type SaveViewInput struct {
+	Name       string    `json:"name" binding:"required"`
+	Search     string    `json:"search"`
+	CategoryID uuid.UUID `json:"categoryId"`
+	Sort       string    `json:"sort" example:"price_asc"`
+	PageSize   int       `json:"pageSize"`
+}
+
+type BatchGetInput struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+type BatchGetResult struct {
+	Products []domain.Product `json:"products"`
+	Missing  []uuid.UUID      `json:"missing"`
+}
+
+// AvailabilityItemInput is one cart line to check against current stock.
+type AvailabilityItemInput struct {
+	ProductID uuid.UUID `json:"productId"`
+	Quantity  int       `json:"quantity"`
+}
+
+// ItemAvailability reports whether a cart line can be fulfilled right now. Available is the
+// product's current stock (0 for an unknown product).
+type ItemAvailability struct {
+	ProductID uuid.UUID `json:"productId"`
+	Requested int       `json:"requested"`
+	Available int       `json:"available"`
+	InStock   bool      `json:"inStock"`
+	Found     bool      `json:"found"`
+}
+
+type AvailabilityResult struct {
+	Items        []ItemAvailability `json:"items"`
+	AllAvailable bool               `json:"allAvailable"`
+}
+
+// BulkImageUpload is a single file targeted at a product in a bulk upload.
+type BulkImageUpload struct {
+	ProductID uuid.UUID
+	File      *multipart.FileHeader
+}
+
+// ImageMetadata is the descriptive text stored with an uploaded image.
+type ImageMetadata struct {
+	AltText string
+	Caption string
+}
+
+// UpdateImageInput edits an image's metadata; nil fields are left unchanged.
+type UpdateImageInput struct {
+	AltText *string `json:"altText"`
+	Caption *string `json:"caption"`
+}
+
+// DeleteImagesInput names the images to delete from one product.
+type DeleteImagesInput struct {
+	IDs []uuid.UUID `json:"ids" binding:"required"`
+}
+
+// DeleteImagesResult reports a bulk image delete. Skipped lists the requested ids that are not
+// images of the product; they are ignored rather than failing the request.
+type DeleteImagesResult struct {
+	Deleted int         `json:"deleted"`
+	Skipped []uuid.UUID `json:"skipped"`
+}
+
+// SignedImageURL is a time-limited delivery URL for one stored product image.
+type SignedImageURL struct {
+	ImageID   uuid.UUID `json:"imageId"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type BulkUploadResult struct {
+	ProductID uuid.UUID             `json:"productId"`
+	Images    []domain.ProductImage `json:"images,omitempty"`
+	Error     string                `json:"error,omitempty"`
 }
@@ -2,105 +2,613 @@ package product
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/minilik/ecommerce/internal/domain"
 	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
 	"github.com/minilik/ecommerce/pkg/cloudinary"
 )
 
+// ImageService manages the files attached to products: images and, kept apart from them, documents
+// such as spec sheets and manuals.
 type ImageService interface {
-	UploadImages(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader) ([]domain.ProductImage, error)
+	// UploadImages stores files for a product. meta is aligned to files by index and may be shorter.
+	// When an upload or the insert fails, the files already sent to Cloudinary are removed again.
+	UploadImages(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader, meta []ImageMetadata) ([]domain.ProductImage, error)
+	UploadBulk(ctx context.Context, uploads []BulkImageUpload) ([]BulkUploadResult, error)
 	ListImages(ctx context.Context, productID uuid.UUID) ([]domain.ProductImage, error)
+	UpdateImage(ctx context.Context, productID, imageID uuid.UUID, input UpdateImageInput) (*domain.ProductImage, error)
+	// DeleteImages removes the product's images among ids; ids of other products are skipped.
+	DeleteImages(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) (*DeleteImagesResult, error)
+	// SignedURLs returns time-limited signed delivery URLs for a product's stored images.
+	SignedURLs(ctx context.Context, productID uuid.UUID) ([]SignedImageURL, error)
+	// UploadDocuments stores non-image files for a product. They never appear among its images.
+	UploadDocuments(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader) ([]domain.ProductAsset, error)
+	// ListDocuments returns a product's documents, oldest first.
+	ListDocuments(ctx context.Context, productID uuid.UUID) ([]domain.ProductAsset, error)
+	// Enabled reports whether uploads are possible, i.e. a Cloudinary uploader is configured.
+	Enabled() bool
 }
 
+// MaxImagesPerProduct caps the number of images stored for a single product.
+const MaxImagesPerProduct = 4
+
+// MaxDocumentsPerProduct caps the number of documents stored for a single product.
+const MaxDocumentsPerProduct = 10
+
+// MaxImageAltTextLength and MaxImageCaptionLength cap image metadata in characters.
+const (
+	MaxImageAltTextLength = 250
+	MaxImageCaptionLength = 500
+)
+
+// DefaultUploadConcurrency is the number of parallel uploads used when none is configured.
+const DefaultUploadConcurrency = 2
+
+// DefaultSignedURLTTL is how long signed delivery URLs stay valid when no TTL is configured.
+const DefaultSignedURLTTL = time.Hour
+
 type imageService struct {
-	imagesRepo repository.ProductImageRepository
-	uploader   *cloudinary.Client
-	logger     *zap.Logger
-	now        func() time.Time
+	assets       repository.ProductAssetRepository
+	uploader     *cloudinary.Client
+	allowedExts  map[string]struct{}
+	documentExts map[string]struct{}
+	concurrency  int
+	// maxUploadBytes caps the combined size of the files of one request; 0 disables the cap.
+	maxUploadBytes int64
+	// dedup skips uploading images whose content hash the product already has.
+	dedup     bool
+	folders   *UploadFolders
+	signedTTL time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
 }
 
-func NewImageService(repo repository.ProductImageRepository, uploader *cloudinary.Client, logger *zap.Logger) ImageService {
+// NewImageService builds the image service; an empty allowedExts falls back to DefaultImageExtensions
+// and an empty documentExts to DefaultDocumentExtensions. concurrency bounds parallel uploads per
+// request and is raised to 1 when lower. maxUploadBytes caps the total size of the files of one
+// request, 0 disables it. dedup makes image uploads return the product's stored image for a file
+// with the same bytes instead of uploading it again. A nil folders uploads everything into the
+// uploader's own Folder. signedTTL <= 0 uses DefaultSignedURLTTL.
+func NewImageService(repo repository.ProductAssetRepository, uploader *cloudinary.Client, allowedExts, documentExts []string, concurrency int, maxUploadBytes int64, dedup bool, folders *UploadFolders, signedTTL time.Duration, clk clock.Clock, logger *zap.Logger) ImageService {
+	if concurrency < 1 {
+		logger.Warn("invalid upload concurrency, using 1", zap.Int("configured", concurrency))
+		concurrency = 1
+	}
+	if signedTTL <= 0 {
+		signedTTL = DefaultSignedURLTTL
+	}
 	return &imageService{
-		imagesRepo: repo,
-		uploader:   uploader,
-		logger:     logger,
-		now:        time.Now,
+		assets:         repo,
+		uploader:       uploader,
+		allowedExts:    normalizeExtensions(allowedExts, DefaultImageExtensions),
+		documentExts:   normalizeExtensions(documentExts, DefaultDocumentExtensions),
+		concurrency:    concurrency,
+		maxUploadBytes: maxUploadBytes,
+		dedup:          dedup,
+		folders:        folders,
+		signedTTL:      signedTTL,
+		logger:         logger,
+		now:            clk.Now,
 	}
 }
 
-func (s *imageService) UploadImages(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader) ([]domain.ProductImage, error) {
+func (s *imageService) UploadImages(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader, meta []ImageMetadata) ([]domain.ProductImage, error) {
+	// checked up front: with dedup, a request of only known files would otherwise never reach the uploader
+	if !s.Enabled() {
+		return nil, domain.ErrImagesDisabled
+	}
 	if len(files) == 0 {
-		return nil, fmt.Errorf("no files provided")
+		return nil, domain.NewValidationError("no files provided")
 	}
-	if len(files) > 4 {
-		return nil, fmt.Errorf("maximum 4 images allowed per request")
+	if len(files) > MaxImagesPerProduct {
+		return nil, domain.NewValidationError("maximum %d images allowed per request", MaxImagesPerProduct)
 	}
-	current, err := s.imagesRepo.CountByProduct(ctx, productID)
+	if len(meta) > len(files) {
+		return nil, domain.NewValidationError("got metadata for %d images but only %d files", len(meta), len(files))
+	}
+	metadata := make([]ImageMetadata, len(meta))
+	for i, m := range meta {
+		normalized, err := normalizeImageMetadata(m.AltText, m.Caption)
+		if err != nil {
+			return nil, err
+		}
+		metadata[i] = normalized
+	}
+	if err := s.checkTotalSize(files); err != nil {
+		return nil, err
+	}
+	if err := s.validateImageFiles(files); err != nil {
+		return nil, err
+	}
+
+	images, added, err := s.uploadImageFiles(ctx, productID, files, metadata)
 	if err != nil {
 		return nil, err
 	}
-	if current+int64(len(files)) > 4 {
-		return nil, fmt.Errorf("upload would exceed limit of 4 images per product")
+	if err := s.assets.AddMany(ctx, added); err != nil {
+		s.destroyRemote(ctx, added)
+		return nil, err
+	}
+	return images, nil
+}
+
+// UploadBulk routes each file to its target product. Products that do not exist are reported
+// before anything is uploaded. Each product is then uploaded and stored on its own: one whose
+// batch would exceed the per-product limit, or whose upload or insert fails, is reported in its
+// result and skipped, and the files it already sent to Cloudinary are removed again.
+func (s *imageService) UploadBulk(ctx context.Context, uploads []BulkImageUpload) ([]BulkUploadResult, error) {
+	if !s.Enabled() {
+		return nil, domain.ErrImagesDisabled
+	}
+	if len(uploads) == 0 {
+		return nil, domain.NewValidationError("no files provided")
+	}
+	// the cap is on the whole request, so an oversized batch is refused before any product
+	files := make([]*multipart.FileHeader, len(uploads))
+	for i, u := range uploads {
+		files[i] = u.File
+	}
+	if err := s.checkTotalSize(files); err != nil {
+		return nil, err
+	}
+
+	// group files per product, keeping first-seen product order for the response
+	order := make([]uuid.UUID, 0)
+	grouped := make(map[uuid.UUID][]*multipart.FileHeader)
+	for _, u := range uploads {
+		if _, ok := grouped[u.ProductID]; !ok {
+			order = append(order, u.ProductID)
+		}
+		grouped[u.ProductID] = append(grouped[u.ProductID], u.File)
 	}
 
-	var uploaded []domain.ProductImage
-	for _, fh := range files {
-		src, err := fh.Open()
+	existing, err := s.assets.ExistingProducts(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkUploadResult, 0, len(order))
+	for _, productID := range order {
+		files := grouped[productID]
+		result := BulkUploadResult{ProductID: productID}
+
+		if !existing[productID] {
+			result.Error = domain.ErrProductNotFound.Error()
+			results = append(results, result)
+			continue
+		}
+		if err := s.validateImageFiles(files); err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		images, added, err := s.uploadImageFiles(ctx, productID, files, nil)
 		if err != nil {
-			return nil, fmt.Errorf("open file %s: %w", fh.Filename, err)
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if err := s.assets.AddMany(ctx, added); err != nil {
+			s.logger.Error("store bulk uploaded images failed", zap.String("product_id", productID.String()), zap.Error(err))
+			s.destroyRemote(ctx, added)
+			result.Error = "failed to store images"
+			results = append(results, result)
+			continue
 		}
 
-		filename := safeFilename(fh.Filename)
-		var url string
-		var uploadErr error
+		result.Images = images
+		results = append(results, result)
+	}
+	return results, nil
+}
 
-		// Prefer signed upload when API key/secret are configured but unsigned / unauthenticated for worst case
-		if s.uploader != nil && s.uploader.APIKey != "" && s.uploader.APISecret != "" {
-			url, uploadErr = s.uploader.UploadSigned(ctx, src, filename, nil)
-		} else if s.uploader != nil {
-			url, uploadErr = s.uploader.UploadUnsigned(ctx, src, filename)
-		} else {
-			src.Close()
-			return nil, fmt.Errorf("cloudinary uploader not configured")
+// uploadImageFiles uploads a product's validated image files, applying metadata by file index. It
+// returns one image per file, in order, and separately the new images the caller must store. With
+// dedup enabled, a file with the bytes of an image the product already has yields that stored image
+// unchanged, and a file repeating an earlier file of the same call yields that file's image; neither
+// is uploaded or counted against MaxImagesPerProduct. When an upload fails, the files of the call
+// that did upload are removed from Cloudinary before the error is returned.
+func (s *imageService) uploadImageFiles(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader, metadata []ImageMetadata) ([]domain.ProductImage, []domain.ProductImage, error) {
+	images := make([]domain.ProductImage, len(files))
+	fresh := make([]int, 0, len(files))
+	var hashes []string
+	sameAs := make(map[int]int)
+	if s.dedup {
+		stored, err := s.assets.ListByProduct(ctx, productID, domain.AssetTypeImage)
+		if err != nil {
+			return nil, nil, err
+		}
+		byHash := make(map[string]domain.ProductImage, len(stored))
+		for _, img := range stored {
+			if img.ContentHash != "" {
+				byHash[img.ContentHash] = img
+			}
 		}
+		firstIndex := make(map[string]int)
+		hashes = make([]string, len(files))
+		for i, fh := range files {
+			hash, err := contentHash(fh)
+			if err != nil {
+				return nil, nil, err
+			}
+			hashes[i] = hash
+			if img, ok := byHash[hash]; ok {
+				images[i] = img
+				continue
+			}
+			if first, ok := firstIndex[hash]; ok {
+				sameAs[i] = first
+				continue
+			}
+			firstIndex[hash] = i
+			fresh = append(fresh, i)
+		}
+	} else {
+		for i := range files {
+			fresh = append(fresh, i)
+		}
+	}
+	if len(fresh) == 0 {
+		return images, nil, nil
+	}
 
-		src.Close()
+	if err := s.checkProductLimit(ctx, productID, len(fresh)); err != nil {
+		return nil, nil, err
+	}
+	toUpload := make([]*multipart.FileHeader, len(fresh))
+	for j, i := range fresh {
+		toUpload[j] = files[i]
+	}
+	added, err := s.uploadFiles(ctx, productID, domain.AssetTypeImage, toUpload)
+	if err != nil {
+		// the failure may be the request's own cancellation, which must not stop the cleanup
+		s.destroyRemote(context.WithoutCancel(ctx), added)
+		return nil, nil, err
+	}
+	for j, i := range fresh {
+		if hashes != nil {
+			added[j].ContentHash = hashes[i]
+		}
+		if i < len(metadata) {
+			added[j].AltText = metadata[i].AltText
+			added[j].Caption = metadata[i].Caption
+		}
+		images[i] = added[j]
+	}
+	for i, first := range sameAs {
+		images[i] = images[first]
+	}
+	return images, added, nil
+}
+
+// contentHash returns the hex sha256 of an uploaded file's bytes.
+func contentHash(fh *multipart.FileHeader) (string, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return "", fmt.Errorf("open file %s: %w", fh.Filename, err)
+	}
+	defer src.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, src); err != nil {
+		return "", fmt.Errorf("read file %s: %w", fh.Filename, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
 
-		if uploadErr != nil {
-			s.logger.Error("cloudinary upload failed",
-				zap.String("filename", filename),
-				zap.Error(uploadErr))
-			return nil, fmt.Errorf("upload %s failed: %w", filename, uploadErr)
+// checkProductLimit verifies that adding n images keeps the product within MaxImagesPerProduct.
+func (s *imageService) checkProductLimit(ctx context.Context, productID uuid.UUID, n int) error {
+	current, err := s.assets.CountByProduct(ctx, productID, domain.AssetTypeImage)
+	if err != nil {
+		return err
+	}
+	if current+int64(n) > MaxImagesPerProduct {
+		return domain.NewValidationError("upload would exceed limit of %d images per product", MaxImagesPerProduct)
+	}
+	return nil
+}
+
+// UploadDocuments validates documents against the document allowlist instead of the image checks and
+// stores them as raw Cloudinary assets, keeping each file's name for display.
+func (s *imageService) UploadDocuments(ctx context.Context, productID uuid.UUID, files []*multipart.FileHeader) ([]domain.ProductAsset, error) {
+	if len(files) == 0 {
+		return nil, domain.NewValidationError("no files provided")
+	}
+	existing, err := s.assets.ExistingProducts(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return nil, err
+	}
+	if !existing[productID] {
+		return nil, domain.ErrProductNotFound
+	}
+	if err := s.checkTotalSize(files); err != nil {
+		return nil, err
+	}
+	if err := s.validateDocumentFiles(files); err != nil {
+		return nil, err
+	}
+	current, err := s.assets.CountByProduct(ctx, productID, domain.AssetTypeDocument)
+	if err != nil {
+		return nil, err
+	}
+	if current+int64(len(files)) > MaxDocumentsPerProduct {
+		return nil, domain.NewValidationError("upload would exceed limit of %d documents per product", MaxDocumentsPerProduct)
+	}
+
+	uploaded, err := s.uploadFiles(ctx, productID, domain.AssetTypeDocument, files)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assets.AddMany(ctx, uploaded); err != nil {
+		return nil, err
+	}
+	return uploaded, nil
+}
+
+func (s *imageService) ListDocuments(ctx context.Context, productID uuid.UUID) ([]domain.ProductAsset, error) {
+	return s.assets.ListByProduct(ctx, productID, domain.AssetTypeDocument)
+}
+
+func (s *imageService) Enabled() bool {
+	return s.uploader != nil
+}
+
+// uploadFiles uploads files as assets of assetType with at most s.concurrency in flight, keeping the
+// input order in the result. The first failure cancels the uploads that have not started yet, and
+// the assets of the files that did upload are returned along with the error so the caller can
+// remove them.
+func (s *imageService) uploadFiles(ctx context.Context, productID uuid.UUID, assetType domain.AssetType, files []*multipart.FileHeader) ([]domain.ProductAsset, error) {
+	if !s.Enabled() {
+		return nil, domain.ErrImagesDisabled
+	}
+	var folder string
+	if s.folders != nil {
+		folder = s.folders.For(ctx, productID)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		urls     = make([]string, len(files))
+		slots    = make(chan struct{}, s.concurrency)
+	)
+	for i, fh := range files {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
 		}
 
-		uploaded = append(uploaded, domain.ProductImage{
+		wg.Add(1)
+		go func(i int, fh *multipart.FileHeader) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			url, err := s.uploadFile(ctx, fh, folder, assetType)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			urls[i] = url
+		}(i, fh)
+	}
+	wg.Wait()
+
+	uploaded := make([]domain.ProductAsset, 0, len(files))
+	for i, url := range urls {
+		if url == "" {
+			// failed or never started
+			continue
+		}
+		asset := domain.ProductAsset{
 			ID:        uuid.New(),
 			ProductID: productID,
+			Type:      assetType,
 			URL:       url,
 			CreatedAt: s.now(),
-		})
+		}
+		if assetType == domain.AssetTypeDocument {
+			asset.Filename = filepath.Base(files[i].Filename)
+		}
+		uploaded = append(uploaded, asset)
 	}
-
-	if len(uploaded) == 0 {
-		return nil, fmt.Errorf("no images uploaded")
+	if firstErr != nil {
+		return uploaded, firstErr
 	}
-	if err := s.imagesRepo.AddMany(ctx, uploaded); err != nil {
-		return nil, err
+	if err := ctx.Err(); err != nil {
+		return uploaded, err
 	}
 	return uploaded, nil
 }
 
+func (s *imageService) uploadFile(ctx context.Context, fh *multipart.FileHeader, folder string, assetType domain.AssetType) (string, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return "", fmt.Errorf("open file %s: %w", fh.Filename, err)
+	}
+	defer src.Close()
+
+	filename := safeFilename(fh.Filename)
+	var url string
+	var uploadErr error
+
+	// Prefer signed upload when API key/secret are configured but unsigned / unauthenticated for worst case
+	// documents go through Cloudinary's raw pipeline, which stores files without image processing
+	resourceType := cloudinary.ResourceImage
+	if assetType == domain.AssetTypeDocument {
+		resourceType = cloudinary.ResourceRaw
+	}
+	if s.uploader.APIKey != "" && s.uploader.APISecret != "" {
+		url, uploadErr = s.uploader.UploadSignedAs(ctx, resourceType, src, filename, folder, nil)
+	} else {
+		url, uploadErr = s.uploader.UploadUnsignedAs(ctx, resourceType, src, filename, folder)
+	}
+
+	if errors.Is(uploadErr, cloudinary.ErrCircuitOpen) {
+		return "", domain.ErrUploadsUnavailable
+	}
+	if uploadErr != nil {
+		s.logger.Error("cloudinary upload failed",
+			zap.String("filename", filename),
+			zap.Error(uploadErr))
+		return "", domain.NewAppError(http.StatusBadGateway, "upload_failed", fmt.Sprintf("upload %s failed", filename), uploadErr)
+	}
+	return url, nil
+}
+
 func (s *imageService) ListImages(ctx context.Context, productID uuid.UUID) ([]domain.ProductImage, error) {
-	return s.imagesRepo.ListByProduct(ctx, productID)
+	return s.assets.ListByProduct(ctx, productID, domain.AssetTypeImage)
+}
+
+func (s *imageService) UpdateImage(ctx context.Context, productID, imageID uuid.UUID, input UpdateImageInput) (*domain.ProductImage, error) {
+	img, err := s.assets.GetImage(ctx, productID, imageID)
+	if err != nil {
+		return nil, err
+	}
+	altText, caption := img.AltText, img.Caption
+	if input.AltText != nil {
+		altText = *input.AltText
+	}
+	if input.Caption != nil {
+		caption = *input.Caption
+	}
+	m, err := normalizeImageMetadata(altText, caption)
+	if err != nil {
+		return nil, err
+	}
+	img.AltText, img.Caption = m.AltText, m.Caption
+	if err := s.assets.UpdateMetadata(ctx, *img); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// maxDeleteImageIDs caps the ids of one bulk delete request.
+const maxDeleteImageIDs = 100
+
+// DeleteImages deletes the rows in one transaction and then the Cloudinary assets. The assets go
+// after the commit: a failed remote delete leaves an unused asset behind, which is harmless, while
+// the other order could leave rows pointing at deleted assets. Remote failures are only logged.
+func (s *imageService) DeleteImages(ctx context.Context, productID uuid.UUID, ids []uuid.UUID) (*DeleteImagesResult, error) {
+	if len(ids) == 0 {
+		return nil, domain.NewValidationError("ids must name at least one image")
+	}
+	if len(ids) > maxDeleteImageIDs {
+		return nil, domain.NewValidationError("at most %d image ids may be deleted at once", maxDeleteImageIDs)
+	}
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	deleted, err := s.assets.DeleteByIDs(ctx, productID, domain.AssetTypeImage, unique)
+	if err != nil {
+		return nil, err
+	}
+	removed := make(map[uuid.UUID]bool, len(deleted))
+	for _, img := range deleted {
+		removed[img.ID] = true
+	}
+	result := &DeleteImagesResult{Deleted: len(deleted), Skipped: []uuid.UUID{}}
+	for _, id := range unique {
+		if !removed[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+
+	s.destroyRemote(ctx, deleted)
+	return result, nil
+}
+
+// destroyRemote deletes the Cloudinary assets of deleted images, logging what it cannot delete.
+func (s *imageService) destroyRemote(ctx context.Context, deleted []domain.ProductImage) {
+	if len(deleted) == 0 {
+		return
+	}
+	if s.uploader == nil || s.uploader.APIKey == "" || s.uploader.APISecret == "" {
+		s.logger.Warn("deleted images left in cloudinary: api credentials not configured", zap.Int("count", len(deleted)))
+		return
+	}
+	for _, img := range deleted {
+		publicID, _, ok := cloudinary.PublicIDFromURL(img.URL)
+		if !ok {
+			s.logger.Warn("deleted image has no cloudinary public id", zap.String("image_id", img.ID.String()), zap.String("url", img.URL))
+			continue
+		}
+		if err := s.uploader.Destroy(ctx, publicID); err != nil {
+			s.logger.Warn("failed to delete image from cloudinary", zap.String("image_id", img.ID.String()), zap.String("public_id", publicID), zap.Error(err))
+		}
+	}
+}
+
+// normalizeImageMetadata trims the text and enforces the length caps.
+func normalizeImageMetadata(altText, caption string) (ImageMetadata, error) {
+	m := ImageMetadata{AltText: strings.TrimSpace(altText), Caption: strings.TrimSpace(caption)}
+	if n := utf8.RuneCountInString(m.AltText); n > MaxImageAltTextLength {
+		return m, domain.NewValidationError("alt text must be at most %d characters, got %d", MaxImageAltTextLength, n)
+	}
+	if n := utf8.RuneCountInString(m.Caption); n > MaxImageCaptionLength {
+		return m, domain.NewValidationError("caption must be at most %d characters, got %d", MaxImageCaptionLength, n)
+	}
+	return m, nil
+}
+
+func (s *imageService) SignedURLs(ctx context.Context, productID uuid.UUID) ([]SignedImageURL, error) {
+	if s.uploader == nil || s.uploader.APIKey == "" || s.uploader.APISecret == "" {
+		return nil, domain.NewAppError(http.StatusServiceUnavailable, "signing_unavailable", "signed image urls require cloudinary api credentials", nil)
+	}
+	images, err := s.assets.ListByProduct(ctx, productID, domain.AssetTypeImage)
+	if err != nil {
+		return nil, err
+	}
+
+	issuedAt := s.now()
+	expiresAt := issuedAt.Add(s.signedTTL)
+	signed := make([]SignedImageURL, 0, len(images))
+	for _, img := range images {
+		publicID, format, ok := cloudinary.PublicIDFromURL(img.URL)
+		if !ok {
+			s.logger.Warn("skip signing non-cloudinary image url",
+				zap.String("image_id", img.ID.String()),
+				zap.String("url", img.URL))
+			continue
+		}
+		url, err := s.uploader.SignedDownloadURL(publicID, format, issuedAt, s.signedTTL)
+		if err != nil {
+			return nil, err
+		}
+		signed = append(signed, SignedImageURL{ImageID: img.ID, URL: url, ExpiresAt: expiresAt})
+	}
+	return signed, nil
 }
 
 func safeFilename(name string) string {
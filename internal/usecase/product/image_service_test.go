@@ -0,0 +1,702 @@
+package product
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+	"github.com/minilik/ecommerce/pkg/cloudinary"
+)
+
+// fakeImageRepo keeps assets per product; an asset without a Type counts as an image. counts
+// holds the stored image count per product.
+type fakeImageRepo struct {
+	repository.ProductAssetRepository
+	counts    map[uuid.UUID]int64
+	byProduct map[uuid.UUID][]domain.ProductAsset
+	missing   map[uuid.UUID]bool
+	added     []domain.ProductAsset
+	addCalls  int
+}
+
+func isAssetType(a domain.ProductAsset, assetType domain.AssetType) bool {
+	return a.Type == assetType || (a.Type == "" && assetType == domain.AssetTypeImage)
+}
+
+func (r *fakeImageRepo) ListByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) ([]domain.ProductAsset, error) {
+	var out []domain.ProductAsset
+	for _, a := range r.byProduct[productID] {
+		if isAssetType(a, assetType) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeImageRepo) CountByProduct(ctx context.Context, productID uuid.UUID, assetType domain.AssetType) (int64, error) {
+	if assetType == domain.AssetTypeImage {
+		return r.counts[productID], nil
+	}
+	docs, _ := r.ListByProduct(ctx, productID, assetType)
+	return int64(len(docs)), nil
+}
+
+func (r *fakeImageRepo) GetImage(ctx context.Context, productID, imageID uuid.UUID) (*domain.ProductImage, error) {
+	for _, img := range r.byProduct[productID] {
+		if img.ID == imageID && isAssetType(img, domain.AssetTypeImage) {
+			return &img, nil
+		}
+	}
+	return nil, domain.ErrImageNotFound
+}
+
+func (r *fakeImageRepo) UpdateMetadata(ctx context.Context, image domain.ProductImage) error {
+	images := r.byProduct[image.ProductID]
+	for i := range images {
+		if images[i].ID == image.ID {
+			images[i].AltText, images[i].Caption = image.AltText, image.Caption
+			return nil
+		}
+	}
+	return domain.ErrImageNotFound
+}
+
+// ExistingProducts treats every product as existing unless it is listed in missing.
+func (r *fakeImageRepo) ExistingProducts(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		existing[id] = !r.missing[id]
+	}
+	return existing, nil
+}
+
+func (r *fakeImageRepo) AddMany(ctx context.Context, images []domain.ProductImage) error {
+	r.addCalls++
+	r.added = append(r.added, images...)
+	return nil
+}
+
+func (r *fakeImageRepo) DeleteByIDs(ctx context.Context, productID uuid.UUID, assetType domain.AssetType, ids []uuid.UUID) ([]domain.ProductAsset, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var kept, deleted []domain.ProductImage
+	for _, img := range r.byProduct[productID] {
+		if wanted[img.ID] && isAssetType(img, assetType) {
+			deleted = append(deleted, img)
+		} else {
+			kept = append(kept, img)
+		}
+	}
+	r.byProduct[productID] = kept
+	return deleted, nil
+}
+
+// stubTransport answers every Cloudinary upload with a fixed secure_url.
+type stubTransport struct{}
+
+func (stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"secure_url":"https://res.example.com/img.jpg"}`)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+// countingTransport records the peak number of uploads in flight at once.
+type countingTransport struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.inFlight++
+	if t.inFlight > t.peak {
+		t.peak = t.inFlight
+	}
+	t.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	t.mu.Lock()
+	t.inFlight--
+	t.mu.Unlock()
+	return stubTransport{}.RoundTrip(req)
+}
+
+var (
+	jpegBytes = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	pngBytes  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+)
+
+type testFile struct {
+	name    string
+	content []byte
+}
+
+func newFileHeaders(t *testing.T, n int) []*multipart.FileHeader {
+	t.Helper()
+	files := make([]testFile, n)
+	for i := range files {
+		files[i] = testFile{name: "photo.jpg", content: jpegBytes}
+	}
+	return newNamedFileHeaders(t, files...)
+}
+
+func newNamedFileHeaders(t *testing.T, files ...testFile) []*multipart.FileHeader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range files {
+		part, err := w.CreateFormFile("files", f.name)
+		require.NoError(t, err)
+		_, err = part.Write(f.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(&buf, w.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["files"]
+}
+
+func TestImageService_UploadBulk_MixedBatch(t *testing.T) {
+	withinLimit, overLimit, unknown := uuid.New(), uuid.New(), uuid.New()
+	repo := &fakeImageRepo{counts: map[uuid.UUID]int64{overLimit: 4}, missing: map[uuid.UUID]bool{unknown: true}}
+	calls := 0
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubTransport{}.RoundTrip(req)
+	})}}
+	svc := NewImageService(repo, uploader, nil, nil, 2, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+	files := newFileHeaders(t, 4)
+	uploads := []BulkImageUpload{
+		{ProductID: withinLimit, File: files[0]},
+		{ProductID: overLimit, File: files[1]},
+		{ProductID: withinLimit, File: files[2]},
+		{ProductID: unknown, File: files[3]},
+	}
+
+	results, err := svc.UploadBulk(context.Background(), uploads)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, withinLimit, results[0].ProductID)
+	assert.Empty(t, results[0].Error)
+	assert.Len(t, results[0].Images, 2)
+
+	assert.Equal(t, overLimit, results[1].ProductID)
+	assert.Contains(t, results[1].Error, "exceed limit")
+	assert.Empty(t, results[1].Images)
+
+	assert.Equal(t, unknown, results[2].ProductID)
+	assert.Equal(t, "product not found", results[2].Error)
+	assert.Empty(t, results[2].Images)
+
+	assert.Equal(t, 2, calls, "only the files of the product within its limit are uploaded")
+	assert.Equal(t, 1, repo.addCalls, "records are stored per successful product")
+	require.Len(t, repo.added, 2)
+	for _, img := range repo.added {
+		assert.Equal(t, withinLimit, img.ProductID)
+	}
+}
+
+// failingSecondUpload is a signed uploader whose first upload succeeds and whose later uploads fail;
+// destroyed collects the public ids it is asked to delete.
+func failingSecondUpload(t *testing.T, destroyed *[]string) *cloudinary.Client {
+	t.Helper()
+	uploads := 0
+	uploader := cloudinary.NewClient("demo", "key", "secret", "", "")
+	uploader.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/destroy") {
+			require.NoError(t, req.ParseForm())
+			*destroyed = append(*destroyed, req.PostForm.Get("public_id"))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"result":"ok"}`)), Header: make(http.Header)}, nil
+		}
+		uploads++
+		if uploads > 1 {
+			return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader("down")), Header: make(http.Header)}, nil
+		}
+		body := `{"secure_url":"https://res.cloudinary.com/demo/image/upload/v1/ecommerce/first.jpg"}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})}
+	return uploader
+}
+
+func TestImageService_UploadFailureRemovesUploadedFiles(t *testing.T) {
+	t.Run("upload images", func(t *testing.T) {
+		var destroyed []string
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, failingSecondUpload(t, &destroyed), nil, nil, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+		_, err := svc.UploadImages(context.Background(), uuid.New(), newFileHeaders(t, 2), nil)
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusBadGateway, appErr.Status)
+		assert.Empty(t, repo.added)
+		assert.Equal(t, []string{"ecommerce/first"}, destroyed, "the file uploaded before the failure is removed")
+	})
+
+	t.Run("upload bulk", func(t *testing.T) {
+		var destroyed []string
+		productID := uuid.New()
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, failingSecondUpload(t, &destroyed), nil, nil, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+		files := newFileHeaders(t, 2)
+		results, err := svc.UploadBulk(context.Background(), []BulkImageUpload{
+			{ProductID: productID, File: files[0]},
+			{ProductID: productID, File: files[1]},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Contains(t, results[0].Error, "upload")
+		assert.Empty(t, results[0].Images)
+		assert.Empty(t, repo.added)
+		assert.Equal(t, []string{"ecommerce/first"}, destroyed, "the file uploaded before the failure is removed")
+	})
+}
+
+func TestImageService_UploadImages_FileValidation(t *testing.T) {
+	repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: stubTransport{}}}
+	svc := NewImageService(repo, uploader, []string{"jpg", "png"}, nil, 2, 0, false, nil, 0, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("allowed extension with matching content", func(t *testing.T) {
+		files := newNamedFileHeaders(t, testFile{name: "a.JPG", content: jpegBytes}, testFile{name: "b.png", content: pngBytes})
+		uploaded, err := svc.UploadImages(ctx, uuid.New(), files, nil)
+		require.NoError(t, err)
+		assert.Len(t, uploaded, 2)
+	})
+
+	t.Run("disallowed extension", func(t *testing.T) {
+		files := newNamedFileHeaders(t, testFile{name: "logo.svg", content: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)})
+		_, err := svc.UploadImages(ctx, uuid.New(), files, nil)
+		require.ErrorIs(t, err, domain.ErrUnsupportedImage)
+		assert.Contains(t, err.Error(), "logo.svg")
+		assert.Contains(t, err.Error(), "not allowed")
+	})
+
+	t.Run("extension does not match content", func(t *testing.T) {
+		files := newNamedFileHeaders(t,
+			testFile{name: "ok.jpg", content: jpegBytes},
+			testFile{name: "renamed.png", content: jpegBytes},
+			testFile{name: "script.jpg", content: []byte("MZ\x90\x00 not an image")},
+		)
+		_, err := svc.UploadImages(ctx, uuid.New(), files, nil)
+		require.ErrorIs(t, err, domain.ErrUnsupportedImage)
+		assert.Contains(t, err.Error(), "renamed.png: content is image/jpeg, not image/png")
+		assert.Contains(t, err.Error(), "script.jpg")
+		assert.NotContains(t, err.Error(), "ok.jpg")
+	})
+}
+
+func TestImageService_UploadImages_Dedup(t *testing.T) {
+	calls := 0
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubTransport{}.RoundTrip(req)
+	})}}
+	ctx := context.Background()
+	productID := uuid.New()
+
+	t.Run("same bytes twice", func(t *testing.T) {
+		calls = 0
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, uploader, nil, nil, 2, 0, true, nil, 0, clock.Real(), zap.NewNop())
+
+		first, err := svc.UploadImages(ctx, productID, newNamedFileHeaders(t, testFile{name: "front.jpg", content: jpegBytes}), []ImageMetadata{{AltText: "Front"}})
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+		assert.Len(t, first[0].ContentHash, 64)
+		repo.byProduct = map[uuid.UUID][]domain.ProductAsset{productID: repo.added}
+
+		second, err := svc.UploadImages(ctx, productID, newNamedFileHeaders(t, testFile{name: "copy.jpg", content: jpegBytes}), []ImageMetadata{{AltText: "Copy"}})
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.Equal(t, first[0].ID, second[0].ID, "the stored image is returned")
+		assert.Equal(t, "Front", second[0].AltText, "the stored image is not changed")
+
+		assert.Len(t, repo.added, 1, "a single stored image")
+		assert.Equal(t, 1, calls, "the copy is not uploaded")
+	})
+
+	t.Run("repeated within one request", func(t *testing.T) {
+		calls = 0
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, uploader, nil, nil, 2, 0, true, nil, 0, clock.Real(), zap.NewNop())
+		other := append(append([]byte{}, jpegBytes...), 'x')
+
+		images, err := svc.UploadImages(ctx, productID, newNamedFileHeaders(t,
+			testFile{name: "a.jpg", content: jpegBytes}, testFile{name: "b.jpg", content: other}, testFile{name: "c.jpg", content: jpegBytes}), nil)
+		require.NoError(t, err)
+		require.Len(t, images, 3)
+		assert.Equal(t, images[0].ID, images[2].ID)
+		assert.NotEqual(t, images[0].ID, images[1].ID)
+		assert.Len(t, repo.added, 2)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		calls = 0
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, uploader, nil, nil, 2, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+		for i := 0; i < 2; i++ {
+			images, err := svc.UploadImages(ctx, productID, newNamedFileHeaders(t, testFile{name: "front.jpg", content: jpegBytes}), nil)
+			require.NoError(t, err)
+			assert.Empty(t, images[0].ContentHash)
+		}
+		assert.Len(t, repo.added, 2)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("only known files without an uploader", func(t *testing.T) {
+		hash, err := contentHash(newNamedFileHeaders(t, testFile{name: "front.jpg", content: jpegBytes})[0])
+		require.NoError(t, err)
+		stored := domain.ProductImage{ID: uuid.New(), ProductID: productID, Type: domain.AssetTypeImage, ContentHash: hash}
+		repo := &fakeImageRepo{byProduct: map[uuid.UUID][]domain.ProductAsset{productID: {stored}}}
+		svc := NewImageService(repo, nil, nil, nil, 2, 0, true, nil, 0, clock.Real(), zap.NewNop())
+
+		_, err = svc.UploadImages(ctx, productID, newNamedFileHeaders(t, testFile{name: "front.jpg", content: jpegBytes}), nil)
+		assert.ErrorIs(t, err, domain.ErrImagesDisabled)
+
+		_, err = svc.UploadBulk(ctx, []BulkImageUpload{{ProductID: productID, File: newNamedFileHeaders(t, testFile{name: "front.jpg", content: jpegBytes})[0]}})
+		assert.ErrorIs(t, err, domain.ErrImagesDisabled)
+	})
+}
+
+func TestImageService_UploadImages_TotalSize(t *testing.T) {
+	// four 30-byte images: each is well under the 100-byte cap, together they are over it
+	photo := append(append([]byte{}, jpegBytes...), bytes.Repeat([]byte("x"), 30-len(jpegBytes))...)
+	fourPhotos := func(t *testing.T) []*multipart.FileHeader {
+		return newNamedFileHeaders(t,
+			testFile{name: "a.jpg", content: photo}, testFile{name: "b.jpg", content: photo},
+			testFile{name: "c.jpg", content: photo}, testFile{name: "d.jpg", content: photo})
+	}
+	calls := 0
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return stubTransport{}.RoundTrip(req)
+	})}}
+	ctx := context.Background()
+
+	t.Run("over the cap", func(t *testing.T) {
+		repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+		svc := NewImageService(repo, uploader, nil, nil, 2, 100, false, nil, 0, clock.Real(), zap.NewNop())
+
+		_, err := svc.UploadImages(ctx, uuid.New(), fourPhotos(t), nil)
+		require.ErrorIs(t, err, domain.ErrUploadTooLarge)
+		assert.Contains(t, err.Error(), "4 files total 120 bytes")
+		assert.Equal(t, 0, calls, "nothing is uploaded")
+		assert.Empty(t, repo.added)
+	})
+
+	t.Run("bulk over the cap", func(t *testing.T) {
+		svc := NewImageService(&fakeImageRepo{counts: map[uuid.UUID]int64{}}, uploader, nil, nil, 2, 100, false, nil, 0, clock.Real(), zap.NewNop())
+		var uploads []BulkImageUpload
+		for _, fh := range fourPhotos(t) {
+			uploads = append(uploads, BulkImageUpload{ProductID: uuid.New(), File: fh})
+		}
+
+		_, err := svc.UploadBulk(ctx, uploads)
+		require.ErrorIs(t, err, domain.ErrUploadTooLarge)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("at the cap", func(t *testing.T) {
+		svc := NewImageService(&fakeImageRepo{counts: map[uuid.UUID]int64{}}, uploader, nil, nil, 2, 120, false, nil, 0, clock.Real(), zap.NewNop())
+
+		uploaded, err := svc.UploadImages(ctx, uuid.New(), fourPhotos(t), nil)
+		require.NoError(t, err)
+		assert.Len(t, uploaded, 4)
+	})
+
+	t.Run("no cap", func(t *testing.T) {
+		svc := NewImageService(&fakeImageRepo{counts: map[uuid.UUID]int64{}}, uploader, nil, nil, 2, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+		_, err := svc.UploadImages(ctx, uuid.New(), fourPhotos(t), nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestImageService_UploadImages_CircuitOpen(t *testing.T) {
+	repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+	calls := 0
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader("down")), Header: make(http.Header)}, nil
+	})}}
+	uploader.Breaker = cloudinary.NewBreaker(1, time.Minute, clock.Real())
+	svc := NewImageService(repo, uploader, nil, nil, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+	_, err := svc.UploadImages(context.Background(), uuid.New(), newFileHeaders(t, 1), nil)
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusBadGateway, appErr.Status, "the failure that opens the breaker is reported as is")
+
+	_, err = svc.UploadImages(context.Background(), uuid.New(), newFileHeaders(t, 1), nil)
+	assert.ErrorIs(t, err, domain.ErrUploadsUnavailable)
+	assert.Equal(t, 1, calls, "Cloudinary is not called while the breaker is open")
+	assert.Empty(t, repo.added)
+}
+
+func TestImageService_UploadImages_Metadata(t *testing.T) {
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: stubTransport{}}}
+	repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+	svc := NewImageService(repo, uploader, nil, nil, 2, 0, false, nil, 0, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("aligned to files", func(t *testing.T) {
+		uploaded, err := svc.UploadImages(ctx, uuid.New(), newFileHeaders(t, 3), []ImageMetadata{
+			{AltText: "  Red trail runner, side view ", Caption: "Side"},
+			{AltText: "Sole close-up"},
+		})
+		require.NoError(t, err)
+		require.Len(t, uploaded, 3)
+		assert.Equal(t, "Red trail runner, side view", uploaded[0].AltText)
+		assert.Equal(t, "Side", uploaded[0].Caption)
+		assert.Equal(t, "Sole close-up", uploaded[1].AltText)
+		assert.Empty(t, uploaded[2].AltText)
+		assert.Equal(t, uploaded, repo.added[len(repo.added)-3:], "metadata is stored with the images")
+	})
+
+	t.Run("more metadata than files", func(t *testing.T) {
+		_, err := svc.UploadImages(ctx, uuid.New(), newFileHeaders(t, 1), []ImageMetadata{{AltText: "a"}, {AltText: "b"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "metadata for 2 images")
+	})
+
+	t.Run("alt text too long", func(t *testing.T) {
+		_, err := svc.UploadImages(ctx, uuid.New(), newFileHeaders(t, 1), []ImageMetadata{{AltText: strings.Repeat("a", MaxImageAltTextLength+1)}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "alt text")
+	})
+}
+
+func TestImageService_UpdateImage(t *testing.T) {
+	productID := uuid.New()
+	img := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.example.com/img.jpg", Caption: "Front"}
+	repo := &fakeImageRepo{byProduct: map[uuid.UUID][]domain.ProductImage{productID: {img}}}
+	svc := NewImageService(repo, nil, nil, nil, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	updated, err := svc.UpdateImage(ctx, productID, img.ID, UpdateImageInput{AltText: str("Blue lamp on a desk")})
+	require.NoError(t, err)
+	assert.Equal(t, "Blue lamp on a desk", updated.AltText)
+	assert.Equal(t, "Front", updated.Caption, "omitted caption is kept")
+	assert.Equal(t, "Blue lamp on a desk", repo.byProduct[productID][0].AltText)
+
+	updated, err = svc.UpdateImage(ctx, productID, img.ID, UpdateImageInput{Caption: str("")})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Caption, "an empty string clears the caption")
+	assert.Equal(t, "Blue lamp on a desk", updated.AltText)
+
+	_, err = svc.UpdateImage(ctx, uuid.New(), img.ID, UpdateImageInput{AltText: str("x")})
+	assert.ErrorIs(t, err, domain.ErrImageNotFound, "image of another product")
+}
+
+func TestImageService_UploadImages_Concurrency(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		concurrency int
+		wantPeak    int
+	}{
+		{name: "bounded to configured size", concurrency: 2, wantPeak: 2},
+		{name: "sequential", concurrency: 1, wantPeak: 1},
+		{name: "invalid falls back to one", concurrency: 0, wantPeak: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &countingTransport{}
+			uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: transport}}
+			repo := &fakeImageRepo{counts: map[uuid.UUID]int64{}}
+			svc := NewImageService(repo, uploader, nil, nil, tc.concurrency, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+			uploaded, err := svc.UploadImages(context.Background(), uuid.New(), newFileHeaders(t, MaxImagesPerProduct), nil)
+			require.NoError(t, err)
+			assert.Len(t, uploaded, MaxImagesPerProduct)
+			assert.Equal(t, tc.wantPeak, transport.peak)
+		})
+	}
+}
+
+// folderTransport records the folder field of every upload form.
+type folderTransport struct {
+	mu      sync.Mutex
+	folders []string
+}
+
+func (t *folderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.folders = append(t.folders, req.FormValue("folder"))
+	t.mu.Unlock()
+	return stubTransport{}.RoundTrip(req)
+}
+
+func TestImageService_UploadImages_CategoryFolder(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Shoes"}
+	product := domain.Product{ID: uuid.New(), Name: "Trail runner", CategoryId: shoes.ID}
+	folders := NewUploadFolders("ecommerce", "prod", newFakeProductRepo(product),
+		&fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes}}, zap.NewNop())
+
+	transport := &folderTransport{}
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", Folder: "ecommerce", HTTPClient: &http.Client{Transport: transport}}
+	svc := NewImageService(&fakeImageRepo{}, uploader, nil, nil, 2, 0, false, folders, 0, clock.Real(), zap.NewNop())
+
+	_, err := svc.UploadImages(context.Background(), product.ID, newFileHeaders(t, 2), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ecommerce/prod/shoes", "ecommerce/prod/shoes"}, transport.folders)
+}
+
+func TestImageService_SignedURLs(t *testing.T) {
+	productID := uuid.New()
+	cloudImage := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/v1712/ecommerce/a.jpg"}
+	foreignImage := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://cdn.example.com/b.jpg"}
+	repo := &fakeImageRepo{byProduct: map[uuid.UUID][]domain.ProductImage{productID: {cloudImage, foreignImage}}}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("signs cloudinary images", func(t *testing.T) {
+		uploader := cloudinary.NewClient("demo", "key", "secret", "", "")
+		svc := NewImageService(repo, uploader, nil, nil, 1, 0, false, nil, 10*time.Minute, clock.Fixed(now), zap.NewNop())
+
+		signed, err := svc.SignedURLs(context.Background(), productID)
+		require.NoError(t, err)
+		require.Len(t, signed, 1, "urls that are not cloudinary deliveries are skipped")
+		assert.Equal(t, cloudImage.ID, signed[0].ImageID)
+		assert.Equal(t, now.Add(10*time.Minute), signed[0].ExpiresAt)
+		assert.Contains(t, signed[0].URL, "public_id=ecommerce%2Fa")
+		assert.Contains(t, signed[0].URL, "signature=")
+	})
+
+	t.Run("no api secret", func(t *testing.T) {
+		uploader := cloudinary.NewClient("demo", "", "", "preset", "")
+		svc := NewImageService(repo, uploader, nil, nil, 1, 0, false, nil, 0, clock.Fixed(now), zap.NewNop())
+
+		_, err := svc.SignedURLs(context.Background(), productID)
+		var appErr *domain.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusServiceUnavailable, appErr.Status)
+	})
+}
+
+func TestImageService_DeleteImages_MixedIDs(t *testing.T) {
+	productID, otherID := uuid.New(), uuid.New()
+	first := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/v1/ecommerce/a.jpg"}
+	second := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/v1/ecommerce/b.jpg"}
+	kept := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.cloudinary.com/demo/image/upload/v1/ecommerce/c.jpg"}
+	foreign := domain.ProductImage{ID: uuid.New(), ProductID: otherID, URL: "https://res.cloudinary.com/demo/image/upload/v1/ecommerce/d.jpg"}
+	repo := &fakeImageRepo{byProduct: map[uuid.UUID][]domain.ProductImage{
+		productID: {first, second, kept},
+		otherID:   {foreign},
+	}}
+
+	var destroyed []string
+	uploader := cloudinary.NewClient("demo", "key", "secret", "", "")
+	uploader.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		require.NoError(t, req.ParseForm())
+		destroyed = append(destroyed, req.PostForm.Get("public_id"))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"result":"ok"}`)), Header: make(http.Header)}, nil
+	})}
+	svc := NewImageService(repo, uploader, nil, nil, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+
+	unknown := uuid.New()
+	result, err := svc.DeleteImages(context.Background(), productID, []uuid.UUID{first.ID, foreign.ID, second.ID, unknown, first.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Deleted)
+	assert.Equal(t, []uuid.UUID{foreign.ID, unknown}, result.Skipped)
+	assert.Equal(t, []domain.ProductImage{kept}, repo.byProduct[productID])
+	assert.Equal(t, []domain.ProductImage{foreign}, repo.byProduct[otherID], "another product's image is untouched")
+	assert.ElementsMatch(t, []string{"ecommerce/a", "ecommerce/b"}, destroyed)
+
+	_, err = svc.DeleteImages(context.Background(), productID, nil)
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "validation_failed", appErr.Code)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestImageService_UploadDocuments(t *testing.T) {
+	productID := uuid.New()
+	photo := domain.ProductImage{ID: uuid.New(), ProductID: productID, URL: "https://res.example.com/a.jpg"}
+	repo := &fakeImageRepo{counts: map[uuid.UUID]int64{productID: 1}, byProduct: map[uuid.UUID][]domain.ProductAsset{productID: {photo}}}
+
+	var paths []string
+	uploader := &cloudinary.Client{CloudName: "demo", UploadPreset: "preset", HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return stubTransport{}.RoundTrip(req)
+	})}}
+	svc := NewImageService(repo, uploader, nil, []string{"pdf"}, 1, 0, false, nil, 0, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+
+	t.Run("stored as raw document", func(t *testing.T) {
+		files := newNamedFileHeaders(t, testFile{name: "spec sheet.pdf", content: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")})
+		uploaded, err := svc.UploadDocuments(ctx, productID, files)
+		require.NoError(t, err)
+		require.Len(t, uploaded, 1)
+		assert.Equal(t, domain.AssetTypeDocument, uploaded[0].Type)
+		assert.Equal(t, "spec sheet.pdf", uploaded[0].Filename)
+		assert.Equal(t, []string{"/v1_1/demo/raw/upload"}, paths)
+	})
+
+	t.Run("disallowed or mismatched file", func(t *testing.T) {
+		files := newNamedFileHeaders(t,
+			testFile{name: "notes.txt", content: []byte("plain text")},
+			testFile{name: "photo.pdf", content: jpegBytes},
+		)
+		_, err := svc.UploadDocuments(ctx, productID, files)
+		require.ErrorIs(t, err, domain.ErrUnsupportedDocument)
+		assert.Contains(t, err.Error(), "notes.txt")
+		assert.Contains(t, err.Error(), "photo.pdf")
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		missing := uuid.New()
+		repo.missing = map[uuid.UUID]bool{missing: true}
+		defer func() { repo.missing = nil }()
+		uploads := len(paths)
+
+		files := newNamedFileHeaders(t, testFile{name: "manual.pdf", content: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")})
+		_, err := svc.UploadDocuments(ctx, missing, files)
+		require.ErrorIs(t, err, domain.ErrProductNotFound)
+		assert.Len(t, paths, uploads, "nothing is uploaded")
+	})
+
+	t.Run("kept apart from images", func(t *testing.T) {
+		repo.byProduct[productID] = append(repo.byProduct[productID], repo.added...)
+
+		docs, err := svc.ListDocuments(ctx, productID)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.Equal(t, "spec sheet.pdf", docs[0].Filename)
+
+		images, err := svc.ListImages(ctx, productID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ProductImage{photo}, images)
+	})
+}
@@ -0,0 +1,150 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/minilik/ecommerce/internal/domain"
+)
+
+// DefaultImageExtensions is used when no allowlist is configured.
+var DefaultImageExtensions = []string{"jpg", "jpeg", "png", "webp"}
+
+// imageMIMETypes maps known image extensions to the content type http.DetectContentType reports for them.
+var imageMIMETypes = map[string]string{
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"webp": "image/webp",
+	"gif":  "image/gif",
+	"bmp":  "image/bmp",
+}
+
+// DefaultDocumentExtensions is used when no document allowlist is configured.
+var DefaultDocumentExtensions = []string{"pdf", "txt", "csv", "docx", "xlsx"}
+
+// documentMIMETypes maps known document extensions to the prefix of the content type
+// http.DetectContentType reports for them. Office formats are zip containers.
+var documentMIMETypes = map[string]string{
+	"pdf":  "application/pdf",
+	"txt":  "text/plain",
+	"csv":  "text/plain",
+	"docx": "application/zip",
+	"xlsx": "application/zip",
+}
+
+// normalizeExtensions lower-cases exts and drops their dots; empty exts falls back to defaults.
+func normalizeExtensions(exts, defaults []string) map[string]struct{} {
+	if len(exts) == 0 {
+		exts = defaults
+	}
+	allowed := make(map[string]struct{}, len(exts))
+	for _, ext := range exts {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext != "" {
+			allowed[ext] = struct{}{}
+		}
+	}
+	return allowed
+}
+
+// validateImageFiles checks every file against the extension allowlist and its sniffed content type.
+// All offending files are reported together, one wrapped ErrUnsupportedImage per file.
+func (s *imageService) validateImageFiles(files []*multipart.FileHeader) error {
+	var errs []error
+	for _, fh := range files {
+		if err := s.validateImageFile(fh); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *imageService) validateImageFile(fh *multipart.FileHeader) error {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(fh.Filename), "."))
+	if _, ok := s.allowedExts[ext]; !ok {
+		return fmt.Errorf("%w: %s: extension %q is not allowed", domain.ErrUnsupportedImage, fh.Filename, ext)
+	}
+	expected, known := imageMIMETypes[ext]
+	if !known {
+		// configured extension without a sniffable signature: the allowlist alone decides
+		return nil
+	}
+
+	sniffed, err := sniffContentType(fh)
+	if err != nil {
+		return err
+	}
+	if sniffed != expected {
+		return fmt.Errorf("%w: %s: content is %s, not %s", domain.ErrUnsupportedImage, fh.Filename, sniffed, expected)
+	}
+	return nil
+}
+
+// validateDocumentFiles checks documents like validateImageFiles checks images, reporting every
+// offending file as a wrapped ErrUnsupportedDocument.
+func (s *imageService) validateDocumentFiles(files []*multipart.FileHeader) error {
+	var errs []error
+	for _, fh := range files {
+		if err := s.validateDocumentFile(fh); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *imageService) validateDocumentFile(fh *multipart.FileHeader) error {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(fh.Filename), "."))
+	if _, ok := s.documentExts[ext]; !ok {
+		return fmt.Errorf("%w: %s: extension %q is not allowed", domain.ErrUnsupportedDocument, fh.Filename, ext)
+	}
+	expected, known := documentMIMETypes[ext]
+	if !known {
+		return nil
+	}
+	sniffed, err := sniffContentType(fh)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(sniffed, expected) {
+		return fmt.Errorf("%w: %s: content is %s, not %s", domain.ErrUnsupportedDocument, fh.Filename, sniffed, expected)
+	}
+	return nil
+}
+
+// sniffContentType detects a file's content type from its first 512 bytes.
+func sniffContentType(fh *multipart.FileHeader) (string, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return "", fmt.Errorf("open file %s: %w", fh.Filename, err)
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read file %s: %w", fh.Filename, err)
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// checkTotalSize rejects files whose sizes add up to more than maxUploadBytes, before any of them
+// is uploaded. A maxUploadBytes of 0 allows any total.
+func (s *imageService) checkTotalSize(files []*multipart.FileHeader) error {
+	if s.maxUploadBytes <= 0 {
+		return nil
+	}
+	var total int64
+	for _, fh := range files {
+		total += fh.Size
+	}
+	if total > s.maxUploadBytes {
+		return fmt.Errorf("%w: %d files total %d bytes, more than the %d allowed per request", domain.ErrUploadTooLarge, len(files), total, s.maxUploadBytes)
+	}
+	return nil
+}
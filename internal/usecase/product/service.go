@@ -2,17 +2,23 @@ package product
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 
 	"github.com/google/uuid"
 
+	"github.com/minilik/ecommerce/config"
 	"github.com/minilik/ecommerce/internal/domain"
 	"github.com/minilik/ecommerce/internal/domain/repository"
 	memcache "github.com/minilik/ecommerce/pkg/cache"
+	"github.com/minilik/ecommerce/pkg/clock"
 )
 
 type Service interface {
@@ -20,31 +26,73 @@ type Service interface {
 	Update(ctx context.Context, id uuid.UUID, input UpdateProductInput) (*domain.Product, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
-	List(ctx context.Context, input ListProductsInput) ([]domain.Product, int64, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (*BatchGetResult, error)
+	CheckAvailability(ctx context.Context, items []AvailabilityItemInput) (*AvailabilityResult, error)
+	// Suggest returns id and name of products whose name starts with query, for typeahead.
+	Suggest(ctx context.Context, query string) ([]domain.ProductSuggestion, error)
+	List(ctx context.Context, input ListProductsInput) ([]domain.Product, ListPage, error)
+	ListByCategory(ctx context.Context, categoryID uuid.UUID, input ListProductsInput) ([]domain.Product, ListPage, error)
+	CountByCategory(ctx context.Context) ([]domain.CategoryCount, error)
+	// ListCategories returns the categories products are filed under, with product counts.
+	ListCategories(ctx context.Context) ([]domain.CategoryCount, error)
+	// Export walks the whole catalog in id order, handing emit one batch at a time so callers can
+	// stream it without holding every product in memory. An error from emit stops the walk.
+	Export(ctx context.Context, emit func([]domain.Product) error) error
 }
 
+// MaxBatchIDs caps how many products can be resolved in a single batch lookup.
+const MaxBatchIDs = 100
+
+const defaultExportBatchSize = 500
+
 type service struct {
-	repo      repository.ProductRepository
-	orderRepo repository.OrderRepository
-	cache     *memcache.MemoryCache
-	logger    *zap.Logger
-	now       func() time.Time
+	repo         repository.ProductRepository
+	orderRepo    repository.OrderRepository
+	categoryRepo repository.CategoryRepository
+	cache        memcache.Cache
+	limits       config.ProductConfig
+	logger       *zap.Logger
+	now          func() time.Time
 }
 
-func NewService(repo repository.ProductRepository, orderRepo repository.OrderRepository, logger *zap.Logger, cache *memcache.MemoryCache) Service {
+func NewService(repo repository.ProductRepository, orderRepo repository.OrderRepository, categoryRepo repository.CategoryRepository, limits config.ProductConfig, clk clock.Clock, logger *zap.Logger, cache memcache.Cache) Service {
 	return &service{
-		repo:      repo,
-		orderRepo: orderRepo,
-		cache:     cache,
-		logger:    logger,
-		now:       time.Now,
+		repo:         repo,
+		orderRepo:    orderRepo,
+		categoryRepo: categoryRepo,
+		cache:        cache,
+		limits:       limits,
+		logger:       logger,
+		now:          clk.Now,
 	}
 }
 
+// maxExternalIDLength matches the external_id column.
+const maxExternalIDLength = 100
+
+// Create stores a new product. With an ExternalID, a repeated create by the same owner returns the
+// product stored the first time instead of a duplicate, so importers can retry safely.
 func (s *service) Create(ctx context.Context, ownerID uuid.UUID, input CreateProductInput) (*domain.Product, error) {
-	if err := validateCreateInput(input); err != nil {
+	limits, categories, err := s.categoryLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCreateInput(input, limits); err != nil {
 		return nil, err
 	}
+	externalID := strings.TrimSpace(input.ExternalID)
+	if utf8.RuneCountInString(externalID) > maxExternalIDLength {
+		return nil, domain.NewValidationError("externalId must not exceed %d characters", maxExternalIDLength)
+	}
+	if externalID != "" {
+		existing, err := s.repo.GetByExternalID(ctx, ownerID, externalID)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, domain.ErrProductNotFound) {
+			return nil, repoError(err)
+		}
+	}
 
 	product := &domain.Product{
 		ID:          uuid.New(),
@@ -52,13 +100,19 @@ func (s *service) Create(ctx context.Context, ownerID uuid.UUID, input CreatePro
 		Description: strings.TrimSpace(input.Description),
 		Price:       input.Price,
 		Stock:       input.Stock,
-		Category:    strings.TrimSpace(input.Category),
+		Category:    canonicalCategory(strings.TrimSpace(input.Category), limits.AllowedCategories),
 		UserID:      ownerID,
 		CreatedAt:   s.now(),
 		UpdatedAt:   s.now(),
+		ExternalID:  externalID,
 	}
+	product.CategoryId = categoryID(product.Category, categories)
 
 	if err := s.repo.Create(ctx, product); err != nil {
+		// a concurrent create with the same external id got there first
+		if externalID != "" && errors.Is(err, domain.ErrExternalIDExists) {
+			return s.repo.GetByExternalID(ctx, ownerID, externalID)
+		}
 		return nil, err
 	}
 
@@ -71,15 +125,26 @@ func (s *service) Update(ctx context.Context, id uuid.UUID, input UpdateProductI
 		return nil, domain.ErrProductNotFound
 	}
 
-	if err := applyUpdate(product, input); err != nil {
+	limits := s.limits
+	var categories []domain.Category
+	if input.Category != nil {
+		if limits, categories, err = s.categoryLimits(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyUpdate(product, input, limits); err != nil {
 		return nil, err
 	}
+	if input.Category != nil {
+		product.CategoryId = categoryID(product.Category, categories)
+	}
 
 	product.UpdatedAt = s.now()
 
 	if err := s.repo.Update(ctx, product); err != nil {
 		return nil, err
 	}
+	s.invalidateProduct(ctx, id)
 
 	return product, nil
 }
@@ -102,18 +167,268 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 		return domain.ErrProductHasPendingOrders
 	}
 
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateProduct(ctx, id)
+	return nil
 }
 
+// GetByID serves product details from the cache when enabled, falling back to the repository on a miss.
+// Cached entries include the preloaded images and share the product list TTL; writers outside this
+// service evict them through InvalidatingUnitOfWork and InvalidatingAssets.
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	key := productCacheKey(id)
+	if v, ok := s.cacheGet(ctx, key); ok {
+		if cached, ok := v.(domain.Product); ok {
+			return cloneProduct(cached), nil
+		}
+	}
+
 	product, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		if ctxErr := domain.ContextError(err); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, domain.ErrProductNotFound
 	}
+	s.cacheSet(ctx, key, *cloneProduct(*product))
 	return product, nil
 }
 
-func (s *service) List(ctx context.Context, input ListProductsInput) ([]domain.Product, int64, error) {
+func productCacheKey(id uuid.UUID) string {
+	return "product:" + id.String()
+}
+
+func (s *service) invalidateProduct(ctx context.Context, id uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+	// a failed delete leaves a stale entry until its TTL runs out, so it is worth a warning
+	if err := s.cache.Delete(ctx, productCacheKey(id)); err != nil {
+		s.logger.Warn("cache delete failed", zap.String("product_id", id.String()), zap.Error(err))
+	}
+}
+
+// cacheGet reports a hit only when the cache is enabled and answered; errors count as a miss.
+func (s *service) cacheGet(ctx context.Context, key string) (interface{}, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	v, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		s.logger.Debug("cache get failed, treating as miss", zap.String("key", key), zap.Error(err))
+		return nil, false
+	}
+	return v, ok
+}
+
+// cacheSet stores value when the cache is enabled; a failure only costs the next read a miss.
+func (s *service) cacheSet(ctx context.Context, key string, value interface{}) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Set(ctx, key, value); err != nil {
+		s.logger.Debug("cache set failed", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// cloneProduct copies p including its images so callers cannot mutate a cached value.
+func cloneProduct(p domain.Product) *domain.Product {
+	p.Images = append([]domain.ProductImage(nil), p.Images...)
+	return &p
+}
+
+// GetByIDs resolves many products at once. Found products follow the order of the
+// requested ids (duplicates collapsed) and ids without a product are reported as missing.
+func (s *service) GetByIDs(ctx context.Context, ids []uuid.UUID) (*BatchGetResult, error) {
+	if len(ids) > MaxBatchIDs {
+		return nil, domain.ErrTooManyProductIDs
+	}
+
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	found, err := s.repo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	byID := make(map[uuid.UUID]domain.Product, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	result := &BatchGetResult{
+		Products: make([]domain.Product, 0, len(found)),
+		Missing:  []uuid.UUID{},
+	}
+	for _, id := range unique {
+		if p, ok := byID[id]; ok {
+			result.Products = append(result.Products, p)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
+
+// CheckAvailability reports, per cart line and in request order, whether current stock covers the
+// requested quantity. All products are read in one query; nothing is reserved.
+func (s *service) CheckAvailability(ctx context.Context, items []AvailabilityItemInput) (*AvailabilityResult, error) {
+	if len(items) == 0 {
+		return nil, domain.NewValidationError("at least one item is required")
+	}
+	if len(items) > MaxBatchIDs {
+		return nil, domain.ErrTooManyProductIDs
+	}
+	ids := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		if item.ProductID == uuid.Nil {
+			return nil, domain.NewValidationError("product id is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, domain.NewValidationError("quantity for product %s must be greater than zero", item.ProductID)
+		}
+		if _, dup := seen[item.ProductID]; dup {
+			return nil, domain.NewValidationError("product %s is listed more than once", item.ProductID)
+		}
+		seen[item.ProductID] = struct{}{}
+		ids = append(ids, item.ProductID)
+	}
+
+	found, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	stock := make(map[uuid.UUID]int, len(found))
+	for _, p := range found {
+		stock[p.ID] = p.Stock
+	}
+
+	result := &AvailabilityResult{Items: make([]ItemAvailability, 0, len(items)), AllAvailable: true}
+	for _, item := range items {
+		available, ok := stock[item.ProductID]
+		line := ItemAvailability{
+			ProductID: item.ProductID,
+			Requested: item.Quantity,
+			Available: available,
+			InStock:   ok && available >= item.Quantity,
+			Found:     ok,
+		}
+		result.AllAvailable = result.AllAvailable && line.InStock
+		result.Items = append(result.Items, line)
+	}
+	return result, nil
+}
+
+// DefaultSuggestLimit is the typeahead result cap used when none is configured.
+const DefaultSuggestLimit = 10
+
+// maxSuggestQueryLength matches the product name column; longer prefixes cannot match anything.
+const maxSuggestQueryLength = 100
+
+func (s *service) Suggest(ctx context.Context, query string) ([]domain.ProductSuggestion, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []domain.ProductSuggestion{}, nil
+	}
+	if utf8.RuneCountInString(query) > maxSuggestQueryLength {
+		return nil, domain.NewValidationError("query must be at most %d characters", maxSuggestQueryLength)
+	}
+	limit := s.limits.SuggestLimit
+	if limit <= 0 {
+		limit = DefaultSuggestLimit
+	}
+	suggestions, err := s.repo.SuggestByPrefix(ctx, query, limit)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	if suggestions == nil {
+		suggestions = []domain.ProductSuggestion{}
+	}
+	return suggestions, nil
+}
+
+func (s *service) List(ctx context.Context, input ListProductsInput) ([]domain.Product, ListPage, error) {
+	return s.list(ctx, input, uuid.Nil)
+}
+
+// ListByCategory lists the products of an existing category with the same paging and search as List.
+func (s *service) ListByCategory(ctx context.Context, categoryID uuid.UUID, input ListProductsInput) ([]domain.Product, ListPage, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, ListPage{}, repoError(err)
+	}
+	return s.list(ctx, input, categoryID)
+}
+
+func (s *service) CountByCategory(ctx context.Context) ([]domain.CategoryCount, error) {
+	counts, err := s.repo.CountByCategory(ctx)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return counts, nil
+}
+
+// categoriesCacheKey caches the category menu for the product list TTL, like listings.
+const categoriesCacheKey = "products:categories"
+
+// ListCategories is CountByCategory for the category menu: products without a category are left
+// out and the rest is ordered by name.
+func (s *service) ListCategories(ctx context.Context) ([]domain.CategoryCount, error) {
+	if v, ok := s.cacheGet(ctx, categoriesCacheKey); ok {
+		if categories, ok := v.([]domain.CategoryCount); ok {
+			return categories, nil
+		}
+	}
+	counts, err := s.repo.CountByCategory(ctx)
+	if err != nil {
+		return nil, repoError(err)
+	}
+	categories := make([]domain.CategoryCount, 0, len(counts))
+	for _, c := range counts {
+		if c.Name != "" {
+			categories = append(categories, c)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	s.cacheSet(ctx, categoriesCacheKey, categories)
+	return categories, nil
+}
+
+func (s *service) Export(ctx context.Context, emit func([]domain.Product) error) error {
+	batchSize := s.limits.ExportBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExportBatchSize
+	}
+
+	after := uuid.Nil
+	for {
+		batch, err := s.repo.ListAfter(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := emit(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
+
+func (s *service) list(ctx context.Context, input ListProductsInput, categoryID uuid.UUID) ([]domain.Product, ListPage, error) {
 	page := input.Page
 	if page <= 0 {
 		page = 1
@@ -126,22 +441,50 @@ func (s *service) List(ctx context.Context, input ListProductsInput) ([]domain.P
 	if pageSize > 100 {
 		pageSize = 100
 	}
+	listPage := ListPage{Page: page, PageSize: pageSize}
+	if input.Sort == "" {
+		input.Sort = s.limits.DefaultSort
+	}
+	if !repository.ValidSort(input.Sort) {
+		return nil, ListPage{}, domain.NewValidationError("unsupported sort %q", input.Sort)
+	}
+
+	if !input.CreatedFrom.IsZero() && !input.CreatedTo.IsZero() && input.CreatedFrom.After(input.CreatedTo) {
+		return nil, ListPage{}, domain.NewValidationError("created_from must not be after created_to")
+	}
 
 	offset := (page - 1) * pageSize
 	filter := repository.ProductFilter{
-		Search: strings.TrimSpace(input.Search),
-		Limit:  pageSize,
-		Offset: offset,
-	}
-
-	cacheKey := fmt.Sprintf("products:list:%s:%d:%d", strings.ToLower(filter.Search), page, pageSize)
-	if s.cache != nil {
-		if v, ok := s.cache.Get(cacheKey); ok {
-			if res, ok2 := v.([2]interface{}); ok2 {
-				if prods, okp := res[0].([]domain.Product); okp {
-					if tot, okt := res[1].(int64); okt {
-						return prods, tot, nil
-					}
+		Search:      normalizeSearch(input.Search),
+		CategoryID:  categoryID,
+		Sort:        input.Sort,
+		Limit:       pageSize,
+		Offset:      offset,
+		SkipCount:   input.SkipCount,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+	}
+	if filter.SkipCount {
+		// one row past the page tells whether a next page exists
+		filter.Limit++
+	}
+
+	cacheKey := fmt.Sprintf("products:list:%s:%s:%d:%d", strings.ToLower(filter.Search), filter.Sort, page, pageSize)
+	if categoryID != uuid.Nil {
+		cacheKey += ":category:" + categoryID.String()
+	}
+	if filter.SkipCount {
+		cacheKey += ":nocount"
+	}
+	if !filter.CreatedFrom.IsZero() || !filter.CreatedTo.IsZero() {
+		cacheKey += fmt.Sprintf(":created:%d:%d", unixNanoOrZero(filter.CreatedFrom), unixNanoOrZero(filter.CreatedTo))
+	}
+	if v, ok := s.cacheGet(ctx, cacheKey); ok {
+		if res, ok2 := v.([2]interface{}); ok2 {
+			if prods, okp := res[0].([]domain.Product); okp {
+				if tot, okt := res[1].(int64); okt {
+					listPage.Total = tot
+					return prods, listPage, nil
 				}
 			}
 		}
@@ -149,66 +492,192 @@ func (s *service) List(ctx context.Context, input ListProductsInput) ([]domain.P
 
 	products, total, err := s.repo.List(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, ListPage{}, repoError(err)
+	}
+	if filter.SkipCount {
+		total = int64(offset + len(products))
+		if len(products) > pageSize {
+			products = products[:pageSize]
+		}
 	}
-	if s.cache != nil {
-		s.cache.Set(cacheKey, [2]interface{}{products, total})
+	s.cacheSet(ctx, cacheKey, [2]interface{}{products, total})
+	listPage.Total = total
+	return products, listPage, nil
+}
+
+// unixNanoOrZero keeps an unset bound at 0 in cache keys, since the zero time has no UnixNano.
+func unixNanoOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
-	return products, total, nil
+	return t.UnixNano()
 }
 
-func validateCreateInput(input CreateProductInput) error {
+// normalizeSearch trims a search term and collapses inner whitespace runs to one space, so
+// whitespace-only equals no search and equivalent terms share a cache entry.
+func normalizeSearch(search string) string {
+	return strings.Join(strings.Fields(search), " ")
+}
+
+// repoError swaps a context cancellation or deadline from the repository for its clean domain
+// error so the driver's message never reaches the client.
+func repoError(err error) error {
+	if ctxErr := domain.ContextError(err); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func validateCreateInput(input CreateProductInput, limits config.ProductConfig) error {
 	if len(strings.TrimSpace(input.Name)) < 3 || len(strings.TrimSpace(input.Name)) > 100 {
-		return fmt.Errorf("required:name must be between 3 and 100 characters")
+		return domain.NewValidationError("required:name must be between 3 and 100 characters")
 	}
 	if len(strings.TrimSpace(input.Description)) < 10 {
-		return fmt.Errorf("required:description must be at least 10 characters")
+		return domain.NewValidationError("required:description must be at least 10 characters")
+	}
+	if exceedsLength(strings.TrimSpace(input.Description), limits.MaxDescriptionLength) {
+		return domain.NewValidationError("required:description must not exceed %d characters", limits.MaxDescriptionLength)
 	}
 	if input.Price <= 0 {
-		return fmt.Errorf("required:price must be greater than zero")
+		return domain.NewValidationError("required:price must be greater than zero")
+	}
+	if !isWholeCents(input.Price) {
+		return domain.NewValidationError("required:price must have at most two decimal places")
+	}
+	if limits.MaxPrice > 0 && input.Price > limits.MaxPrice {
+		return domain.NewValidationError("required:price must not exceed %.2f", limits.MaxPrice)
 	}
 	if input.Stock < 0 {
-		return fmt.Errorf("required:stock must be non-negative")
+		return domain.NewValidationError("required:stock must be non-negative")
+	}
+	if limits.MaxStock > 0 && input.Stock > limits.MaxStock {
+		return domain.NewValidationError("required:stock must not exceed %d", limits.MaxStock)
 	}
 	if strings.TrimSpace(input.Category) == "" {
-		return fmt.Errorf("required:category is required")
+		return domain.NewValidationError("required:category is required")
+	}
+	if err := checkCategory(strings.TrimSpace(input.Category), limits.AllowedCategories); err != nil {
+		return err
 	}
 	return nil
 }
 
-func applyUpdate(product *domain.Product, input UpdateProductInput) error {
+func applyUpdate(product *domain.Product, input UpdateProductInput, limits config.ProductConfig) error {
 	if input.Name != nil {
 		name := strings.TrimSpace(*input.Name)
 		if len(name) == 0 {
-			return fmt.Errorf("name cannot be empty")
+			return domain.NewValidationError("name cannot be empty")
 		}
 		product.Name = name
 	}
 	if input.Description != nil {
 		desc := strings.TrimSpace(*input.Description)
 		if len(desc) == 0 {
-			return fmt.Errorf("description cannot be empty")
+			return domain.NewValidationError("description cannot be empty")
+		}
+		if exceedsLength(desc, limits.MaxDescriptionLength) {
+			return domain.NewValidationError("description must not exceed %d characters", limits.MaxDescriptionLength)
 		}
 		product.Description = desc
 	}
 	if input.Price != nil {
 		if *input.Price <= 0 {
-			return fmt.Errorf("price must be greater than zero")
+			return domain.NewValidationError("price must be greater than zero")
+		}
+		if !isWholeCents(*input.Price) {
+			return domain.NewValidationError("price must have at most two decimal places")
+		}
+		if limits.MaxPrice > 0 && *input.Price > limits.MaxPrice {
+			return domain.NewValidationError("price must not exceed %.2f", limits.MaxPrice)
 		}
 		product.Price = *input.Price
 	}
 	if input.Stock != nil {
 		if *input.Stock < 0 {
-			return fmt.Errorf("stock must be non-negative")
+			return domain.NewValidationError("stock must be non-negative")
+		}
+		if limits.MaxStock > 0 && *input.Stock > limits.MaxStock {
+			return domain.NewValidationError("stock must not exceed %d", limits.MaxStock)
 		}
 		product.Stock = *input.Stock
 	}
 	if input.Category != nil {
 		category := strings.TrimSpace(*input.Category)
 		if category == "" {
-			return fmt.Errorf("category cannot be empty")
+			return domain.NewValidationError("category cannot be empty")
+		}
+		if err := checkCategory(category, limits.AllowedCategories); err != nil {
+			return err
 		}
-		product.Category = category
+		product.Category = canonicalCategory(category, limits.AllowedCategories)
 	}
 	return nil
 }
+
+// categoryLimits returns the product limits with AllowedCategories set to the names of the
+// categories in the category repository, along with those categories. product.allowed_categories
+// only applies while no category is defined there.
+func (s *service) categoryLimits(ctx context.Context) (config.ProductConfig, []domain.Category, error) {
+	limits := s.limits
+	if s.categoryRepo == nil {
+		return limits, nil, nil
+	}
+	categories, _, err := s.categoryRepo.List(ctx, repository.ProductFilter{})
+	if err != nil {
+		return limits, nil, repoError(err)
+	}
+	if len(categories) == 0 {
+		return limits, nil, nil
+	}
+	limits.AllowedCategories = make([]string, 0, len(categories))
+	for _, c := range categories {
+		limits.AllowedCategories = append(limits.AllowedCategories, c.Name)
+	}
+	return limits, categories, nil
+}
+
+// categoryID returns the id of the category named name, or uuid.Nil when there is none, so
+// category_id follows the category name a product is filed under.
+func categoryID(name string, categories []domain.Category) uuid.UUID {
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return c.ID
+		}
+	}
+	return uuid.Nil
+}
+
+// checkCategory rejects a category that is not on a non-empty allowlist.
+func checkCategory(category string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(a, category) {
+			return nil
+		}
+	}
+	return domain.NewValidationError("category %q is not allowed; use one of: %s", category, strings.Join(allowed, ", "))
+}
+
+// canonicalCategory returns the allowlist's spelling of category so "electronics" is stored as "Electronics".
+func canonicalCategory(category string, allowed []string) string {
+	for _, a := range allowed {
+		if strings.EqualFold(a, category) {
+			return a
+		}
+	}
+	return category
+}
+
+// isWholeCents reports whether price has at most two decimal places. The small tolerance absorbs
+// binary representation error, e.g. 0.29*100 == 28.999999999999996.
+func isWholeCents(price float64) bool {
+	cents := price * 100
+	return math.Abs(cents-math.Round(cents)) < 1e-6
+}
+
+// exceedsLength reports whether s has more than max characters; runes are counted so multibyte text is not penalized.
+func exceedsLength(s string, max int) bool {
+	return max > 0 && utf8.RuneCountInString(s) > max
+}
@@ -0,0 +1,844 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	memcache "github.com/minilik/ecommerce/pkg/cache"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// fakeProductRepo is an in-memory ProductRepository; unimplemented methods panic via the nil embed.
+type fakeProductRepo struct {
+	repository.ProductRepository
+	products        map[uuid.UUID]domain.Product
+	getByIDCalls    int
+	listSearches    []string
+	listAfterLimits []int
+	categories      []domain.CategoryCount
+	categoriesCalls int
+}
+
+func newFakeProductRepo(products ...domain.Product) *fakeProductRepo {
+	repo := &fakeProductRepo{products: make(map[uuid.UUID]domain.Product, len(products))}
+	for _, p := range products {
+		repo.products[p.ID] = p
+	}
+	return repo
+}
+
+func (r *fakeProductRepo) CountByCategory(ctx context.Context) ([]domain.CategoryCount, error) {
+	r.categoriesCalls++
+	return r.categories, nil
+}
+
+// Create enforces the owner/external id uniqueness the database index provides.
+func (r *fakeProductRepo) Create(ctx context.Context, product *domain.Product) error {
+	if product.ExternalID != "" {
+		for _, p := range r.products {
+			if p.UserID == product.UserID && p.ExternalID == product.ExternalID {
+				return domain.ErrExternalIDExists
+			}
+		}
+	}
+	r.products[product.ID] = *product
+	return nil
+}
+
+func (r *fakeProductRepo) GetByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*domain.Product, error) {
+	for _, p := range r.products {
+		if p.UserID == ownerID && p.ExternalID == externalID {
+			return &p, nil
+		}
+	}
+	return nil, domain.ErrProductNotFound
+}
+
+func (r *fakeProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	r.getByIDCalls++
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	p, ok := r.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	return &p, nil
+}
+
+func (r *fakeProductRepo) Update(ctx context.Context, product *domain.Product) error {
+	r.products[product.ID] = *product
+	return nil
+}
+
+func (r *fakeProductRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	out := make([]domain.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeProductRepo) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Product, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to connect: %w", err)
+	}
+	r.listSearches = append(r.listSearches, filter.Search)
+	var out []domain.Product
+	for _, p := range r.products {
+		if filter.CategoryID != uuid.Nil && p.CategoryId != filter.CategoryID {
+			continue
+		}
+		if (!filter.CreatedFrom.IsZero() && p.CreatedAt.Before(filter.CreatedFrom)) ||
+			(!filter.CreatedTo.IsZero() && p.CreatedAt.After(filter.CreatedTo)) {
+			continue
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.String() < out[j].ID.String() })
+	total := int64(len(out))
+	if filter.SkipCount {
+		total = 0
+	}
+	out = out[min(filter.Offset, len(out)):]
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, total, nil
+}
+
+// ListAfter pages through products ordered by id and records each requested limit.
+func (r *fakeProductRepo) ListAfter(ctx context.Context, after uuid.UUID, limit int) ([]domain.Product, error) {
+	r.listAfterLimits = append(r.listAfterLimits, limit)
+	var out []domain.Product
+	for _, p := range r.products {
+		if strings.Compare(p.ID.String(), after.String()) > 0 {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID.String() < out[j].ID.String() })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+type fakeCategoryRepo struct {
+	repository.CategoryRepository
+	categories map[uuid.UUID]domain.Category
+}
+
+func (r *fakeCategoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	c, ok := r.categories[id]
+	if !ok {
+		return nil, domain.ErrCategoryNotFound
+	}
+	return &c, nil
+}
+
+func (r *fakeCategoryRepo) List(ctx context.Context, filter repository.ProductFilter) ([]domain.Category, int64, error) {
+	out := make([]domain.Category, 0, len(r.categories))
+	for _, c := range r.categories {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, int64(len(out)), nil
+}
+
+func TestValidateCreateInput_Limits(t *testing.T) {
+	limits := config.ProductConfig{MaxPrice: 500, MaxStock: 50}
+	valid := CreateProductInput{
+		Name:        "Keyboard",
+		Description: "Mechanical keyboard",
+		Price:       100,
+		Stock:       10,
+		Category:    "electronics",
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		assert.NoError(t, validateCreateInput(valid, limits))
+	})
+
+	t.Run("price above max", func(t *testing.T) {
+		input := valid
+		input.Price = 500.01
+		assert.Error(t, validateCreateInput(input, limits))
+	})
+
+	t.Run("stock above max", func(t *testing.T) {
+		input := valid
+		input.Stock = 51
+		assert.Error(t, validateCreateInput(input, limits))
+	})
+
+	t.Run("zero limits are unbounded", func(t *testing.T) {
+		input := valid
+		input.Price = 1_000_000
+		input.Stock = 1_000_000
+		assert.NoError(t, validateCreateInput(input, config.ProductConfig{}))
+	})
+}
+
+func TestApplyUpdate_Limits(t *testing.T) {
+	limits := config.ProductConfig{MaxPrice: 500, MaxStock: 50}
+
+	t.Run("price above max", func(t *testing.T) {
+		product := &domain.Product{Price: 10, Stock: 1}
+		price := 501.0
+		assert.Error(t, applyUpdate(product, UpdateProductInput{Price: &price}, limits))
+		assert.Equal(t, 10.0, product.Price)
+	})
+
+	t.Run("stock above max", func(t *testing.T) {
+		product := &domain.Product{Price: 10, Stock: 1}
+		stock := 51
+		assert.Error(t, applyUpdate(product, UpdateProductInput{Stock: &stock}, limits))
+		assert.Equal(t, 1, product.Stock)
+	})
+}
+
+func TestPricePrecision(t *testing.T) {
+	valid := CreateProductInput{
+		Name:        "Keyboard",
+		Description: "Mechanical keyboard",
+		Stock:       10,
+		Category:    "electronics",
+	}
+	cases := []struct {
+		price float64
+		ok    bool
+	}{
+		{price: 9.99, ok: true},
+		{price: 10, ok: true},
+		{price: 0.29, ok: true},
+		{price: 9.999, ok: false},
+		{price: 0.001, ok: false},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.price), func(t *testing.T) {
+			input := valid
+			input.Price = tc.price
+			product := &domain.Product{Price: 5}
+			createErr := validateCreateInput(input, config.ProductConfig{})
+			updateErr := applyUpdate(product, UpdateProductInput{Price: &tc.price}, config.ProductConfig{})
+			if tc.ok {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				assert.Equal(t, tc.price, product.Price)
+				return
+			}
+			assert.ErrorContains(t, createErr, "at most two decimal places")
+			assert.ErrorContains(t, updateErr, "at most two decimal places")
+			assert.Equal(t, 5.0, product.Price)
+		})
+	}
+}
+
+func TestAllowedCategories(t *testing.T) {
+	limits := config.ProductConfig{AllowedCategories: []string{"Electronics", "Books"}}
+	svc := NewService(newFakeProductRepo(), nil, nil, limits, clock.Real(), zap.NewNop(), nil)
+	input := CreateProductInput{
+		Name:        "Keyboard",
+		Description: "Mechanical keyboard",
+		Price:       100,
+		Stock:       10,
+		Category:    "electronics",
+	}
+
+	t.Run("allowed category is stored in its configured spelling", func(t *testing.T) {
+		product, err := svc.Create(context.Background(), uuid.New(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "Electronics", product.Category)
+	})
+
+	t.Run("unknown category is rejected", func(t *testing.T) {
+		typo := input
+		typo.Category = "Electonics"
+		_, err := svc.Create(context.Background(), uuid.New(), typo)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `category "Electonics" is not allowed`)
+
+		product := &domain.Product{Category: "Books"}
+		category := "Electonics"
+		assert.Error(t, applyUpdate(product, UpdateProductInput{Category: &category}, limits))
+		assert.Equal(t, "Books", product.Category)
+	})
+
+	t.Run("empty allowlist keeps free text", func(t *testing.T) {
+		typo := input
+		typo.Category = "Electonics"
+		assert.NoError(t, validateCreateInput(typo, config.ProductConfig{}))
+	})
+}
+
+func TestService_CategoriesFromRepository(t *testing.T) {
+	ctx := context.Background()
+	limits := config.ProductConfig{AllowedCategories: []string{"Electronics", "Books"}}
+	home := domain.Category{ID: uuid.New(), Name: "Home"}
+	garden := domain.Category{ID: uuid.New(), Name: "Garden"}
+	input := CreateProductInput{
+		Name:        "Lamp",
+		Description: "Adjustable desk lamp",
+		Price:       24,
+		Stock:       5,
+		Category:    "home",
+	}
+
+	t.Run("defined categories replace the configured list", func(t *testing.T) {
+		categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{home.ID: home, garden.ID: garden}}
+		repo := newFakeProductRepo()
+		svc := NewService(repo, nil, categories, limits, clock.Real(), zap.NewNop(), nil)
+
+		product, err := svc.Create(ctx, uuid.New(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "Home", product.Category, "stored in the category's spelling")
+
+		configured := input
+		configured.Category = "Books"
+		_, err = svc.Create(ctx, uuid.New(), configured)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `category "Books" is not allowed; use one of: Garden, Home`)
+
+		category := "garden"
+		updated, err := svc.Update(ctx, product.ID, UpdateProductInput{Category: &category})
+		require.NoError(t, err)
+		assert.Equal(t, "Garden", updated.Category)
+
+		category = "Electronics"
+		_, err = svc.Update(ctx, product.ID, UpdateProductInput{Category: &category})
+		assert.Error(t, err)
+	})
+
+	t.Run("no categories defined falls back to the configured list", func(t *testing.T) {
+		svc := NewService(newFakeProductRepo(), nil, &fakeCategoryRepo{}, limits, clock.Real(), zap.NewNop(), nil)
+
+		_, err := svc.Create(ctx, uuid.New(), input)
+		assert.Error(t, err)
+
+		books := input
+		books.Category = "books"
+		product, err := svc.Create(ctx, uuid.New(), books)
+		require.NoError(t, err)
+		assert.Equal(t, "Books", product.Category)
+	})
+}
+
+func TestService_GetByIDs(t *testing.T) {
+	first := domain.Product{ID: uuid.New(), Name: "first"}
+	second := domain.Product{ID: uuid.New(), Name: "second"}
+	svc := NewService(newFakeProductRepo(first, second), nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+	t.Run("partial match keeps request order", func(t *testing.T) {
+		missing := uuid.New()
+		res, err := svc.GetByIDs(context.Background(), []uuid.UUID{second.ID, missing, first.ID, second.ID})
+		require.NoError(t, err)
+		require.Len(t, res.Products, 2)
+		assert.Equal(t, second.ID, res.Products[0].ID)
+		assert.Equal(t, first.ID, res.Products[1].ID)
+		assert.Equal(t, []uuid.UUID{missing}, res.Missing)
+	})
+
+	t.Run("cap exceeded", func(t *testing.T) {
+		ids := make([]uuid.UUID, MaxBatchIDs+1)
+		for i := range ids {
+			ids[i] = uuid.New()
+		}
+		_, err := svc.GetByIDs(context.Background(), ids)
+		assert.ErrorIs(t, err, domain.ErrTooManyProductIDs)
+	})
+}
+
+func TestService_CheckAvailability(t *testing.T) {
+	plenty := domain.Product{ID: uuid.New(), Name: "plenty", Stock: 10}
+	scarce := domain.Product{ID: uuid.New(), Name: "scarce", Stock: 1}
+	svc := NewService(newFakeProductRepo(plenty, scarce), nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+	t.Run("one item out of stock", func(t *testing.T) {
+		missing := uuid.New()
+		res, err := svc.CheckAvailability(context.Background(), []AvailabilityItemInput{
+			{ProductID: plenty.ID, Quantity: 3},
+			{ProductID: scarce.ID, Quantity: 2},
+			{ProductID: missing, Quantity: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 3)
+		assert.False(t, res.AllAvailable)
+
+		assert.Equal(t, ItemAvailability{ProductID: plenty.ID, Requested: 3, Available: 10, InStock: true, Found: true}, res.Items[0])
+		assert.Equal(t, ItemAvailability{ProductID: scarce.ID, Requested: 2, Available: 1, InStock: false, Found: true}, res.Items[1])
+		assert.Equal(t, ItemAvailability{ProductID: missing, Requested: 1}, res.Items[2])
+	})
+
+	t.Run("all in stock", func(t *testing.T) {
+		res, err := svc.CheckAvailability(context.Background(), []AvailabilityItemInput{
+			{ProductID: plenty.ID, Quantity: 10},
+			{ProductID: scarce.ID, Quantity: 1},
+		})
+		require.NoError(t, err)
+		assert.True(t, res.AllAvailable)
+	})
+
+	t.Run("invalid lines", func(t *testing.T) {
+		for name, items := range map[string][]AvailabilityItemInput{
+			"empty":     nil,
+			"zero qty":  {{ProductID: plenty.ID, Quantity: 0}},
+			"nil id":    {{Quantity: 1}},
+			"duplicate": {{ProductID: plenty.ID, Quantity: 1}, {ProductID: plenty.ID, Quantity: 2}},
+		} {
+			_, err := svc.CheckAvailability(context.Background(), items)
+			var appErr *domain.AppError
+			require.ErrorAs(t, err, &appErr, name)
+			assert.Equal(t, "validation_failed", appErr.Code, name)
+		}
+	})
+}
+
+// racingProductRepo misses the external id lookup, as a create racing another would.
+type racingProductRepo struct {
+	*fakeProductRepo
+	lookups int
+}
+
+func (r *racingProductRepo) GetByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*domain.Product, error) {
+	r.lookups++
+	if r.lookups == 1 {
+		return nil, domain.ErrProductNotFound
+	}
+	return r.fakeProductRepo.GetByExternalID(ctx, ownerID, externalID)
+}
+
+func TestService_Create_ExternalID(t *testing.T) {
+	ctx := context.Background()
+	owner := uuid.New()
+	input := CreateProductInput{Name: "Desk Lamp", Description: "A warm reading lamp", Price: 25, Stock: 4, Category: "lighting", ExternalID: " sku-42 "}
+
+	t.Run("repeated create returns the first product", func(t *testing.T) {
+		repo := newFakeProductRepo()
+		svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+		first, err := svc.Create(ctx, owner, input)
+		require.NoError(t, err)
+		assert.Equal(t, "sku-42", first.ExternalID)
+
+		retry := input
+		retry.Stock = 99
+		second, err := svc.Create(ctx, owner, retry)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, 4, second.Stock, "the stored product is returned unchanged")
+		assert.Len(t, repo.products, 1)
+
+		_, err = svc.Create(ctx, uuid.New(), input)
+		require.NoError(t, err)
+		assert.Len(t, repo.products, 2, "external ids are unique per owner")
+	})
+
+	t.Run("losing a concurrent create returns the winner", func(t *testing.T) {
+		winner := domain.Product{ID: uuid.New(), Name: "Desk Lamp", UserID: owner, ExternalID: "sku-42"}
+		repo := &racingProductRepo{fakeProductRepo: newFakeProductRepo(winner)}
+		svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+		got, err := svc.Create(ctx, owner, input)
+		require.NoError(t, err)
+		assert.Equal(t, winner.ID, got.ID)
+		assert.Len(t, repo.products, 1)
+	})
+
+	t.Run("without external id every create is new", func(t *testing.T) {
+		repo := newFakeProductRepo()
+		svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+		plain := input
+		plain.ExternalID = ""
+		for i := 0; i < 2; i++ {
+			_, err := svc.Create(ctx, owner, plain)
+			require.NoError(t, err)
+		}
+		assert.Len(t, repo.products, 2)
+	})
+}
+
+func TestService_Create_UsesClock(t *testing.T) {
+	frozen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewService(newFakeProductRepo(), nil, nil, config.ProductConfig{}, clock.Fixed(frozen), zap.NewNop(), nil)
+
+	product, err := svc.Create(context.Background(), uuid.New(), CreateProductInput{
+		Name:        "Keyboard",
+		Description: "Mechanical keyboard",
+		Price:       100,
+		Stock:       10,
+		Category:    "electronics",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, frozen, product.CreatedAt)
+	assert.Equal(t, frozen, product.UpdatedAt)
+}
+
+func TestService_ListByCategory(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Shoes"}
+	empty := domain.Category{ID: uuid.New(), Name: "Hats"}
+	sneaker := domain.Product{ID: uuid.New(), Name: "Sneaker", CategoryId: shoes.ID}
+	lamp := domain.Product{ID: uuid.New(), Name: "Lamp", CategoryId: uuid.New()}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes, empty.ID: empty}}
+	svc := NewService(newFakeProductRepo(sneaker, lamp), nil, categories, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+	ctx := context.Background()
+
+	products, page, err := svc.ListByCategory(ctx, shoes.ID, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	require.Len(t, products, 1)
+	assert.Equal(t, sneaker.ID, products[0].ID)
+
+	products, page, err = svc.ListByCategory(ctx, empty.ID, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+	assert.Empty(t, products)
+
+	_, _, err = svc.ListByCategory(ctx, uuid.New(), ListProductsInput{})
+	assert.ErrorIs(t, err, domain.ErrCategoryNotFound)
+}
+
+func TestService_ListByCategory_CreatedProducts(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Shoes"}
+	hats := domain.Category{ID: uuid.New(), Name: "Hats"}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes, hats.ID: hats}}
+	svc := NewService(newFakeProductRepo(), nil, categories, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, uuid.New(), CreateProductInput{
+		Name:        "Sneaker",
+		Description: "Running shoe",
+		Price:       80,
+		Stock:       3,
+		Category:    "shoes",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, shoes.ID, created.CategoryId)
+
+	products, page, err := svc.ListByCategory(ctx, shoes.ID, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	require.Len(t, products, 1)
+	assert.Equal(t, created.ID, products[0].ID)
+
+	category := "Hats"
+	_, err = svc.Update(ctx, created.ID, UpdateProductInput{Category: &category})
+	require.NoError(t, err)
+
+	products, _, err = svc.ListByCategory(ctx, shoes.ID, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Empty(t, products)
+	products, _, err = svc.ListByCategory(ctx, hats.ID, ListProductsInput{})
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, created.ID, products[0].ID)
+}
+
+func TestService_List_NormalizesSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeProductRepo(domain.Product{ID: uuid.New(), Name: "Desk Lamp"})
+	svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), memcache.Memory(memcache.NewMemoryCache(time.Minute, 10)))
+
+	for _, search := range []string{"", "   ", "\t \n"} {
+		_, _, err := svc.List(ctx, ListProductsInput{Search: search})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{""}, repo.listSearches, "whitespace-only search hits the empty-search cache entry")
+
+	for _, search := range []string{"  Desk   Lamp ", "desk lamp", "DESK\tLAMP"} {
+		_, _, err := svc.List(ctx, ListProductsInput{Search: search})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"", "Desk Lamp"}, repo.listSearches, "inner whitespace is collapsed before querying and caching")
+}
+
+func TestService_List_SkipCount(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeProductRepo(
+		domain.Product{ID: uuid.New(), Name: "Lamp"},
+		domain.Product{ID: uuid.New(), Name: "Desk"},
+		domain.Product{ID: uuid.New(), Name: "Chair"},
+	)
+	svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+	first, page, err := svc.List(ctx, ListProductsInput{Page: 1, PageSize: 2, SkipCount: true})
+	require.NoError(t, err)
+	assert.Len(t, first, 2, "the probe row is not returned")
+	assert.True(t, page.HasNext(), "total signals a next page")
+
+	last, page, err := svc.List(ctx, ListProductsInput{Page: 2, PageSize: 2, SkipCount: true})
+	require.NoError(t, err)
+	assert.Len(t, last, 1)
+	assert.Equal(t, int64(3), page.Total)
+	assert.False(t, page.HasNext(), "no page after the last one")
+
+	counted, page, err := svc.List(ctx, ListProductsInput{Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	assert.Len(t, counted, 2)
+	assert.Equal(t, int64(3), page.Total)
+}
+
+func TestService_List_CreatedRange(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	repo := newFakeProductRepo(
+		domain.Product{ID: uuid.New(), Name: "Old", CreatedAt: day(1)},
+		domain.Product{ID: uuid.New(), Name: "Middle", CreatedAt: day(10)},
+		domain.Product{ID: uuid.New(), Name: "New", CreatedAt: day(20)},
+	)
+	svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), memcache.Memory(memcache.NewMemoryCache(time.Minute, 100)))
+
+	names := func(products []domain.Product) []string {
+		out := make([]string, len(products))
+		for i, p := range products {
+			out[i] = p.Name
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	products, page, err := svc.List(ctx, ListProductsInput{CreatedFrom: day(5), CreatedTo: day(20)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Equal(t, []string{"Middle", "New"}, names(products), "bounds are inclusive")
+
+	products, _, err = svc.List(ctx, ListProductsInput{CreatedTo: day(10)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Middle", "Old"}, names(products), "a different range is not served from the cache")
+
+	products, _, err = svc.List(ctx, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Len(t, products, 3)
+
+	_, _, err = svc.List(ctx, ListProductsInput{CreatedFrom: day(20), CreatedTo: day(1)})
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "validation_failed", appErr.Code)
+}
+
+func TestDescriptionMaxLength(t *testing.T) {
+	limits := config.ProductConfig{MaxDescriptionLength: 20}
+	atLimit := strings.Repeat("é", 20) // 20 runes, 40 bytes
+	overLimit := atLimit + "x"
+	base := CreateProductInput{Name: "Keyboard", Price: 10, Stock: 1, Category: "electronics"}
+
+	t.Run("create at limit counts runes", func(t *testing.T) {
+		input := base
+		input.Description = atLimit
+		assert.NoError(t, validateCreateInput(input, limits))
+	})
+
+	t.Run("create just over limit", func(t *testing.T) {
+		input := base
+		input.Description = overLimit
+		assert.ErrorContains(t, validateCreateInput(input, limits), "must not exceed 20 characters")
+	})
+
+	t.Run("update at limit", func(t *testing.T) {
+		product := &domain.Product{}
+		require.NoError(t, applyUpdate(product, UpdateProductInput{Description: &atLimit}, limits))
+		assert.Equal(t, atLimit, product.Description)
+	})
+
+	t.Run("update just over limit", func(t *testing.T) {
+		product := &domain.Product{Description: "unchanged description"}
+		assert.Error(t, applyUpdate(product, UpdateProductInput{Description: &overLimit}, limits))
+		assert.Equal(t, "unchanged description", product.Description)
+	})
+}
+
+func TestService_ListCategories(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cached", func(t *testing.T) {
+		repo := newFakeProductRepo()
+		// CountByCategory's order, largest first, with the uncategorized products
+		repo.categories = []domain.CategoryCount{{Name: "Garden", Count: 20}, {Name: "Books", Count: 12}, {Name: "", Count: 3}}
+		svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), memcache.Memory(memcache.NewMemoryCache(time.Minute, 10)))
+
+		for i := 0; i < 2; i++ {
+			got, err := svc.ListCategories(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []domain.CategoryCount{{Name: "Books", Count: 12}, {Name: "Garden", Count: 20}}, got)
+		}
+		assert.Equal(t, 1, repo.categoriesCalls)
+	})
+
+	t.Run("none in use", func(t *testing.T) {
+		svc := NewService(newFakeProductRepo(), nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+		got, err := svc.ListCategories(ctx)
+		require.NoError(t, err)
+		assert.NotNil(t, got, "an empty menu is [] rather than null")
+		assert.Empty(t, got)
+	})
+}
+
+func TestService_GetByID_Cache(t *testing.T) {
+	ctx := context.Background()
+	product := domain.Product{
+		ID:          uuid.New(),
+		Name:        "Desk Lamp",
+		Description: "A warm reading lamp",
+		Price:       25,
+		Stock:       4,
+		Images:      []domain.ProductImage{{ID: uuid.New(), URL: "https://res.example.com/lamp.jpg"}},
+	}
+	repo := newFakeProductRepo(product)
+	svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), memcache.Memory(memcache.NewMemoryCache(time.Minute, 10)))
+
+	t.Run("miss falls back to repository", func(t *testing.T) {
+		got, err := svc.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Desk Lamp", got.Name)
+		assert.Len(t, got.Images, 1)
+		assert.Equal(t, 1, repo.getByIDCalls)
+	})
+
+	t.Run("hit skips repository and keeps images", func(t *testing.T) {
+		got, err := svc.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.getByIDCalls)
+		require.Len(t, got.Images, 1)
+		assert.Equal(t, "https://res.example.com/lamp.jpg", got.Images[0].URL)
+
+		// mutating the returned value must not leak into the cache
+		got.Name = "changed"
+		got.Images[0].URL = "changed"
+		again, err := svc.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Desk Lamp", again.Name)
+		assert.Equal(t, "https://res.example.com/lamp.jpg", again.Images[0].URL)
+	})
+
+	t.Run("update invalidates", func(t *testing.T) {
+		name := "Floor Lamp"
+		_, err := svc.Update(ctx, product.ID, UpdateProductInput{Name: &name})
+		require.NoError(t, err)
+		calls := repo.getByIDCalls
+
+		got, err := svc.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Floor Lamp", got.Name)
+		assert.Equal(t, calls+1, repo.getByIDCalls)
+	})
+
+	t.Run("missing product is not cached", func(t *testing.T) {
+		missing := uuid.New()
+		_, err := svc.GetByID(ctx, missing)
+		require.ErrorIs(t, err, domain.ErrProductNotFound)
+		calls := repo.getByIDCalls
+		_, err = svc.GetByID(ctx, missing)
+		require.ErrorIs(t, err, domain.ErrProductNotFound)
+		assert.Equal(t, calls+1, repo.getByIDCalls)
+	})
+}
+
+func TestService_Export_Batches(t *testing.T) {
+	var seeded []domain.Product
+	for i := 0; i < 7; i++ {
+		seeded = append(seeded, domain.Product{ID: uuid.New(), Name: "Item", Price: 1, Stock: 1})
+	}
+	repo := newFakeProductRepo(seeded...)
+	svc := NewService(repo, nil, nil, config.ProductConfig{ExportBatchSize: 3}, clock.Real(), zap.NewNop(), nil)
+
+	var batchSizes []int
+	seen := make(map[uuid.UUID]bool)
+	err := svc.Export(context.Background(), func(batch []domain.Product) error {
+		batchSizes = append(batchSizes, len(batch))
+		for _, p := range batch {
+			assert.False(t, seen[p.ID], "product emitted twice")
+			seen[p.ID] = true
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, seen, len(seeded))
+	assert.Equal(t, []int{3, 3, 1}, batchSizes, "never more than one batch in flight")
+	assert.Equal(t, []int{3, 3, 3}, repo.listAfterLimits)
+}
+
+// failingCache simulates an unreachable cache backend.
+type failingCache struct {
+	calls int
+}
+
+func (c *failingCache) Get(ctx context.Context, key string) (interface{}, bool, error) {
+	c.calls++
+	return nil, false, errors.New("cache unavailable")
+}
+
+func (c *failingCache) Set(ctx context.Context, key string, value interface{}) error {
+	c.calls++
+	return errors.New("cache unavailable")
+}
+
+func (c *failingCache) Delete(ctx context.Context, key string) error {
+	c.calls++
+	return errors.New("cache unavailable")
+}
+
+func (c *failingCache) Flush(ctx context.Context) (int, error) {
+	c.calls++
+	return 0, errors.New("cache unavailable")
+}
+
+func TestService_CacheErrorsFallBackToRepository(t *testing.T) {
+	ctx := context.Background()
+	product := domain.Product{ID: uuid.New(), Name: "Desk Lamp", Description: "A warm reading lamp", Price: 25, Stock: 4}
+	repo := newFakeProductRepo(product)
+	cache := &failingCache{}
+	svc := NewService(repo, nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), cache)
+
+	for i := 1; i <= 2; i++ {
+		got, err := svc.GetByID(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Desk Lamp", got.Name)
+		assert.Equal(t, i, repo.getByIDCalls, "every read goes to the repository")
+	}
+
+	products, page, err := svc.List(ctx, ListProductsInput{})
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, int64(1), page.Total)
+
+	name := "Floor Lamp"
+	_, err = svc.Update(ctx, product.ID, UpdateProductInput{Name: &name})
+	require.NoError(t, err, "a failed invalidation does not fail the write")
+	assert.Positive(t, cache.calls)
+}
+
+func TestService_ContextErrorsAreMapped(t *testing.T) {
+	lamp := domain.Product{ID: uuid.New(), Name: "Desk Lamp"}
+	svc := NewService(newFakeProductRepo(lamp), nil, nil, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := svc.List(cancelled, ListProductsInput{})
+	var appErr *domain.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, domain.StatusClientClosedRequest, appErr.Status)
+	assert.Equal(t, "request was canceled", appErr.Error(), "driver detail must not leak")
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	_, err = svc.GetByID(expired, lamp.ID)
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, http.StatusServiceUnavailable, appErr.Status, "a timeout is not a missing product")
+}
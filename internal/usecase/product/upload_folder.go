@@ -0,0 +1,84 @@
+package product
+
+import (
+	"context"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain/repository"
+)
+
+// UploadFolders derives the Cloudinary folder for a product's images from a base folder, the
+// deployment environment and, optionally, the product's category, e.g. "ecommerce/production/shoes".
+type UploadFolders struct {
+	base        string
+	environment string
+	products    repository.ProductRepository
+	categories  repository.CategoryRepository
+	logger      *zap.Logger
+}
+
+// NewUploadFolders builds a folder resolver. An empty environment is left out of the path, and
+// nil repositories disable the per-category segment.
+func NewUploadFolders(base, environment string, products repository.ProductRepository, categories repository.CategoryRepository, logger *zap.Logger) *UploadFolders {
+	return &UploadFolders{
+		base:        strings.Trim(base, "/"),
+		environment: folderSegment(environment),
+		products:    products,
+		categories:  categories,
+		logger:      logger,
+	}
+}
+
+// For returns the folder for productID's uploads. A category that cannot be resolved only drops
+// its segment; it never fails the upload.
+func (f *UploadFolders) For(ctx context.Context, productID uuid.UUID) string {
+	segments := []string{f.base, f.environment}
+	if f.products != nil && f.categories != nil {
+		segments = append(segments, f.category(ctx, productID))
+	}
+	return path.Join(segments...)
+}
+
+// category names the category row of the product, falling back to the category name stored on the
+// product itself when it is not filed under one, as with product.allowed_categories.
+func (f *UploadFolders) category(ctx context.Context, productID uuid.UUID) string {
+	product, err := f.products.GetByID(ctx, productID)
+	if err != nil {
+		return ""
+	}
+	if product.CategoryId == uuid.Nil {
+		return folderSegment(product.Category)
+	}
+	category, err := f.categories.GetByID(ctx, product.CategoryId)
+	if err != nil {
+		f.logger.Warn("resolve upload folder category failed",
+			zap.String("product_id", productID.String()),
+			zap.Error(err))
+		return ""
+	}
+	return folderSegment(category.Name)
+}
+
+// folderSegment lowercases s and keeps letters and digits, turning every other run of characters
+// into a single hyphen, so "Running Shoes & Boots" becomes "running-shoes-boots".
+func folderSegment(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
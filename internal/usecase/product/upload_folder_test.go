@@ -0,0 +1,64 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/config"
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+func TestUploadFolders_For(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Running Shoes & Boots"}
+	categorized := domain.Product{ID: uuid.New(), Name: "Trail runner", CategoryId: shoes.ID}
+	uncategorized := domain.Product{ID: uuid.New(), Name: "Gift card"}
+	freeText := domain.Product{ID: uuid.New(), Name: "Mug", Category: "Kitchen Ware"}
+	products := newFakeProductRepo(categorized, uncategorized, freeText)
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes}}
+	ctx := context.Background()
+
+	cases := []struct {
+		name    string
+		folders *UploadFolders
+		product uuid.UUID
+		want    string
+	}{
+		{name: "base only", folders: NewUploadFolders("ecommerce", "", nil, nil, zap.NewNop()), product: categorized.ID, want: "ecommerce"},
+		{name: "environment", folders: NewUploadFolders("ecommerce/", "Production", nil, nil, zap.NewNop()), product: categorized.ID, want: "ecommerce/production"},
+		{name: "environment and category", folders: NewUploadFolders("ecommerce", "production", products, categories, zap.NewNop()), product: categorized.ID, want: "ecommerce/production/running-shoes-boots"},
+		{name: "category name only", folders: NewUploadFolders("ecommerce", "production", products, categories, zap.NewNop()), product: freeText.ID, want: "ecommerce/production/kitchen-ware"},
+		{name: "no category", folders: NewUploadFolders("ecommerce", "production", products, categories, zap.NewNop()), product: uncategorized.ID, want: "ecommerce/production"},
+		{name: "unknown product", folders: NewUploadFolders("ecommerce", "", products, categories, zap.NewNop()), product: uuid.New(), want: "ecommerce"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.folders.For(ctx, tc.product))
+		})
+	}
+}
+
+func TestUploadFolders_For_CreatedProduct(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Running Shoes"}
+	products := newFakeProductRepo()
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes}}
+	svc := NewService(products, nil, categories, config.ProductConfig{}, clock.Real(), zap.NewNop(), nil)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, uuid.New(), CreateProductInput{
+		Name:        "Trail runner",
+		Description: "Grippy sole",
+		Price:       120,
+		Stock:       5,
+		Category:    "running shoes",
+	})
+	require.NoError(t, err)
+
+	folders := NewUploadFolders("ecommerce", "production", products, categories, zap.NewNop())
+	assert.Equal(t, "ecommerce/production/running-shoes", folders.For(ctx, created.ID))
+}
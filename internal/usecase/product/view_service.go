@@ -0,0 +1,157 @@
+package product
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// ViewService manages saved product views. Every call is scoped to ownerID; another owner's view
+// is reported as not found.
+type ViewService interface {
+	Create(ctx context.Context, ownerID uuid.UUID, input SaveViewInput) (*domain.ProductView, error)
+	Update(ctx context.Context, ownerID, id uuid.UUID, input SaveViewInput) (*domain.ProductView, error)
+	Delete(ctx context.Context, ownerID, id uuid.UUID) error
+	Get(ctx context.Context, ownerID, id uuid.UUID) (*domain.ProductView, error)
+	List(ctx context.Context, ownerID uuid.UUID) ([]domain.ProductView, error)
+	// Apply fills the fields input leaves empty from the view's filter and returns the view's
+	// category (uuid.Nil for all categories). Page is never taken from the view.
+	Apply(ctx context.Context, ownerID, id uuid.UUID, input ListProductsInput) (ListProductsInput, uuid.UUID, error)
+}
+
+const maxViewNameLength = 100
+
+type viewService struct {
+	views      repository.ProductViewRepository
+	categories repository.CategoryRepository
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+func NewViewService(views repository.ProductViewRepository, categories repository.CategoryRepository, clk clock.Clock, logger *zap.Logger) ViewService {
+	return &viewService{
+		views:      views,
+		categories: categories,
+		logger:     logger,
+		now:        clk.Now,
+	}
+}
+
+func (s *viewService) Create(ctx context.Context, ownerID uuid.UUID, input SaveViewInput) (*domain.ProductView, error) {
+	name, filter, err := s.validate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameFree(ctx, ownerID, name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	view := &domain.ProductView{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		Name:      name,
+		Filter:    filter,
+		CreatedAt: s.now(),
+		UpdatedAt: s.now(),
+	}
+	if err := s.views.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *viewService) Update(ctx context.Context, ownerID, id uuid.UUID, input SaveViewInput) (*domain.ProductView, error) {
+	view, err := s.views.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	name, filter, err := s.validate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameFree(ctx, ownerID, name, id); err != nil {
+		return nil, err
+	}
+
+	view.Name = name
+	view.Filter = filter
+	view.UpdatedAt = s.now()
+	if err := s.views.Update(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *viewService) Delete(ctx context.Context, ownerID, id uuid.UUID) error {
+	return s.views.Delete(ctx, ownerID, id)
+}
+
+func (s *viewService) Get(ctx context.Context, ownerID, id uuid.UUID) (*domain.ProductView, error) {
+	return s.views.GetByID(ctx, ownerID, id)
+}
+
+func (s *viewService) List(ctx context.Context, ownerID uuid.UUID) ([]domain.ProductView, error) {
+	return s.views.ListByOwner(ctx, ownerID)
+}
+
+func (s *viewService) Apply(ctx context.Context, ownerID, id uuid.UUID, input ListProductsInput) (ListProductsInput, uuid.UUID, error) {
+	view, err := s.views.GetByID(ctx, ownerID, id)
+	if err != nil {
+		return input, uuid.Nil, err
+	}
+	s.logger.Debug("applying product view", zap.String("view_id", id.String()), zap.String("owner_id", ownerID.String()))
+	if normalizeSearch(input.Search) == "" {
+		input.Search = view.Filter.Search
+	}
+	if input.Sort == "" {
+		input.Sort = view.Filter.Sort
+	}
+	if input.PageSize <= 0 {
+		input.PageSize = view.Filter.PageSize
+	}
+	return input, view.Filter.CategoryID, nil
+}
+
+func (s *viewService) validate(ctx context.Context, input SaveViewInput) (string, domain.ProductViewFilter, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxViewNameLength {
+		return "", domain.ProductViewFilter{}, domain.NewValidationError("view name must be between 1 and %d characters", maxViewNameLength)
+	}
+	if !repository.ValidSort(input.Sort) {
+		return "", domain.ProductViewFilter{}, domain.NewValidationError("unsupported sort %q", input.Sort)
+	}
+	if input.PageSize < 0 || input.PageSize > 100 {
+		return "", domain.ProductViewFilter{}, domain.NewValidationError("pageSize must be between 1 and 100")
+	}
+	if input.CategoryID != uuid.Nil {
+		if _, err := s.categories.GetByID(ctx, input.CategoryID); err != nil {
+			return "", domain.ProductViewFilter{}, err
+		}
+	}
+	return name, domain.ProductViewFilter{
+		Search:     normalizeSearch(input.Search),
+		CategoryID: input.CategoryID,
+		Sort:       input.Sort,
+		PageSize:   input.PageSize,
+	}, nil
+}
+
+// ensureNameFree rejects a name the owner already uses for a view other than self.
+func (s *viewService) ensureNameFree(ctx context.Context, ownerID uuid.UUID, name string, self uuid.UUID) error {
+	existing, err := s.views.FindByName(ctx, ownerID, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != self {
+		return domain.ErrProductViewExists
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/minilik/ecommerce/internal/domain"
+	"github.com/minilik/ecommerce/internal/domain/repository"
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// fakeViewRepo is an in-memory ProductViewRepository that scopes lookups by owner like the real one.
+type fakeViewRepo struct {
+	views map[uuid.UUID]domain.ProductView
+}
+
+var _ repository.ProductViewRepository = (*fakeViewRepo)(nil)
+
+func newFakeViewRepo() *fakeViewRepo {
+	return &fakeViewRepo{views: map[uuid.UUID]domain.ProductView{}}
+}
+
+func (r *fakeViewRepo) Create(ctx context.Context, view *domain.ProductView) error {
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *fakeViewRepo) Update(ctx context.Context, view *domain.ProductView) error {
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *fakeViewRepo) Delete(ctx context.Context, ownerID, id uuid.UUID) error {
+	if _, err := r.GetByID(ctx, ownerID, id); err != nil {
+		return err
+	}
+	delete(r.views, id)
+	return nil
+}
+
+func (r *fakeViewRepo) GetByID(ctx context.Context, ownerID, id uuid.UUID) (*domain.ProductView, error) {
+	v, ok := r.views[id]
+	if !ok || v.OwnerID != ownerID {
+		return nil, domain.ErrProductViewNotFound
+	}
+	return &v, nil
+}
+
+func (r *fakeViewRepo) FindByName(ctx context.Context, ownerID uuid.UUID, name string) (*domain.ProductView, error) {
+	for _, v := range r.views {
+		if v.OwnerID == ownerID && v.Name == name {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeViewRepo) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.ProductView, error) {
+	var out []domain.ProductView
+	for _, v := range r.views {
+		if v.OwnerID == ownerID {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func TestViewService_Save(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Shoes"}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes}}
+	frozen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewViewService(newFakeViewRepo(), categories, clock.Fixed(frozen), zap.NewNop())
+	ctx := context.Background()
+	owner, other := uuid.New(), uuid.New()
+
+	view, err := svc.Create(ctx, owner, SaveViewInput{Name: " Cheap shoes ", Search: "runner", CategoryID: shoes.ID, Sort: repository.SortPriceAsc, PageSize: 25})
+	require.NoError(t, err)
+	assert.Equal(t, "Cheap shoes", view.Name)
+	assert.Equal(t, domain.ProductViewFilter{Search: "runner", CategoryID: shoes.ID, Sort: repository.SortPriceAsc, PageSize: 25}, view.Filter)
+	assert.Equal(t, frozen, view.CreatedAt)
+
+	_, err = svc.Create(ctx, owner, SaveViewInput{Name: "Cheap shoes"})
+	assert.ErrorIs(t, err, domain.ErrProductViewExists)
+
+	// Names are per owner, and another owner cannot see or change the view.
+	_, err = svc.Create(ctx, other, SaveViewInput{Name: "Cheap shoes"})
+	require.NoError(t, err)
+	_, err = svc.Get(ctx, other, view.ID)
+	assert.ErrorIs(t, err, domain.ErrProductViewNotFound)
+	_, err = svc.Update(ctx, other, view.ID, SaveViewInput{Name: "Mine now"})
+	assert.ErrorIs(t, err, domain.ErrProductViewNotFound)
+	assert.ErrorIs(t, svc.Delete(ctx, other, view.ID), domain.ErrProductViewNotFound)
+
+	views, err := svc.List(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	assert.Equal(t, view.ID, views[0].ID)
+
+	updated, err := svc.Update(ctx, owner, view.ID, SaveViewInput{Name: "Cheap shoes", Sort: repository.SortName})
+	require.NoError(t, err, "keeping its own name is not a conflict")
+	assert.Equal(t, repository.SortName, updated.Filter.Sort)
+	assert.Equal(t, uuid.Nil, updated.Filter.CategoryID)
+
+	for name, input := range map[string]SaveViewInput{
+		"empty name":       {Name: "  "},
+		"unknown sort":     {Name: "x", Sort: "random"},
+		"page size":        {Name: "x", PageSize: 101},
+		"unknown category": {Name: "x", CategoryID: uuid.New()},
+	} {
+		_, err := svc.Create(ctx, owner, input)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestViewService_Apply(t *testing.T) {
+	shoes := domain.Category{ID: uuid.New(), Name: "Shoes"}
+	categories := &fakeCategoryRepo{categories: map[uuid.UUID]domain.Category{shoes.ID: shoes}}
+	svc := NewViewService(newFakeViewRepo(), categories, clock.Real(), zap.NewNop())
+	ctx := context.Background()
+	owner := uuid.New()
+
+	view, err := svc.Create(ctx, owner, SaveViewInput{Name: "Shoes", Search: "runner", CategoryID: shoes.ID, Sort: repository.SortPriceDesc, PageSize: 25})
+	require.NoError(t, err)
+
+	input, categoryID, err := svc.Apply(ctx, owner, view.ID, ListProductsInput{Page: 2})
+	require.NoError(t, err)
+	assert.Equal(t, ListProductsInput{Page: 2, PageSize: 25, Search: "runner", Sort: repository.SortPriceDesc}, input)
+	assert.Equal(t, shoes.ID, categoryID)
+
+	// Explicit request parameters win over the stored filter.
+	input, _, err = svc.Apply(ctx, owner, view.ID, ListProductsInput{Page: 1, PageSize: 5, Search: "boot", Sort: repository.SortName})
+	require.NoError(t, err)
+	assert.Equal(t, ListProductsInput{Page: 1, PageSize: 5, Search: "boot", Sort: repository.SortName}, input)
+
+	_, _, err = svc.Apply(ctx, uuid.New(), view.ID, ListProductsInput{})
+	assert.ErrorIs(t, err, domain.ErrProductViewNotFound)
+}
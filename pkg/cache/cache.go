@@ -0,0 +1,55 @@
+package cache
+
+import "context"
+
+// Cache is the store services cache computed results in. An error means the backend could not be
+// reached, which is different from a miss; callers should log it and fall back to the source of truth
+// so a cache outage never takes the API down with it.
+type Cache interface {
+	Get(ctx context.Context, key string) (value interface{}, found bool, err error)
+	Set(ctx context.Context, key string, value interface{}) error
+	Delete(ctx context.Context, key string) error
+	// Flush drops every entry and returns how many were dropped.
+	Flush(ctx context.Context) (int, error)
+}
+
+// StatsReporter is implemented by caches that can report their usage, like the memory cache.
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// Memory adapts a MemoryCache to Cache. It never returns errors. A nil MemoryCache yields a nil
+// Cache, so "caching disabled" stays a plain nil check for callers.
+func Memory(c *MemoryCache) Cache {
+	if c == nil {
+		return nil
+	}
+	return memoryAdapter{c}
+}
+
+type memoryAdapter struct {
+	c *MemoryCache
+}
+
+func (m memoryAdapter) Get(_ context.Context, key string) (interface{}, bool, error) {
+	v, ok := m.c.Get(key)
+	return v, ok, nil
+}
+
+func (m memoryAdapter) Set(_ context.Context, key string, value interface{}) error {
+	m.c.Set(key, value)
+	return nil
+}
+
+func (m memoryAdapter) Delete(_ context.Context, key string) error {
+	m.c.Delete(key)
+	return nil
+}
+
+func (m memoryAdapter) Flush(_ context.Context) (int, error) {
+	return m.c.Flush(), nil
+}
+
+func (m memoryAdapter) Stats() Stats {
+	return m.c.Stats()
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +16,25 @@ type MemoryCache struct {
 	items map[string]entry
 	ttl   time.Duration
 	max   int
+
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	rejected atomic.Uint64
+
+	softLimit int
+	onSoft    func(Stats)
+	warned    bool // guarded by mu; re-armed once the size drops below softLimit
+}
+
+// Stats is a point-in-time view of a MemoryCache. Rejected counts inserts dropped because the
+// cache was full of live entries.
+type Stats struct {
+	Size      int    `json:"size"`
+	Max       int    `json:"max"`
+	SoftLimit int    `json:"softLimit"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Rejected  uint64 `json:"rejected"`
 }
 
 func NewMemoryCache(ttl time.Duration, max int) *MemoryCache {
@@ -28,20 +48,41 @@ func NewMemoryCache(ttl time.Duration, max int) *MemoryCache {
 	}
 }
 
+// WithSoftLimit calls warn once when the cache fills to ratio of its capacity, and again only after
+// it has dropped back below that level. warn runs outside the cache lock. A ratio <= 0 disables it.
+// Configure before the cache is shared.
+func (c *MemoryCache) WithSoftLimit(ratio float64, warn func(Stats)) *MemoryCache {
+	if ratio <= 0 || warn == nil {
+		return c
+	}
+	c.softLimit = int(ratio * float64(c.max))
+	if c.softLimit < 1 {
+		c.softLimit = 1
+	}
+	c.onSoft = warn
+	return c
+}
+
 func (c *MemoryCache) Get(key string) (interface{}, bool) {
 	c.mu.RLock()
-	defer c.mu.RUnlock()
 	e, ok := c.items[key]
-	if !ok {
-		return nil, false
-	}
-	if time.Now().After(e.expiration) {
+	c.mu.RUnlock()
+	if !ok || time.Now().After(e.expiration) {
+		c.misses.Add(1)
 		return nil, false
 	}
+	c.hits.Add(1)
 	return e.value, true
 }
 
 func (c *MemoryCache) Set(key string, value interface{}) {
+	if st, crossed := c.set(key, value); crossed {
+		c.onSoft(st)
+	}
+}
+
+// set stores the entry and reports whether this insert crossed the soft limit.
+func (c *MemoryCache) set(key string, value interface{}) (Stats, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if len(c.items) >= c.max {
@@ -54,11 +95,66 @@ func (c *MemoryCache) Set(key string, value interface{}) {
 		}
 		// if still full, skip insert
 		if len(c.items) >= c.max {
-			return
+			c.rejected.Add(1)
+			return Stats{}, false
 		}
 	}
 	c.items[key] = entry{
 		value:      value,
 		expiration: time.Now().Add(c.ttl),
 	}
+	if c.softLimit == 0 {
+		return Stats{}, false
+	}
+	if len(c.items) < c.softLimit {
+		c.warned = false
+		return Stats{}, false
+	}
+	if c.warned {
+		return Stats{}, false
+	}
+	c.warned = true
+	return c.statsLocked(), true
+}
+
+// Delete removes key from the cache; missing keys are ignored.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Flush removes every entry from the cache and returns how many it held.
+func (c *MemoryCache) Flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.items)
+	c.items = make(map[string]entry, c.max)
+	c.warned = false
+	return n
+}
+
+// Len reports the number of entries held, including expired ones not yet evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// Stats reports the current size and the hit, miss and rejection counters since creation.
+func (c *MemoryCache) Stats() Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.statsLocked()
+}
+
+func (c *MemoryCache) statsLocked() Stats {
+	return Stats{
+		Size:      len(c.items),
+		Max:       c.max,
+		SoftLimit: c.softLimit,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Rejected:  c.rejected.Load(),
+	}
 }
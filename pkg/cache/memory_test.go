@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache_StatsCountHitsAndMisses(t *testing.T) {
+	c := NewMemoryCache(time.Minute, 10)
+	c.Set("a", 1)
+
+	c.Get("a")
+	c.Get("a")
+	c.Get("missing")
+
+	st := c.Stats()
+	assert.Equal(t, uint64(2), st.Hits)
+	assert.Equal(t, uint64(1), st.Misses)
+	assert.Equal(t, 1, st.Size)
+	assert.Equal(t, 10, st.Max)
+
+	expired := NewMemoryCache(-time.Second, 10)
+	expired.Set("a", 1)
+	_, ok := expired.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), expired.Stats().Misses, "an expired entry counts as a miss")
+}
+
+func TestMemoryCache_RejectedWhenFull(t *testing.T) {
+	c := NewMemoryCache(time.Minute, 2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+
+	st := c.Stats()
+	assert.Equal(t, 2, st.Size)
+	assert.Equal(t, uint64(1), st.Rejected)
+}
+
+func TestMemoryCache_SoftLimitWarnsOncePerCrossing(t *testing.T) {
+	var warnings []Stats
+	c := NewMemoryCache(time.Minute, 10).WithSoftLimit(0.5, func(s Stats) { warnings = append(warnings, s) })
+
+	for _, k := range []string{"1", "2", "3", "4"} {
+		c.Set(k, k)
+	}
+	assert.Empty(t, warnings)
+
+	c.Set("5", "5")
+	c.Set("6", "6")
+	c.Set("7", "7")
+	if assert.Len(t, warnings, 1, "warns when the limit is reached, not on every insert above it") {
+		assert.Equal(t, 5, warnings[0].Size)
+		assert.Equal(t, 5, warnings[0].SoftLimit)
+	}
+
+	c.Flush()
+	for _, k := range []string{"1", "2", "3", "4", "5"} {
+		c.Set(k, k)
+	}
+	assert.Len(t, warnings, 2, "re-armed after draining below the limit")
+}
@@ -0,0 +1,33 @@
+package clock
+
+import "time"
+
+// Clock provides the current time. Services take a Clock so timestamps are consistent
+// across the application and deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+// Real returns a Clock backed by time.Now.
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+type fixedClock struct {
+	t time.Time
+}
+
+// Fixed returns a Clock frozen at t.
+func Fixed(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
@@ -0,0 +1,136 @@
+package cloudinary
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/minilik/ecommerce/pkg/clock"
+)
+
+// ErrCircuitOpen is returned without contacting Cloudinary while the breaker is open.
+var ErrCircuitOpen = errors.New("cloudinary circuit breaker is open")
+
+// BreakerState is where a Breaker is in its closed -> open -> half-open cycle.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every call through and counts consecutive failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails every call with ErrCircuitOpen until the cooldown has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets one probe call through; its outcome closes or reopens the breaker.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// outcome is how a call that the breaker let through ended.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is a call that says nothing about Cloudinary's health, e.g. one the caller
+	// cancelled. It only gives up the half-open probe slot.
+	outcomeIgnored
+)
+
+// Breaker stops calls to Cloudinary during an outage, so uploads fail at once instead of each
+// waiting for the client timeout. After threshold consecutive failures it opens for cooldown, then
+// lets a single probe through: success closes it again, failure reopens it for another cooldown.
+// A nil *Breaker lets everything through.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mutex    sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker opens after threshold consecutive failures and stays open for cooldown. A threshold
+// below 1 returns nil, which disables the breaker.
+func NewBreaker(threshold int, cooldown time.Duration, clk clock.Clock) *Breaker {
+	if threshold < 1 {
+		return nil
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, clock: clk, state: BreakerClosed}
+}
+
+// State reports the current state, moving an open breaker whose cooldown has passed to half-open.
+func (b *Breaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.advance()
+	return b.state
+}
+
+// allow returns ErrCircuitOpen when the call must not go out. Every call it lets through must be
+// followed by exactly one done.
+func (b *Breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.advance()
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *Breaker) done(result outcome) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+		switch result {
+		case outcomeSuccess:
+			b.state, b.failures = BreakerClosed, 0
+		case outcomeFailure:
+			b.open()
+		}
+		return
+	}
+	switch result {
+	case outcomeSuccess:
+		b.failures = 0
+	case outcomeFailure:
+		// calls that were already in flight when the breaker opened do not restart the cooldown
+		if b.state == BreakerClosed {
+			b.failures++
+			if b.failures >= b.threshold {
+				b.open()
+			}
+		}
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = b.clock.Now()
+	b.failures = 0
+}
+
+// advance moves an open breaker to half-open once its cooldown has passed. Callers hold the mutex.
+func (b *Breaker) advance() {
+	if b.state == BreakerOpen && !b.clock.Now().Before(b.openedAt.Add(b.cooldown)) {
+		b.state = BreakerHalfOpen
+		b.probing = false
+	}
+}
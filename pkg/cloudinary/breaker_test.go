@@ -0,0 +1,169 @@
+package cloudinary
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a clock the test moves forward by hand.
+type manualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// outageTransport stands in for Cloudinary: it answers with status, or fails with err when set,
+// and counts the requests that reached it.
+type outageTransport struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (t *outageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if t.err != nil {
+		return nil, t.err
+	}
+	body := `{"error":{"message":"unavailable"}}`
+	if t.status == http.StatusOK {
+		body = `{"secure_url":"https://res.example.com/img.jpg"}`
+	}
+	return &http.Response{StatusCode: t.status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func newBreakerClient(threshold int, cooldown time.Duration) (*Client, *outageTransport, *manualClock) {
+	clk := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	transport := &outageTransport{status: http.StatusServiceUnavailable}
+	c := NewClient("demo", "key", "secret", "", "")
+	c.HTTPClient = &http.Client{Transport: transport}
+	c.Breaker = NewBreaker(threshold, cooldown, clk)
+	return c, transport, clk
+}
+
+func upload(c *Client) error {
+	_, err := c.UploadSigned(context.Background(), strings.NewReader("img"), "a.jpg", "", nil)
+	return err
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	c, transport, _ := newBreakerClient(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		err := upload(c)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen, "attempt %d still reaches Cloudinary", i+1)
+	}
+	assert.Equal(t, BreakerOpen, c.Breaker.State())
+
+	err := upload(c)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, transport.calls, "an open breaker fails fast without calling Cloudinary")
+}
+
+func TestBreaker_SuccessResetsFailureCount(t *testing.T) {
+	c, transport, _ := newBreakerClient(2, time.Minute)
+
+	require.Error(t, upload(c))
+	transport.status = http.StatusOK
+	require.NoError(t, upload(c))
+	transport.status = http.StatusBadGateway
+	require.Error(t, upload(c))
+
+	assert.Equal(t, BreakerClosed, c.Breaker.State(), "failures were not consecutive")
+}
+
+func TestBreaker_IgnoresRejectedUploadsAndCancellations(t *testing.T) {
+	c, transport, _ := newBreakerClient(1, time.Minute)
+
+	transport.status = http.StatusBadRequest
+	require.Error(t, upload(c))
+	assert.Equal(t, BreakerClosed, c.Breaker.State(), "a rejected file says nothing about an outage")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	transport.err = errors.New("connection reset")
+	_, err := c.UploadSigned(ctx, strings.NewReader("img"), "a.jpg", "", nil)
+	require.Error(t, err)
+	assert.Equal(t, BreakerClosed, c.Breaker.State(), "the caller gave up, Cloudinary did not fail")
+
+	_, err = c.UploadSigned(context.Background(), strings.NewReader("img"), "a.jpg", "", nil)
+	require.Error(t, err)
+	assert.Equal(t, BreakerOpen, c.Breaker.State(), "network errors count")
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	t.Run("success closes", func(t *testing.T) {
+		c, transport, clk := newBreakerClient(1, time.Minute)
+		require.Error(t, upload(c))
+		require.Equal(t, BreakerOpen, c.Breaker.State())
+
+		clk.Advance(59 * time.Second)
+		assert.ErrorIs(t, upload(c), ErrCircuitOpen, "still cooling down")
+
+		clk.Advance(time.Second)
+		assert.Equal(t, BreakerHalfOpen, c.Breaker.State())
+		transport.status = http.StatusOK
+		require.NoError(t, upload(c))
+		assert.Equal(t, BreakerClosed, c.Breaker.State())
+		require.NoError(t, upload(c))
+	})
+
+	t.Run("failure reopens for another cooldown", func(t *testing.T) {
+		c, transport, clk := newBreakerClient(1, time.Minute)
+		require.Error(t, upload(c))
+
+		clk.Advance(time.Minute)
+		require.Equal(t, BreakerHalfOpen, c.Breaker.State())
+		err := upload(c)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen, "the probe goes out")
+		assert.Equal(t, BreakerOpen, c.Breaker.State())
+
+		calls := transport.calls
+		clk.Advance(30 * time.Second)
+		assert.ErrorIs(t, upload(c), ErrCircuitOpen)
+		assert.Equal(t, calls, transport.calls)
+	})
+
+	t.Run("one probe at a time", func(t *testing.T) {
+		clk := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+		b := NewBreaker(1, time.Minute, clk)
+		require.NoError(t, b.allow())
+		b.done(outcomeFailure)
+		clk.Advance(time.Minute)
+
+		require.NoError(t, b.allow(), "the probe")
+		assert.ErrorIs(t, b.allow(), ErrCircuitOpen, "others wait for the probe")
+		b.done(outcomeIgnored)
+		assert.NoError(t, b.allow(), "an abandoned probe frees the slot")
+	})
+}
+
+func TestNewBreaker_Disabled(t *testing.T) {
+	b := NewBreaker(0, time.Minute, &manualClock{})
+	assert.Nil(t, b)
+	assert.NoError(t, b.allow())
+	b.done(outcomeFailure)
+	assert.Equal(t, BreakerClosed, b.State())
+}
@@ -0,0 +1,71 @@
+package cloudinary
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// PublicIDFromURL extracts the public id and format from a Cloudinary delivery URL such as
+// https://res.cloudinary.com/demo/image/upload/v1712/ecommerce/shoes/a.jpg ("ecommerce/shoes/a", "jpg").
+// Transformations before the version segment are skipped. ok is false for URLs in any other shape.
+func PublicIDFromURL(rawURL string) (publicID, format string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	_, rest, found := strings.Cut(u.Path, "/upload/")
+	if !found || rest == "" {
+		return "", "", false
+	}
+	segments := strings.Split(rest, "/")
+	for i, s := range segments {
+		if versionSegment.MatchString(s) {
+			segments = segments[i+1:]
+			break
+		}
+	}
+	if len(segments) == 0 {
+		return "", "", false
+	}
+	rest = strings.Join(segments, "/")
+	ext := path.Ext(rest)
+	if ext == "" {
+		return rest, "", true
+	}
+	return strings.TrimSuffix(rest, ext), strings.TrimPrefix(ext, "."), true
+}
+
+// SignedDownloadURL returns a private download URL for publicID that Cloudinary serves only until
+// issuedAt+ttl. The query is signed with the API secret like any other signed API call, so it works
+// for uploaded, private and authenticated assets alike.
+func (c *Client) SignedDownloadURL(publicID, format string, issuedAt time.Time, ttl time.Duration) (string, error) {
+	if c.APIKey == "" || c.APISecret == "" {
+		return "", fmt.Errorf("api key/secret required for signed delivery urls")
+	}
+	if publicID == "" {
+		return "", fmt.Errorf("public id required")
+	}
+	params := map[string]string{
+		"public_id":  publicID,
+		"expires_at": strconv.FormatInt(issuedAt.Add(ttl).Unix(), 10),
+		"timestamp":  strconv.FormatInt(issuedAt.Unix(), 10),
+	}
+	if format != "" {
+		params["format"] = format
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	query.Set("api_key", c.APIKey)
+	query.Set("signature", c.sign(params))
+	return fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/image/download?%s", url.PathEscape(c.CloudName), query.Encode()), nil
+}
@@ -0,0 +1,57 @@
+package cloudinary
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicIDFromURL(t *testing.T) {
+	cases := []struct {
+		url      string
+		publicID string
+		format   string
+		ok       bool
+	}{
+		{url: "https://res.cloudinary.com/demo/image/upload/v1712345678/ecommerce/shoes/a.jpg", publicID: "ecommerce/shoes/a", format: "jpg", ok: true},
+		{url: "https://res.cloudinary.com/demo/image/upload/c_fill,w_200/v1712345678/sample.png", publicID: "sample", format: "png", ok: true},
+		{url: "https://res.cloudinary.com/demo/image/upload/sample", publicID: "sample", ok: true},
+		{url: "https://example.com/images/a.jpg", ok: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			publicID, format, ok := PublicIDFromURL(tc.url)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.publicID, publicID)
+			assert.Equal(t, tc.format, format)
+		})
+	}
+}
+
+func TestClient_SignedDownloadURL(t *testing.T) {
+	c := NewClient("demo", "123456", "abcd", "", "")
+	issuedAt := time.Unix(1700000000, 0)
+
+	raw, err := c.SignedDownloadURL("ecommerce/shoes/a", "jpg", issuedAt, time.Hour)
+	require.NoError(t, err)
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "api.cloudinary.com", u.Host)
+	assert.Equal(t, "/v1_1/demo/image/download", u.Path)
+
+	q := u.Query()
+	assert.Equal(t, "ecommerce/shoes/a", q.Get("public_id"))
+	assert.Equal(t, "jpg", q.Get("format"))
+	assert.Equal(t, "1700003600", q.Get("expires_at"))
+	assert.Equal(t, "1700000000", q.Get("timestamp"))
+	assert.Equal(t, "123456", q.Get("api_key"))
+	// sha1("expires_at=1700003600&format=jpg&public_id=ecommerce/shoes/a&timestamp=1700000000abcd")
+	assert.Equal(t, "f2c43c89bfca8970ca8eabf90a7e404b0d0d65b7", q.Get("signature"))
+
+	_, err = NewClient("demo", "", "", "preset", "").SignedDownloadURL("a", "jpg", issuedAt, time.Hour)
+	assert.Error(t, err, "unsigned-only clients cannot sign")
+}
@@ -0,0 +1,63 @@
+package cloudinary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Destroy deletes an uploaded image by public id. An asset Cloudinary no longer has counts as
+// deleted. Like signed uploads it needs the API key and secret.
+func (c *Client) Destroy(ctx context.Context, publicID string) error {
+	if c.APIKey == "" || c.APISecret == "" {
+		return fmt.Errorf("api key/secret required to delete images")
+	}
+	if publicID == "" {
+		return fmt.Errorf("public id required")
+	}
+	params := map[string]string{
+		"public_id": publicID,
+		"timestamp": strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	form := url.Values{}
+	for k, v := range params {
+		form.Set(k, v)
+	}
+	form.Set("api_key", c.APIKey)
+	form.Set("signature", c.sign(params))
+
+	endpoint := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/image/destroy", url.PathEscape(c.CloudName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudinary destroy network error: %w", err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("cloudinary destroy failed (status %d): %s", resp.StatusCode, string(b))
+	}
+
+	var dr struct {
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(b, &dr); err != nil {
+		return fmt.Errorf("decode cloudinary response: %w", err)
+	}
+	switch dr.Result {
+	case "ok", "not found":
+		return nil
+	}
+	return fmt.Errorf("cloudinary destroy of %q returned %q", publicID, dr.Result)
+}
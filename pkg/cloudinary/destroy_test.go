@@ -0,0 +1,51 @@
+package cloudinary
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestClient_Destroy(t *testing.T) {
+	respond := func(body string, capture *http.Request) *http.Client {
+		return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			*capture = *req
+			require.NoError(t, capture.ParseForm())
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		})}
+	}
+
+	t.Run("signed request", func(t *testing.T) {
+		var req http.Request
+		c := NewClient("demo", "key", "secret", "", "")
+		c.HTTPClient = respond(`{"result":"ok"}`, &req)
+
+		require.NoError(t, c.Destroy(context.Background(), "ecommerce/a"))
+		assert.Equal(t, "/v1_1/demo/image/destroy", req.URL.Path)
+		assert.Equal(t, "ecommerce/a", req.PostForm.Get("public_id"))
+		assert.Equal(t, "key", req.PostForm.Get("api_key"))
+		params := map[string]string{"public_id": "ecommerce/a", "timestamp": req.PostForm.Get("timestamp")}
+		assert.Equal(t, c.sign(params), req.PostForm.Get("signature"))
+	})
+
+	t.Run("missing asset counts as deleted", func(t *testing.T) {
+		var req http.Request
+		c := NewClient("demo", "key", "secret", "", "")
+		c.HTTPClient = respond(`{"result":"not found"}`, &req)
+		assert.NoError(t, c.Destroy(context.Background(), "ecommerce/a"))
+	})
+
+	t.Run("needs credentials", func(t *testing.T) {
+		c := NewClient("demo", "", "", "preset", "")
+		assert.Error(t, c.Destroy(context.Background(), "ecommerce/a"))
+	})
+}
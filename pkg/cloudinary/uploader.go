@@ -24,8 +24,17 @@ type Client struct {
 	UploadPreset string
 	Folder       string
 	HTTPClient   *http.Client
+	// Breaker, when set, fails uploads fast with ErrCircuitOpen while Cloudinary is down.
+	Breaker *Breaker
 }
 
+// Resource types name the Cloudinary upload pipelines: images are processed and transformable,
+// raw files (PDFs, spreadsheets, ...) are stored and delivered as-is.
+const (
+	ResourceImage = "image"
+	ResourceRaw   = "raw"
+)
+
 func NewClient(cloudName, apiKey, apiSecret, uploadPreset, folder string) *Client {
 	// Use a custom transport with longer timeouts and better DNS handling
 	transport := &http.Transport{
@@ -50,8 +59,14 @@ func NewClient(cloudName, apiKey, apiSecret, uploadPreset, folder string) *Clien
 	}
 }
 
-// UploadUnsigned uploads a file using an unsigned upload preset. Returns the secure_url.
-func (c *Client) UploadUnsigned(ctx context.Context, file io.Reader, filename string) (string, error) {
+// UploadUnsigned uploads a file using an unsigned upload preset into folder, or the client's
+// Folder when folder is empty. Returns the secure_url.
+func (c *Client) UploadUnsigned(ctx context.Context, file io.Reader, filename, folder string) (string, error) {
+	return c.UploadUnsignedAs(ctx, ResourceImage, file, filename, folder)
+}
+
+// UploadUnsignedAs is UploadUnsigned for the given resource type (ResourceImage or ResourceRaw).
+func (c *Client) UploadUnsignedAs(ctx context.Context, resourceType string, file io.Reader, filename, folder string) (string, error) {
 	if c.UploadPreset == "" {
 		return "", fmt.Errorf("upload preset required for unsigned upload")
 	}
@@ -67,69 +82,41 @@ func (c *Client) UploadUnsigned(ctx context.Context, file io.Reader, filename st
 	}
 
 	_ = writer.WriteField("upload_preset", c.UploadPreset)
-	if c.Folder != "" {
-		_ = writer.WriteField("folder", c.Folder)
+	if folder = c.folder(folder); folder != "" {
+		_ = writer.WriteField("folder", folder)
 	}
 
 	if err := writer.Close(); err != nil {
 		return "", err
 	}
 
-	endpoint := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/image/upload", url.PathEscape(c.CloudName))
+	endpoint := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/upload", url.PathEscape(c.CloudName), resourceType)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &buf)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		// Provide more context for DNS/network errors
-		if netErr, ok := err.(net.Error); ok {
-			if netErr.Timeout() {
-				return "", fmt.Errorf("cloudinary upload timeout: %w", err)
-			}
-			if dnsErr, ok := netErr.(*net.DNSError); ok {
-				return "", fmt.Errorf("cloudinary DNS resolution failed (check network/Docker DNS): %w", dnsErr)
-			}
-		}
-		return "", fmt.Errorf("cloudinary upload network error: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("cloudinary upload failed (status %d): %s", resp.StatusCode, string(b))
-	}
-
-	type uploadResp struct {
-		SecureURL string `json:"secure_url"`
-		URL       string `json:"url"`
-	}
-	var ur uploadResp
-	b, _ := io.ReadAll(resp.Body)
-	if err := json.Unmarshal(b, &ur); err != nil {
-		return "", fmt.Errorf("decode cloudinary response: %w", err)
-	}
-	if ur.SecureURL != "" {
-		return ur.SecureURL, nil
-	}
-	if ur.URL != "" {
-		return ur.URL, nil
-	}
-	return "", fmt.Errorf("cloudinary response missing url")
+	return c.send(req)
 }
 
 // UploadSigned uploads a file using signed parameters (api_key + signature + timestamp).
 // Signature is computed as sha1 of the concatenated, sorted params and api secret, per Cloudinary spec.
-func (c *Client) UploadSigned(ctx context.Context, file io.Reader, filename string, opts map[string]string) (string, error) {
+// folder works as in UploadUnsigned; a "folder" entry in opts overrides it.
+func (c *Client) UploadSigned(ctx context.Context, file io.Reader, filename, folder string, opts map[string]string) (string, error) {
+	return c.UploadSignedAs(ctx, ResourceImage, file, filename, folder, opts)
+}
+
+// UploadSignedAs is UploadSigned for the given resource type (ResourceImage or ResourceRaw).
+func (c *Client) UploadSignedAs(ctx context.Context, resourceType string, file io.Reader, filename, folder string, opts map[string]string) (string, error) {
 	if c.APIKey == "" || c.APISecret == "" {
 		return "", fmt.Errorf("api key/secret required for signed upload")
 	}
 	// base params
 	params := map[string]string{}
 	// optional folder
-	if c.Folder != "" {
-		params["folder"] = c.Folder
+	if folder = c.folder(folder); folder != "" {
+		params["folder"] = folder
 	}
 	// merge opts
 	for k, v := range opts {
@@ -165,15 +152,30 @@ func (c *Client) UploadSigned(ctx context.Context, file io.Reader, filename stri
 		return "", err
 	}
 
-	endpoint := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/image/upload", url.PathEscape(c.CloudName))
+	endpoint := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/upload", url.PathEscape(c.CloudName), resourceType)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &buf)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
+	return c.send(req)
+}
+
+// send posts an upload request through the breaker and returns the uploaded file's URL. Network
+// errors, 5xx and 429 count as failures; other responses show Cloudinary is up, even if they
+// reject the upload.
+func (c *Client) send(req *http.Request) (string, error) {
+	if err := c.Breaker.allow(); err != nil {
+		return "", err
+	}
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
+		if req.Context().Err() != nil {
+			c.Breaker.done(outcomeIgnored)
+		} else {
+			c.Breaker.done(outcomeFailure)
+		}
 		// Provide more context for DNS/network errors
 		if netErr, ok := err.(net.Error); ok {
 			if netErr.Timeout() {
@@ -186,6 +188,11 @@ func (c *Client) UploadSigned(ctx context.Context, file io.Reader, filename stri
 		return "", fmt.Errorf("cloudinary upload network error: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		c.Breaker.done(outcomeFailure)
+	} else {
+		c.Breaker.done(outcomeSuccess)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
 		return "", fmt.Errorf("cloudinary upload failed (status %d): %s", resp.StatusCode, string(b))
@@ -209,6 +216,14 @@ func (c *Client) UploadSigned(ctx context.Context, file io.Reader, filename stri
 	return "", fmt.Errorf("cloudinary response missing url")
 }
 
+// folder returns the per-call folder, falling back to the client-wide one.
+func (c *Client) folder(folder string) string {
+	if folder != "" {
+		return folder
+	}
+	return c.Folder
+}
+
 // sign computes SHA1 hex signature for provided params using API secret.
 // Build string "key=value&..." sorted by key, then append api_secret, sha1 hex of the result.
 func (c *Client) sign(params map[string]string) string {
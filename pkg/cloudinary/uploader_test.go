@@ -0,0 +1,85 @@
+package cloudinary
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formCapture records the multipart fields of the last upload and answers with a fixed url.
+type formCapture struct {
+	fields map[string]string
+}
+
+func (f *formCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return nil, err
+	}
+	f.fields = make(map[string]string)
+	for k, v := range req.MultipartForm.Value {
+		f.fields[k] = v[0]
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"secure_url":"https://res.example.com/img.jpg"}`)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestClient_UploadFolder(t *testing.T) {
+	cases := []struct {
+		name   string
+		folder string
+		want   string
+	}{
+		{name: "per-call folder", folder: "ecommerce/production/shoes", want: "ecommerce/production/shoes"},
+		{name: "falls back to client folder", folder: "", want: "ecommerce"},
+	}
+	for _, tc := range cases {
+		t.Run("signed "+tc.name, func(t *testing.T) {
+			capture := &formCapture{}
+			c := NewClient("demo", "key", "secret", "", "ecommerce")
+			c.HTTPClient = &http.Client{Transport: capture}
+
+			_, err := c.UploadSigned(context.Background(), strings.NewReader("img"), "a.jpg", tc.folder, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, capture.fields["folder"])
+
+			params := map[string]string{"folder": tc.want, "timestamp": capture.fields["timestamp"]}
+			assert.Equal(t, c.sign(params), capture.fields["signature"], "folder is part of the signature")
+		})
+		t.Run("unsigned "+tc.name, func(t *testing.T) {
+			capture := &formCapture{}
+			c := NewClient("demo", "", "", "preset", "ecommerce")
+			c.HTTPClient = &http.Client{Transport: capture}
+
+			_, err := c.UploadUnsigned(context.Background(), strings.NewReader("img"), "a.jpg", tc.folder)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, capture.fields["folder"])
+			assert.Equal(t, "preset", capture.fields["upload_preset"])
+		})
+	}
+}
+
+func TestClient_UploadAs_RawEndpoint(t *testing.T) {
+	var paths []string
+	c := NewClient("demo", "key", "secret", "preset", "")
+	c.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return (&formCapture{}).RoundTrip(req)
+	})}
+
+	_, err := c.UploadSignedAs(context.Background(), ResourceRaw, strings.NewReader("%PDF-1.7"), "manual.pdf", "", nil)
+	require.NoError(t, err)
+	_, err = c.UploadUnsignedAs(context.Background(), ResourceRaw, strings.NewReader("%PDF-1.7"), "manual.pdf", "")
+	require.NoError(t, err)
+	_, err = c.UploadSigned(context.Background(), strings.NewReader("img"), "a.jpg", "", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/v1_1/demo/raw/upload", "/v1_1/demo/raw/upload", "/v1_1/demo/image/upload"}, paths)
+}
@@ -0,0 +1,62 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or were not signed by this server.
+// Handlers should answer it with 400 Bad Request.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Signer encodes pagination cursors as opaque tokens carrying an HMAC-SHA256 signature,
+// so clients can pass them back but cannot edit them to scan arbitrary ranges.
+type Signer interface {
+	Encode(payload interface{}) (string, error)
+	Decode(token string, out interface{}) error
+}
+
+type hmacSigner struct {
+	secret []byte
+}
+
+// NewSigner returns a cursor signer keyed with the given server secret.
+func NewSigner(secret string) Signer {
+	return &hmacSigner{secret: []byte(secret)}
+}
+
+// Encode serializes payload as JSON and returns base64url(mac || payload).
+func (s *hmacSigner) Encode(payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	token := append(s.sign(body), body...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Decode verifies the token signature and unmarshals its payload into out.
+func (s *hmacSigner) Decode(token string, out interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= sha256.Size {
+		return ErrInvalidCursor
+	}
+	mac, body := raw[:sha256.Size], raw[sha256.Size:]
+	if !hmac.Equal(mac, s.sign(body)) {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (s *hmacSigner) sign(body []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(body)
+	return h.Sum(nil)
+}
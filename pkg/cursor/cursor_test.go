@@ -0,0 +1,44 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pagePosition struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	signer := NewSigner("server-secret")
+	in := pagePosition{CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), ID: "p-42"}
+
+	token, err := signer.Encode(in)
+	require.NoError(t, err)
+	assert.NotContains(t, token, "p-42", "cursor stays opaque")
+
+	var out pagePosition
+	require.NoError(t, signer.Decode(token, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestSigner_RejectsTampered(t *testing.T) {
+	signer := NewSigner("server-secret")
+	token, err := signer.Encode(pagePosition{ID: "p-42"})
+	require.NoError(t, err)
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	raw[len(raw)-3] ^= 0x01 // flip a bit inside the payload
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	var out pagePosition
+	assert.ErrorIs(t, signer.Decode(tampered, &out), ErrInvalidCursor)
+	assert.ErrorIs(t, signer.Decode("not-base64!", &out), ErrInvalidCursor)
+	assert.ErrorIs(t, NewSigner("other-secret").Decode(token, &out), ErrInvalidCursor, "foreign secret")
+}
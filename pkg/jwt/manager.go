@@ -13,6 +13,12 @@ const (
 	userIDClaimKey   = "uid"
 	usernameClaimKey = "uname"
 	roleClaimKey     = "role"
+	emailClaimKey    = "email"
+	// purposeClaimKey marks single-purpose tokens so they are never accepted as access tokens.
+	purposeClaimKey = "purpose"
+
+	purposeEmailVerification = "email_verification"
+	purposePasswordReset     = "password_reset"
 )
 
 // Claims represents the JWT claims used by the application.
@@ -23,24 +29,51 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// EmailVerificationClaims is the content of an email verification token.
+type EmailVerificationClaims struct {
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+}
+
+// PasswordResetClaims is the content of a password reset token. ResetID names the stored reset
+// record, which is what makes the token single-use.
+type PasswordResetClaims struct {
+	UserID    uuid.UUID
+	ResetID   uuid.UUID
+	ExpiresAt time.Time
+}
+
 // Manager defines operations for generating and validating JWT tokens.
 type Manager interface {
 	GenerateAccessToken(userID uuid.UUID, username, role string, ttl time.Duration, issuer string) (string, error)
 	ParseToken(tokenString string) (*Claims, error)
+	// GenerateEmailVerificationToken signs a token proving control of email for userID.
+	GenerateEmailVerificationToken(userID uuid.UUID, email string, ttl time.Duration, issuer string) (string, error)
+	// ParseEmailVerificationToken accepts only tokens from GenerateEmailVerificationToken.
+	ParseEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error)
+	// GeneratePasswordResetToken signs a token redeemable once for the reset record resetID.
+	GeneratePasswordResetToken(userID, resetID uuid.UUID, ttl time.Duration, issuer string) (string, error)
+	// ParsePasswordResetToken accepts only tokens from GeneratePasswordResetToken.
+	ParsePasswordResetToken(tokenString string) (*PasswordResetClaims, error)
 }
 
 type manager struct {
-	secret []byte
+	secret   []byte
+	audience string
 }
 
-// NewManager creates a new JWT manager with the provided secret.
-func NewManager(secret string) (Manager, error) {
+// NewManager creates a new JWT manager with the provided secret. A non-empty audience is written
+// to the aud claim of every token and required when parsing, so a token minted by another service
+// sharing the secret is rejected; an empty audience neither sets nor checks it.
+func NewManager(secret, audience string) (Manager, error) {
 	if secret == "" {
 		return nil, errors.New("jwt secret cannot be empty")
 	}
 
 	return &manager{
-		secret: []byte(secret),
+		secret:   []byte(secret),
+		audience: audience,
 	}, nil
 }
 
@@ -55,6 +88,37 @@ func (m *manager) GenerateAccessToken(userID uuid.UUID, username, role string, t
 		"exp":            now.Add(ttl).Unix(),
 	}
 
+	return m.sign(claims)
+}
+
+func (m *manager) GenerateEmailVerificationToken(userID uuid.UUID, email string, ttl time.Duration, issuer string) (string, error) {
+	now := time.Now()
+	return m.sign(jwt.MapClaims{
+		userIDClaimKey:  userID.String(),
+		emailClaimKey:   email,
+		purposeClaimKey: purposeEmailVerification,
+		"iss":           issuer,
+		"iat":           now.Unix(),
+		"exp":           now.Add(ttl).Unix(),
+	})
+}
+
+func (m *manager) GeneratePasswordResetToken(userID, resetID uuid.UUID, ttl time.Duration, issuer string) (string, error) {
+	now := time.Now()
+	return m.sign(jwt.MapClaims{
+		userIDClaimKey:  userID.String(),
+		purposeClaimKey: purposePasswordReset,
+		"jti":           resetID.String(),
+		"iss":           issuer,
+		"iat":           now.Unix(),
+		"exp":           now.Add(ttl).Unix(),
+	})
+}
+
+func (m *manager) sign(claims jwt.MapClaims) (string, error) {
+	if m.audience != "" {
+		claims["aud"] = m.audience
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	str, err := token.SignedString(m.secret)
 	if err != nil {
@@ -64,31 +128,12 @@ func (m *manager) GenerateAccessToken(userID uuid.UUID, username, role string, t
 }
 
 func (m *manager) ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		}
-		return m.secret, nil
-	})
+	mapClaims, userID, err := m.parse(tokenString)
 	if err != nil {
-		return nil, fmt.Errorf("parse token: %w", err)
+		return nil, err
 	}
-	if !token.Valid {
-		return nil, errors.New("invalid token")
-	}
-
-	mapClaims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
-	}
-
-	userIDStr, ok := mapClaims[userIDClaimKey].(string)
-	if !ok {
-		return nil, errors.New("user id claim missing")
-	}
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user id claim: %w", err)
+	if _, ok := mapClaims[purposeClaimKey]; ok {
+		return nil, errors.New("not an access token")
 	}
 
 	username, _ := mapClaims[usernameClaimKey].(string)
@@ -112,3 +157,79 @@ func (m *manager) ParseToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+func (m *manager) ParseEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	mapClaims, userID, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if purpose, _ := mapClaims[purposeClaimKey].(string); purpose != purposeEmailVerification {
+		return nil, errors.New("not an email verification token")
+	}
+	email, _ := mapClaims[emailClaimKey].(string)
+	if email == "" {
+		return nil, errors.New("email claim missing")
+	}
+
+	claims := &EmailVerificationClaims{UserID: userID, Email: email}
+	if exp, ok := mapClaims["exp"].(float64); ok {
+		claims.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return claims, nil
+}
+
+func (m *manager) ParsePasswordResetToken(tokenString string) (*PasswordResetClaims, error) {
+	mapClaims, userID, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if purpose, _ := mapClaims[purposeClaimKey].(string); purpose != purposePasswordReset {
+		return nil, errors.New("not a password reset token")
+	}
+	jti, _ := mapClaims["jti"].(string)
+	resetID, err := uuid.Parse(jti)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reset id claim: %w", err)
+	}
+
+	claims := &PasswordResetClaims{UserID: userID, ResetID: resetID}
+	if exp, ok := mapClaims["exp"].(float64); ok {
+		claims.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	return claims, nil
+}
+
+// parse verifies the signature and expiry and returns the claims with the user id claim decoded.
+func (m *manager) parse(tokenString string) (jwt.MapClaims, uuid.UUID, error) {
+	var opts []jwt.ParserOption
+	if m.audience != "" {
+		opts = append(opts, jwt.WithAudience(m.audience))
+	}
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return m.secret, nil
+	}, opts...)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, uuid.Nil, errors.New("invalid token")
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, uuid.Nil, errors.New("invalid token claims")
+	}
+
+	userIDStr, ok := mapClaims[userIDClaimKey].(string)
+	if !ok {
+		return nil, uuid.Nil, errors.New("user id claim missing")
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("invalid user id claim: %w", err)
+	}
+	return mapClaims, userID, nil
+}
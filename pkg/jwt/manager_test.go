@@ -0,0 +1,41 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Audience(t *testing.T) {
+	api, err := NewManager("shared-secret", "ecommerce-api")
+	require.NoError(t, err)
+	sibling, err := NewManager("shared-secret", "billing-api")
+	require.NoError(t, err)
+	userID := uuid.New()
+
+	token, err := api.GenerateAccessToken(userID, "jane", "user", time.Minute, "ecommerce-api")
+	require.NoError(t, err)
+
+	claims, err := api.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+
+	_, err = sibling.ParseToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	unscoped, err := NewManager("shared-secret", "")
+	require.NoError(t, err)
+	legacy, err := unscoped.GenerateAccessToken(userID, "jane", "user", time.Minute, "ecommerce-api")
+	require.NoError(t, err)
+	_, err = api.ParseToken(legacy)
+	assert.ErrorIs(t, err, jwt.ErrTokenRequiredClaimMissing, "a token without aud is rejected once an audience is configured")
+
+	verification, err := sibling.GenerateEmailVerificationToken(userID, "jane@example.com", time.Minute, "billing-api")
+	require.NoError(t, err)
+	_, err = api.ParseEmailVerificationToken(verification)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience, "single-purpose tokens are scoped too")
+}
@@ -9,34 +9,85 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// Options overrides the environment-based logger defaults. Empty fields keep the defaults.
+type Options struct {
+	Level  string // debug, info, warn, error, ...
+	Format string // json or console
+	// SamplingInitial and SamplingThereafter configure production sampling; zero initial keeps zap's default.
+	SamplingInitial    int
+	SamplingThereafter int
+}
+
 // New creates a zap logger based on the provided environment name ( production or development).
-func New(environment string) (*zap.Logger, error) {
+func New(environment string, opts Options) (*zap.Logger, error) {
+	var (
+		config    zap.Config
+		buildOpts []zap.Option
+	)
 	switch strings.ToLower(environment) {
 	case "production", "prod":
-		return newProductionLogger()
+		config = productionConfig()
+		if config.Sampling != nil {
+			initial, thereafter := config.Sampling.Initial, config.Sampling.Thereafter
+			if opts.SamplingInitial > 0 {
+				initial, thereafter = opts.SamplingInitial, opts.SamplingThereafter
+			}
+			// replace zap's built-in sampler, which would also drop error entries
+			config.Sampling = nil
+			buildOpts = append(buildOpts, withSampling(initial, thereafter))
+		}
 	default:
-		return newDevelopmentLogger()
+		config = developmentConfig()
+	}
+
+	if err := applyOptions(&config, opts); err != nil {
+		return nil, err
+	}
+
+	logger, err := config.Build(buildOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build logger: %w", err)
 	}
+
+	return logger, nil
 }
 
-func newDevelopmentLogger() (*zap.Logger, error) {
+func developmentConfig() zap.Config {
 	config := zap.NewDevelopmentConfig()
 	config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	return config.Build()
+	return config
 }
 
-func newProductionLogger() (*zap.Logger, error) {
+func productionConfig() zap.Config {
 	config := zap.NewProductionConfig()
 	config.Encoding = "json"
 	config.OutputPaths = []string{"stdout"}
 	config.ErrorOutputPaths = []string{"stderr"}
+	return config
+}
 
-	logger, err := config.Build()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build production logger: %w", err)
+func applyOptions(config *zap.Config, opts Options) error {
+	if level := strings.TrimSpace(opts.Level); level != "" {
+		parsed, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+		config.Level = zap.NewAtomicLevelAt(parsed)
 	}
 
-	return logger, nil
+	switch strings.ToLower(strings.TrimSpace(opts.Format)) {
+	case "":
+	case "json":
+		config.Encoding = "json"
+		config.EncoderConfig = zap.NewProductionEncoderConfig()
+	case "console", "text", "plain":
+		config.Encoding = "console"
+		config.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return fmt.Errorf("invalid log format %q: expected json or console", opts.Format)
+	}
+	return nil
 }
 
 // WithFields returns a new logger with the provided key-value pairs - customize zap logger fields for each request
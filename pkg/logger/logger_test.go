@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNew_Level(t *testing.T) {
+	t.Run("production default disables debug", func(t *testing.T) {
+		log, err := New("production", Options{})
+		require.NoError(t, err)
+		assert.False(t, log.Core().Enabled(zapcore.DebugLevel))
+	})
+
+	t.Run("debug override enables debug in production", func(t *testing.T) {
+		log, err := New("production", Options{Level: "debug", Format: "console"})
+		require.NoError(t, err)
+		assert.True(t, log.Core().Enabled(zapcore.DebugLevel))
+	})
+
+	t.Run("warn override disables info in development", func(t *testing.T) {
+		log, err := New("development", Options{Level: "warn", Format: "json"})
+		require.NoError(t, err)
+		assert.False(t, log.Core().Enabled(zapcore.InfoLevel))
+		assert.True(t, log.Core().Enabled(zapcore.WarnLevel))
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := New("development", Options{Level: "loud"})
+		assert.Error(t, err)
+		_, err = New("development", Options{Format: "xml"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// samplingTick is the window in which identical entries are counted by the sampler.
+const samplingTick = time.Second
+
+// withSampling samples repeated entries below error level. The first `initial` identical
+// entries per tick are logged, then every `thereafter`-th one (0 drops the rest).
+// Error and above bypass the sampler so they are never dropped.
+func withSampling(initial, thereafter int) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newSampledCore(core, initial, thereafter)
+	})
+}
+
+func newSampledCore(core zapcore.Core, initial, thereafter int) zapcore.Core {
+	belowError := &levelRangeCore{Core: core, enabled: func(l zapcore.Level) bool { return l < zapcore.ErrorLevel }}
+	errorAndAbove := &levelRangeCore{Core: core, enabled: func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel }}
+	return zapcore.NewTee(
+		zapcore.NewSamplerWithOptions(belowError, samplingTick, initial, thereafter),
+		errorAndAbove,
+	)
+}
+
+// levelRangeCore restricts the wrapped core to the levels accepted by enabled.
+type levelRangeCore struct {
+	zapcore.Core
+	enabled func(zapcore.Level) bool
+}
+
+func (c *levelRangeCore) Enabled(l zapcore.Level) bool {
+	return c.enabled(l) && c.Core.Enabled(l)
+}
+
+func (c *levelRangeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelRangeCore{Core: c.Core.With(fields), enabled: c.enabled}
+}
+
+func (c *levelRangeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
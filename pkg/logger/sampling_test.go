@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithSampling(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core, withSampling(2, 0))
+
+	for i := 0; i < 10; i++ {
+		log.Info("repeated info")
+	}
+	for i := 0; i < 10; i++ {
+		log.Error("repeated error")
+	}
+
+	assert.Equal(t, 2, logs.FilterMessage("repeated info").Len(), "info entries beyond initial are suppressed")
+	assert.Equal(t, 10, logs.FilterMessage("repeated error").Len(), "error entries are never sampled")
+}
+
+func TestWithSampling_Thereafter(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core, withSampling(1, 3)).With(zap.String("component", "test"))
+
+	for i := 0; i < 10; i++ {
+		log.Warn("repeated warn")
+	}
+
+	// first entry, then every 3rd of the remaining 9
+	assert.Equal(t, 4, logs.FilterMessage("repeated warn").Len())
+}
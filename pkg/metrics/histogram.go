@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// UploadSizeBuckets are upper bounds in bytes suited to image upload requests (64KB to 64MB).
+var UploadSizeBuckets = []float64{64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20}
+
+// Histogram counts observations into cumulative upper-bound buckets, kept separately per label.
+// It is safe for concurrent use.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	counts []uint64 // counts[i] holds observations <= buckets[i]; the last slot is +Inf
+	sum    float64
+}
+
+// Sample is a point-in-time view of one labeled series. Buckets maps each upper bound ("+Inf"
+// included) to the cumulative number of observations at or below it.
+type Sample struct {
+	Count   uint64            `json:"count"`
+	Sum     float64           `json:"sum"`
+	Buckets map[string]uint64 `json:"buckets"`
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds.
+func NewHistogram(buckets []float64) *Histogram {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Histogram{buckets: b, series: make(map[string]*series)}
+}
+
+// Observe records v under label.
+func (h *Histogram) Observe(label string, v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[label]
+	if !ok {
+		s = &series{counts: make([]uint64, len(h.buckets)+1)}
+		h.series[label] = s
+	}
+	i := sort.SearchFloat64s(h.buckets, v)
+	s.counts[i]++
+	s.sum += v
+}
+
+// Snapshot returns the current samples keyed by label.
+func (h *Histogram) Snapshot() map[string]Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make(map[string]Sample, len(h.series))
+	for label, s := range h.series {
+		sample := Sample{Sum: s.sum, Buckets: make(map[string]uint64, len(s.counts))}
+		var cumulative uint64
+		for i, n := range s.counts {
+			cumulative += n
+			bound := math.Inf(1)
+			if i < len(h.buckets) {
+				bound = h.buckets[i]
+			}
+			sample.Buckets[formatBound(bound)] = cumulative
+		}
+		sample.Count = cumulative
+		out[label] = sample
+	}
+	return out
+}
+
+func formatBound(b float64) string {
+	if math.IsInf(b, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(b, 'f', -1, 64)
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_Observe(t *testing.T) {
+	h := NewHistogram([]float64{100, 10})
+
+	var wg sync.WaitGroup
+	for _, v := range []float64{5, 10, 50, 500} {
+		wg.Add(1)
+		go func(v float64) {
+			defer wg.Done()
+			h.Observe("/upload", v)
+		}(v)
+	}
+	wg.Wait()
+	h.Observe("/other", 1)
+
+	snap := h.Snapshot()
+	require.Len(t, snap, 2)
+	upload := snap["/upload"]
+	assert.Equal(t, uint64(4), upload.Count)
+	assert.Equal(t, float64(565), upload.Sum)
+	assert.Equal(t, map[string]uint64{"10": 2, "100": 3, "+Inf": 4}, upload.Buckets)
+	assert.Equal(t, uint64(1), snap["/other"].Count)
+}
@@ -6,22 +6,29 @@ import (
 )
 
 // Base represents the standard API response body.
+// Successful responses always carry "data": endpoints without a payload (delete, promote, ...)
+// use SuccessEmpty so clients get an empty object instead of a missing field.
 type Base struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Code    string      `json:"code,omitempty"`
 	Errors  []string    `json:"errors,omitempty"`
+	// Details carries structured data about an error, e.g. which order lines were short of stock.
+	Details interface{} `json:"details,omitempty"`
 }
 
 // Paginated represents a paginated API response body.
+// TotalPages and TotalProducts are omitted when the total was not counted; HasNext is always set.
 type Paginated struct {
 	Success       bool        `json:"success"`
 	Message       string      `json:"message"`
 	Data          interface{} `json:"data"`
 	CurrentPage   int         `json:"currentPage"`
 	PageSize      int         `json:"pageSize"`
-	TotalPages    int         `json:"totalPages"`
-	TotalProducts int64       `json:"totalProducts"`
+	TotalPages    *int        `json:"totalPages,omitempty"`
+	TotalProducts *int64      `json:"totalProducts,omitempty"`
+	HasNext       bool        `json:"hasNext"`
 	Errors        []string    `json:"errors,omitempty"`
 }
 
@@ -34,6 +41,15 @@ func SuccessBase(message string, object interface{}) Base {
 	}
 }
 
+// SuccessEmpty returns a successful base response whose data is an empty object.
+func SuccessEmpty(message string) Base {
+	return Base{
+		Success: true,
+		Message: message,
+		Data:    struct{}{},
+	}
+}
+
 // ErrorBase returns an error base response.
 // TODO: check the env't and ignore error details if it's in production
 func ErrorBase(message string, errs []string) Base {
@@ -44,6 +60,13 @@ func ErrorBase(message string, errs []string) Base {
 	}
 }
 
+// ErrorCode returns an error base response tagged with a machine-readable code.
+func ErrorCode(code, message string, errs []string) Base {
+	resp := ErrorBase(message, errs)
+	resp.Code = code
+	return resp
+}
+
 // SuccessPaginated returns a successful paginated response.
 func SuccessPaginated(message string, object interface{}, page, size int, total int64) Paginated {
 	totalPages := 0
@@ -60,8 +83,21 @@ func SuccessPaginated(message string, object interface{}, page, size int, total
 		Data:          object,
 		CurrentPage:   page,
 		PageSize:      size,
-		TotalPages:    totalPages,
-		TotalProducts: total,
+		TotalPages:    &totalPages,
+		TotalProducts: &total,
+		HasNext:       page < totalPages,
+	}
+}
+
+// SuccessPage returns a successful paginated response for a list that was not counted.
+func SuccessPage(message string, object interface{}, page, size int, hasNext bool) Paginated {
+	return Paginated{
+		Success:     true,
+		Message:     message,
+		Data:        object,
+		CurrentPage: page,
+		PageSize:    size,
+		HasNext:     hasNext,
 	}
 }
 
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Timestamp formats for config.ServerConfig.TimestampFormat.
+const (
+	// TimestampRFC3339 writes times as RFC 3339 strings with fractional seconds, like encoding/json.
+	TimestampRFC3339 = "rfc3339"
+	// TimestampEpochMillis writes times as milliseconds since the Unix epoch.
+	TimestampEpochMillis = "epoch_millis"
+)
+
+type timestampFormat struct {
+	millis bool
+	loc    *time.Location
+}
+
+// timestamps is the format Time values are written in; nil means rfc3339 in UTC.
+var timestamps atomic.Pointer[timestampFormat]
+
+// UseTimestampFormat sets how Time values are written: format is rfc3339 or epoch_millis, and
+// timeZone, an IANA name (empty means UTC), is the zone rfc3339 times are shown in. It is meant to
+// be called once at startup.
+func UseTimestampFormat(format, timeZone string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = TimestampRFC3339
+	}
+	if format != TimestampRFC3339 && format != TimestampEpochMillis {
+		return fmt.Errorf("invalid timestamp format %q: want %s or %s", format, TimestampRFC3339, TimestampEpochMillis)
+	}
+	loc := time.UTC
+	if name := strings.TrimSpace(timeZone); name != "" {
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+	timestamps.Store(&timestampFormat{millis: format == TimestampEpochMillis, loc: loc})
+	return nil
+}
+
+// Time is a time.Time in a response body. It is written in the format set by UseTimestampFormat;
+// a plain time.Time keeps encoding/json's RFC 3339 in its own zone.
+type Time struct {
+	time.Time
+}
+
+// NewTime wraps t for a response body.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// NewTimePtr wraps t for a response body, keeping nil so it is still written as null.
+func NewTimePtr(t *time.Time) *Time {
+	if t == nil {
+		return nil
+	}
+	return &Time{Time: *t}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time) MarshalJSON() ([]byte, error) {
+	f := timestamps.Load()
+	if f == nil {
+		return t.Time.UTC().MarshalJSON()
+	}
+	if f.millis {
+		return strconv.AppendInt(nil, t.UnixMilli(), 10), nil
+	}
+	return t.In(f.loc).MarshalJSON()
+}
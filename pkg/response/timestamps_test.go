@@ -0,0 +1,83 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stampedRecord struct {
+	Name       string
+	CreatedAt  Time
+	ArchivedAt *Time
+	ExpiresAt  *Time `json:"expiresAt,omitempty"`
+}
+
+func newStampedRecord() stampedRecord {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	archived := time.Date(2024, 6, 2, 8, 30, 0, 0, time.UTC)
+	return stampedRecord{
+		Name:       "Mug <large>",
+		CreatedAt:  NewTime(time.Date(2024, 6, 1, 11, 0, 0, 123000000, berlin)),
+		ArchivedAt: NewTimePtr(&archived),
+	}
+}
+
+// useTimestampFormat sets the format for one test and restores the previous one after it.
+func useTimestampFormat(t *testing.T, format, timeZone string) {
+	t.Helper()
+	previous := timestamps.Load()
+	t.Cleanup(func() { timestamps.Store(previous) })
+	require.NoError(t, UseTimestampFormat(format, timeZone))
+}
+
+func TestTime_DefaultIsRFC3339InUTC(t *testing.T) {
+	useTimestampFormat(t, "", "")
+
+	open := stampedRecord{Name: "Teapot", CreatedAt: NewTime(time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC))}
+	got, err := json.Marshal(SuccessBase("ok", []stampedRecord{newStampedRecord(), open}))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"success":true,"message":"ok","data":[{
+		"Name":"Mug <large>",
+		"CreatedAt":"2024-06-01T09:00:00.123Z",
+		"ArchivedAt":"2024-06-02T08:30:00Z"
+	},{
+		"Name":"Teapot",
+		"CreatedAt":"2024-06-03T00:00:00Z",
+		"ArchivedAt":null
+	}]}`, string(got))
+}
+
+func TestTime_EpochMillis(t *testing.T) {
+	useTimestampFormat(t, TimestampEpochMillis, "Europe/Berlin")
+
+	got, err := json.Marshal(newStampedRecord())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"Name":"Mug <large>","CreatedAt":1717232400123,"ArchivedAt":1717317000000}`, string(got))
+}
+
+func TestTime_TimeZone(t *testing.T) {
+	useTimestampFormat(t, TimestampRFC3339, "America/New_York")
+
+	got, err := json.Marshal(newStampedRecord())
+	require.NoError(t, err)
+
+	assert.Contains(t, string(got), `"CreatedAt":"2024-06-01T05:00:00.123-04:00"`)
+	assert.Contains(t, string(got), `"ArchivedAt":"2024-06-02T04:30:00-04:00"`)
+}
+
+func TestUseTimestampFormat_Invalid(t *testing.T) {
+	useTimestampFormat(t, TimestampEpochMillis, "")
+
+	assert.Error(t, UseTimestampFormat("unix", ""))
+	assert.Error(t, UseTimestampFormat(TimestampRFC3339, "Mars/Olympus_Mons"))
+
+	got, err := json.Marshal(NewTime(time.UnixMilli(1717232400123)))
+	require.NoError(t, err)
+	assert.Equal(t, "1717232400123", string(got), "a rejected format leaves the current one")
+}